@@ -1,6 +1,8 @@
 import time
 import socket
 import json
+import threading
+import uuid
 import azure.functions as func
 import logging
 
@@ -10,8 +12,29 @@ from .exec_func import execute_function
 # Global variable for hostname
 hostname = socket.gethostname()
 
+# Module state lives as long as the worker process, so the first request it serves is a cold start
+instance_id = uuid.uuid4().hex[:16]
+instance_start_us = time.time_ns() // 1000
+instance_served = False
+instance_lock = threading.Lock()
+
+
+def register_request(received_us):
+    global instance_served
+    with instance_lock:
+        cold_start = not instance_served
+        instance_served = True
+
+    return cold_start, received_us - instance_start_us
+
 def main(req: func.HttpRequest) -> func.HttpResponse:
-    logging.info("Processing request.")
+    received_us = time.time_ns() // 1000
+    cold_start, uptime_us = register_request(received_us)
+
+    # Invocation ID and function name forwarded by the loader, echoed back and logged for joining with its records
+    invocation_id = req.headers.get("invocation-id", "")
+    function_name = req.headers.get("function-name", "")
+    logging.info(f"Processing request. invocationID={invocation_id} function={function_name}")
 
     start_time = time.time()
 
@@ -33,7 +56,9 @@ def main(req: func.HttpRequest) -> func.HttpResponse:
     logging.info(f"Runtime requested: {runtime_milliseconds} ms, Memory: {memory_mebibytes} MiB")
 
     # Call the execute_function, which needs to be copied from server/trace-func-py/exec_func.py
+    start_us = time.time_ns() // 1000
     duration = execute_function("",runtime_milliseconds,memory_mebibytes)
+    end_us = time.time_ns() // 1000
     result_msg = f"Workload completed in {duration} microseconds"
 
     # Prepare the response
@@ -44,13 +69,21 @@ def main(req: func.HttpRequest) -> func.HttpResponse:
         "ExecutionTime": int((time.time() - start_time) * 1_000_000),  # Total time (includes HTTP, workload, and response prep)
         "DurationInMicroSec": duration,  # Time spent on the workload itself
         "MemoryUsageInKb": memory_mebibytes * 1024,
-        "Message": result_msg
+        "Message": result_msg,
+        "ReceiveTimestampInMicroSec": received_us,
+        "StartTimestampInMicroSec": start_us,
+        "EndTimestampInMicroSec": end_us,
+        "ColdStart": cold_start,
+        "InstanceUptimeInMicroSec": uptime_us,
+        "InstanceID": instance_id
     }
 
     logging.info(f"Response: {response}")
+    logging.info(f"Invocation served in {duration}[us] (cold start: {cold_start}) invocationID={invocation_id} function={function_name}")
 
     return func.HttpResponse(
         json.dumps(response),
         status_code=200,
-        mimetype="application/json"
+        mimetype="application/json",
+        headers={"invocation-id": invocation_id, "function-name": function_name}
     )
@@ -54,6 +54,7 @@ var (
 	iatGeneration = flag.Bool("iatGeneration", false, "Generate IATs only or run invocations as well")
 	iatFromFile   = flag.Bool("generated", false, "True if iats were already generated")
 	dryRun        = flag.Bool("dryRun", false, "Dry run mode - do not deploy functions or generate invocations")
+	renderDir     = flag.String("renderDeployment", "", "Write the deployment artifacts to the directory instead of deploying functions and generating invocations")
 )
 
 func init() {
@@ -96,14 +97,23 @@ func main() {
 		common.PlatformAWSLambda,
 		common.PlatformDirigent,
 		common.PlatformAzureFunctions,
+		common.PlatformGenericHTTP,
+		common.PlatformOpenFaaS,
+		common.PlatformFission,
+		common.PlatformSimulated,
+		common.PlatformLocal,
 	}
 	if !slices.Contains(supportedPlatforms, cfg.Platform) {
 		log.Fatal("Unsupported platform!")
 	}
 
-	if cfg.Platform == common.PlatformKnative {
+	if cfg.Platform == common.PlatformKnative || cfg.Platform == common.PlatformFission {
 		common.CheckCPULimit(cfg.CPULimit)
 	}
+	common.CheckGRPCConnectionReuse(cfg.GRPCConnectionReuse)
+	common.CheckReadinessPolicy(cfg.ReadinessPolicy)
+	common.CheckOutputFormat(cfg.OutputFormat, cfg.OutputCompression)
+	common.CheckAWSLambdaInvocationType(cfg.AWSLambdaInvocationType)
 
 	run(&cfg, *iatFromFile, *iatGeneration)
 }
@@ -147,7 +157,9 @@ func parseYAMLSpecification(cfg *config.LoaderConfiguration) string {
 	case "firecracker":
 		return "workloads/firecracker/trace_func_go.yaml"
 	default:
-		if cfg.Platform != common.PlatformDirigent && cfg.Platform != common.PlatformAzureFunctions {
+		if cfg.Platform != common.PlatformDirigent && cfg.Platform != common.PlatformAzureFunctions &&
+			cfg.Platform != common.PlatformGenericHTTP && cfg.Platform != common.PlatformSimulated &&
+			cfg.Platform != common.PlatformLocal {
 			log.Fatal("Invalid 'YAMLSelector' parameter.")
 		}
 	}
@@ -248,13 +260,29 @@ func run(cfg *config.LoaderConfiguration, readIATFromFile bool, writeIATsToFile
 		FailureConfiguration:  config.ReadFailureConfiguration(*failurePath),
 		DirigentConfiguration: dirigentConfig,
 
+		GenericHTTPConfiguration: config.ReadGenericHTTPConfig(cfg),
+		OpenFaaSConfiguration:    config.ReadOpenFaaSConfig(cfg),
+		FissionConfiguration:     config.ReadFissionConfig(cfg),
+		DynamicGRPCConfiguration: config.ReadDynamicGRPCConfig(cfg),
+		AutoscalingConfiguration: config.ReadAutoscalingConfig(cfg),
+		SimulatedConfiguration:   config.ReadSimulatedConfig(cfg),
+		LocalConfiguration:       config.ReadLocalConfig(cfg),
+
 		TraceGranularity: parseTraceGranularity(cfg),
 		TestMode:         false,
 
 		TraceDuration: experimentDuration,
 		Functions:     functions,
+
+		ConfigPath:        *configPath,
+		FailureConfigPath: *failurePath,
 	})
 
+	if *renderDir != "" {
+		experimentDriver.RenderDeployment(*renderDir)
+		return
+	}
+
 	// Skip experiments execution during dry run mode
 	if *dryRun {
 		return
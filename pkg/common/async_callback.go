@@ -0,0 +1,48 @@
+package common
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+const asyncCallbackTimeout = 5 * time.Second
+
+// AsyncCallback is the completion notice of an asynchronous invocation, POSTed to the callback receiver of the loader
+// by the function or the platform.
+type AsyncCallback struct {
+	// ID returned when submitting the invocation, set by platforms
+	AsyncResponseID string `json:"AsyncResponseID,omitempty"`
+	// ID of the invocation and name of the function, set by functions, which are unaware of the response ID assigned
+	// by the platform
+	InvocationID string `json:"InvocationID,omitempty"`
+	Function     string `json:"Function,omitempty"`
+	// end-to-end duration of the invocation as measured by the platform, optional
+	EndToEndDurationInMicroSec int64 `json:"EndToEndDurationInMicroSec,omitempty"`
+	// response of the invocation, optional
+	Payload json.RawMessage `json:"Payload,omitempty"`
+}
+
+var asyncCallbackClient = &http.Client{Timeout: asyncCallbackTimeout}
+
+// PostAsyncCallback delivers the callback to the callback receiver at the given URL.
+func PostAsyncCallback(url string, callback *AsyncCallback) error {
+	body, err := json.Marshal(callback)
+	if err != nil {
+		return err
+	}
+
+	resp, err := asyncCallbackClient.Post(url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusAccepted {
+		return fmt.Errorf("callback receiver replied with status code %d", resp.StatusCode)
+	}
+
+	return nil
+}
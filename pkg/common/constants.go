@@ -29,6 +29,15 @@ const (
 	OneSecondInMicroseconds = 1_000_000.0
 )
 
+// Request headers (gRPC metadata keys) identifying an invocation on the function side.
+// Kept lowercase since gRPC metadata keys are case-sensitive and always lowercase.
+const (
+	InvocationIDHeader = "invocation-id"
+	FunctionNameHeader = "function-name"
+	// URL of the callback receiver of the loader, set on asynchronous invocations only
+	AsyncCallbackURLHeader = "async-callback-url"
+)
+
 const (
 	// MinExecTimeMilli 1ms (min. billing unit of AWS)
 	MinExecTimeMilli = 1
@@ -114,8 +123,86 @@ const (
 	PlatformOpenWhisk      string = "openwhisk"
 	PlatformAWSLambda      string = "awslambda"
 	PlatformAzureFunctions string = "azurefunctions"
+	PlatformGenericHTTP    string = "generichttp"
+	PlatformOpenFaaS       string = "openfaas"
+	PlatformFission        string = "fission"
+	PlatformSimulated      string = "simulated"
+	PlatformLocal          string = "local"
+)
+
+// fission executors
+const (
+	FissionExecutorPoolManager string = "poolmgr"
+	FissionExecutorNewDeploy   string = "newdeploy"
+)
+
+var ValidFissionExecutors = []string{FissionExecutorPoolManager, FissionExecutorNewDeploy}
+
+// gRPC connection reuse modes
+const (
+	GRPCConnectionPerInvocation string = "per-invocation"
+	GRPCConnectionPool          string = "pool"
+)
+
+var ValidGRPCConnectionReuseModes = []string{"", GRPCConnectionPerInvocation, GRPCConnectionPool}
+
+// handling of functions that do not become ready before the experiment
+const (
+	ReadinessPolicyAbort string = "abort"
+	ReadinessPolicyDrop  string = "drop"
+)
+
+var ValidReadinessPolicies = []string{"", ReadinessPolicyAbort, ReadinessPolicyDrop}
+
+// invocation types of AWS Lambda functions
+const (
+	AWSLambdaInvocationRequestResponse string = "RequestResponse"
+	AWSLambdaInvocationEvent           string = "Event"
 )
 
+var ValidAWSLambdaInvocationTypes = []string{"", AWSLambdaInvocationRequestResponse, AWSLambdaInvocationEvent}
+
+// formats of the invocation and metric outputs
+const (
+	OutputFormatCSV       string = "csv"
+	OutputFormatParquet   string = "parquet"
+	OutputFormatJSONLines string = "jsonl"
+	OutputFormatSQLite    string = "sqlite"
+)
+
+var ValidOutputFormats = []string{"", OutputFormatCSV, OutputFormatParquet, OutputFormatJSONLines, OutputFormatSQLite}
+
+// compression of the invocation and metric outputs
+const (
+	OutputCompressionGzip string = "gzip"
+	OutputCompressionZstd string = "zstd"
+)
+
+var ValidOutputCompressions = []string{"", OutputCompressionGzip, OutputCompressionZstd}
+
+// policies computing the autoscaling settings of each function from the trace
+const (
+	// scale to zero, keeping instances across the typical idle period of the function
+	AutoscalingPolicyTrace string = "trace"
+	// keep the average concurrency of the function provisioned
+	AutoscalingPolicyProvisioned string = "provisioned"
+	// scale on the request rate an instance can sustain given the function runtime
+	AutoscalingPolicyRPS string = "rps"
+	// only apply the settings of the configuration file
+	AutoscalingPolicyStatic string = "static"
+)
+
+var ValidAutoscalingPolicies = []string{AutoscalingPolicyTrace, AutoscalingPolicyProvisioned, AutoscalingPolicyRPS, AutoscalingPolicyStatic}
+
+// units of the execution time reported by a function
+const (
+	DurationUnitMicroseconds string = "us"
+	DurationUnitMilliseconds string = "ms"
+	DurationUnitSeconds      string = "s"
+)
+
+var ValidDurationUnits = []string{DurationUnitMicroseconds, DurationUnitMilliseconds, DurationUnitSeconds}
+
 // dirigent backend
 const (
 	BackendDandelion string = "dandelion"
@@ -0,0 +1,77 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2023 EASL and the vHive community
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package common
+
+import (
+	"context"
+
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// EchoInvocationMetadata reads the invocation ID and the function name forwarded by the loader,
+// sends them back to the client as gRPC header metadata and returns a log entry carrying both,
+// so that the function logs can be joined with the loader's duration CSV.
+func EchoInvocationMetadata(ctx context.Context) *logrus.Entry {
+	invocationID, functionName, _ := IncomingInvocationMetadata(ctx)
+
+	if invocationID != "" {
+		err := grpc.SetHeader(ctx, metadata.Pairs(
+			InvocationIDHeader, invocationID,
+			FunctionNameHeader, functionName,
+		))
+		if err != nil {
+			logrus.Warnf("Failed to echo invocation metadata - %v", err)
+		}
+	}
+
+	return InvocationLogEntry(invocationID, functionName)
+}
+
+// IncomingInvocationMetadata returns the invocation ID, the function name and, for asynchronous invocations, the URL
+// of the callback receiver forwarded by the loader.
+func IncomingInvocationMetadata(ctx context.Context) (invocationID string, functionName string, callbackURL string) {
+	md, _ := metadata.FromIncomingContext(ctx)
+
+	return firstMetadataValue(md, InvocationIDHeader), firstMetadataValue(md, FunctionNameHeader),
+		firstMetadataValue(md, AsyncCallbackURLHeader)
+}
+
+// InvocationLogEntry returns a log entry tagged with the invocation ID and the function name.
+func InvocationLogEntry(invocationID string, functionName string) *logrus.Entry {
+	return logrus.WithFields(logrus.Fields{
+		"invocationID": invocationID,
+		"function":     functionName,
+	})
+}
+
+func firstMetadataValue(md metadata.MD, key string) string {
+	if values := md.Get(key); len(values) > 0 {
+		return values[0]
+	}
+
+	return ""
+}
@@ -0,0 +1,27 @@
+package common
+
+import (
+	"context"
+	"testing"
+
+	"google.golang.org/grpc/metadata"
+)
+
+func TestEchoInvocationMetadata(t *testing.T) {
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(
+		InvocationIDHeader, "min3.inv17",
+		FunctionNameHeader, "trace-func-0",
+	))
+
+	entry := EchoInvocationMetadata(ctx)
+	if entry.Data["invocationID"] != "min3.inv17" || entry.Data["function"] != "trace-func-0" {
+		t.Errorf("Unexpected log fields: %v", entry.Data)
+	}
+}
+
+func TestEchoInvocationMetadataMissing(t *testing.T) {
+	entry := EchoInvocationMetadata(context.Background())
+	if entry.Data["invocationID"] != "" || entry.Data["function"] != "" {
+		t.Errorf("Expected empty log fields, got %v", entry.Data)
+	}
+}
@@ -38,10 +38,10 @@ func (lfq *LockFreeQueue[T]) Enqueue(item T) {
 	}
 
 	for {
-		last := (*lfqElement[T])(lfq.tail)
-		next := last.next
+		last := (*lfqElement[T])(atomic.LoadPointer(&lfq.tail))
+		next := atomic.LoadPointer(&last.next)
 
-		if last == (*lfqElement[T])(lfq.tail) {
+		if last == (*lfqElement[T])(atomic.LoadPointer(&lfq.tail)) {
 			if next == nil {
 				if atomic.CompareAndSwapPointer(&last.next, next, unsafe.Pointer(node)) {
 					atomic.CompareAndSwapPointer(&lfq.tail, unsafe.Pointer(last), unsafe.Pointer(node))
@@ -59,15 +59,17 @@ func (lfq *LockFreeQueue[T]) Dequeue() T {
 	defer atomic.AddInt32(&lfq.length, -1)
 
 	for {
-		first := (*lfqElement[T])(lfq.head)
-		last := (*lfqElement[T])(lfq.tail)
-		next := first.next
+		first := (*lfqElement[T])(atomic.LoadPointer(&lfq.head))
+		last := (*lfqElement[T])(atomic.LoadPointer(&lfq.tail))
+		next := atomic.LoadPointer(&first.next)
 
-		if first == (*lfqElement[T])(lfq.head) {
+		if first == (*lfqElement[T])(atomic.LoadPointer(&lfq.head)) {
 			if first == last {
 				if next == nil {
 					logrus.Fatal("No element to dequeue.")
 				}
+				// the tail lags behind an element being enqueued
+				atomic.CompareAndSwapPointer(&lfq.tail, unsafe.Pointer(last), next)
 			} else {
 				value := (*lfqElement[T])(next).value
 				if atomic.CompareAndSwapPointer(&lfq.head, unsafe.Pointer(first), next) {
@@ -94,6 +94,21 @@ type DirigentMetadata struct {
 	NumRets int `json:"NumRets"`
 }
 
+// AutoscalingSettings describes how the platform scales a function. Zero values leave the corresponding setting to
+// the deployment template or the platform defaults, except for MinScale.
+type AutoscalingSettings struct {
+	Policy   string
+	MinScale int
+	// whether the policy or an override set MinScale, otherwise Dirigent keeps the scaling lower bound of the function
+	MinScaleSet bool
+	MaxScale    int
+	// concurrency or rps
+	Metric                      string
+	Target                      float64
+	StableWindowSeconds         int
+	ScaleToZeroRetentionSeconds int
+}
+
 type WorkflowMetadata struct {
 	InvocationRequest string
 }
@@ -104,6 +119,8 @@ type Function struct {
 
 	// From the static trace profiler
 	InitialScale int
+	// From the autoscaling policy engine, nil to keep the settings of the deployment template
+	Autoscaling *AutoscalingSettings
 	// From the trace
 	InvocationStats  *FunctionInvocationStats
 	RuntimeStats     *FunctionRuntimeStats
@@ -26,6 +26,7 @@ package common
 
 import (
 	"encoding/json"
+	"fmt"
 	"hash/fnv"
 	"io"
 	"log"
@@ -154,6 +155,12 @@ func GetName(function *Function) int {
 	return functionId
 }
 
+// AWSLambdaFunctionName returns the name of the Lambda function deployed for the function, e.g., trace-func-0 for
+// trace-func-0-2642643831809466437.
+func AWSLambdaFunctionName(function *Function) string {
+	return fmt.Sprintf("%s-%s", FunctionNamePrefix, strings.Split(function.Name, "-")[2])
+}
+
 func DeepCopy[T any](a T) (T, error) {
 	var b T
 	byt, err := json.Marshal(a)
@@ -28,3 +28,33 @@ func CheckCPULimit(cpuLimit string) {
 		log.Fatal("Invalid CPU Limit ", cpuLimit)
 	}
 }
+
+func CheckGRPCConnectionReuse(mode string) {
+	if !slices.Contains(ValidGRPCConnectionReuseModes, mode) {
+		log.Fatal("Invalid gRPC connection reuse mode ", mode)
+	}
+}
+
+func CheckReadinessPolicy(policy string) {
+	if !slices.Contains(ValidReadinessPolicies, policy) {
+		log.Fatal("Invalid readiness policy ", policy)
+	}
+}
+
+func CheckAWSLambdaInvocationType(invocationType string) {
+	if !slices.Contains(ValidAWSLambdaInvocationTypes, invocationType) {
+		log.Fatal("Invalid AWS Lambda invocation type ", invocationType)
+	}
+}
+
+func CheckOutputFormat(format string, compression string) {
+	if !slices.Contains(ValidOutputFormats, format) {
+		log.Fatal("Invalid output format ", format)
+	}
+	if !slices.Contains(ValidOutputCompressions, compression) {
+		log.Fatal("Invalid output compression ", compression)
+	}
+	if format == OutputFormatSQLite && compression != "" {
+		log.Fatal("Compression is not supported for SQLite outputs")
+	}
+}
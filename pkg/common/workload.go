@@ -7,6 +7,9 @@ package common
 // }
 import "C"
 import (
+	"crypto/rand"
+	"encoding/hex"
+	"sync/atomic"
 	"time"
 )
 
@@ -45,3 +48,34 @@ func TraceFunctionExecution(start time.Time, IterationsMultiplier uint32, timeLe
 
 	return msg
 }
+
+// FunctionInstance holds the per-instance state that workload servers report back to the loader,
+// i.e., a random instance ID, when the instance was started and whether it has already served a request.
+type FunctionInstance struct {
+	id        string
+	startTime time.Time
+	served    atomic.Bool
+}
+
+func NewFunctionInstance() *FunctionInstance {
+	id := make([]byte, 8)
+	_, _ = rand.Read(id)
+
+	return &FunctionInstance{
+		id:        hex.EncodeToString(id),
+		startTime: time.Now(),
+	}
+}
+
+// ID identifies the instance, e.g., to count the instances of public cloud functions, whose sandboxes are opaque.
+func (fi *FunctionInstance) ID() string {
+	return fi.id
+}
+
+// RegisterRequest marks a request received at the given time as served by this instance. It returns
+// whether this is the first request of the instance (i.e., a cold start) and the instance uptime in µs.
+func (fi *FunctionInstance) RegisterRequest(received time.Time) (bool, int64) {
+	coldStart := fi.served.CompareAndSwap(false, true)
+
+	return coldStart, received.Sub(fi.startTime).Microseconds()
+}
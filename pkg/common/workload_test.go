@@ -0,0 +1,47 @@
+package common
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestFunctionInstanceColdStart(t *testing.T) {
+	instance := NewFunctionInstance()
+
+	var coldStarts int64
+	wg := sync.WaitGroup{}
+	for range 100 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			if coldStart, _ := instance.RegisterRequest(time.Now()); coldStart {
+				atomic.AddInt64(&coldStarts, 1)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if coldStarts != 1 {
+		t.Errorf("Expected exactly one cold start, got %d", coldStarts)
+	}
+}
+
+func TestFunctionInstanceUptime(t *testing.T) {
+	instance := NewFunctionInstance()
+
+	_, uptime := instance.RegisterRequest(instance.startTime.Add(5 * time.Millisecond))
+	if uptime != 5000 {
+		t.Errorf("Expected uptime of 5000 µs, got %d", uptime)
+	}
+}
+
+func TestFunctionInstanceID(t *testing.T) {
+	first, second := NewFunctionInstance(), NewFunctionInstance()
+
+	if len(first.ID()) != 16 || first.ID() == second.ID() {
+		t.Errorf("Expected distinct 16-character instance IDs, got %s and %s", first.ID(), second.ID())
+	}
+}
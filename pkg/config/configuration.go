@@ -9,12 +9,24 @@ type Configuration struct {
 	FailureConfiguration  *FailureConfiguration
 	DirigentConfiguration *DirigentConfig
 
+	GenericHTTPConfiguration *GenericHTTPConfig
+	OpenFaaSConfiguration    *OpenFaaSConfig
+	FissionConfiguration     *FissionConfig
+	DynamicGRPCConfiguration *DynamicGRPCConfig
+	AutoscalingConfiguration *AutoscalingConfig
+	SimulatedConfiguration   *SimulatedConfig
+	LocalConfiguration       *LocalConfig
+
 	TraceGranularity common.TraceGranularity
 	// TraceDuration In minutes.
 	TraceDuration int
 
 	TestMode bool
 
+	// paths of the loader and failure configuration files, recorded in the run manifest
+	ConfigPath        string
+	FailureConfigPath string
+
 	Functions []*common.Function
 }
 
@@ -27,7 +27,9 @@ package config
 import (
 	"encoding/json"
 	"github.com/vhive-serverless/loader/pkg/common"
+	"net"
 	"os"
+	"slices"
 	"strings"
 
 	log "github.com/sirupsen/logrus"
@@ -39,6 +41,33 @@ type FailureConfiguration struct {
 	FailAt        int    `json:"FailAt"`
 	FailComponent string `json:"FailComponent"`
 	FailNode      string `json:"FailNode"`
+
+	// failure schedule, to which the single failure above is added if set
+	Events []FailureEvent `json:"Events"`
+	// log the failures instead of injecting them
+	DryRun bool `json:"DryRun"`
+}
+
+// FailureEvent is a failure of a component injected into the cluster during the experiment.
+type FailureEvent struct {
+	// time in seconds since the beginning of the experiment
+	At        int      `json:"At"`
+	Component string   `json:"Component"`
+	Nodes     []string `json:"Nodes"`
+	// time the component is kept down, or 0 for an instantaneous failure (e.g., a restart)
+	DurationSeconds int `json:"DurationSeconds"`
+	// action ending the failure once the duration elapsed
+	Recovery string `json:"Recovery"`
+
+	// targets of the Kubernetes failure actions
+	Function   string  `json:"Function"`
+	Percentage float64 `json:"Percentage"`
+	Namespace  string  `json:"Namespace"`
+	Deployment string  `json:"Deployment"`
+
+	// the failure is repeated with the given period, as many times as RepeatCount or until the experiment ends if 0
+	RepeatEverySeconds int `json:"RepeatEverySeconds"`
+	RepeatCount        int `json:"RepeatCount"`
 }
 
 type LoaderConfiguration struct {
@@ -64,22 +93,64 @@ type LoaderConfiguration struct {
 	ExperimentDuration int    `json:"ExperimentDuration"`
 	WarmupDuration     int    `json:"WarmupDuration"`
 
+	// format and compression of the execution, kn_stats, deployment_scale and cluster_usage outputs
+	OutputFormat      string `json:"OutputFormat"`
+	OutputCompression string `json:"OutputCompression"`
+
 	IsPartiallyPanic            bool   `json:"IsPartiallyPanic"`
 	EnableZipkinTracing         bool   `json:"EnableZipkinTracing"`
 	EnableMetricsScrapping      bool   `json:"EnableMetricsScrapping"`
 	MetricScrapingPeriodSeconds int    `json:"MetricScrapingPeriodSeconds"`
 	AutoscalingMetric           string `json:"AutoscalingMetric"`
 
-	GRPCConnectionTimeoutSeconds int  `json:"GRPCConnectionTimeoutSeconds"`
-	GRPCFunctionTimeoutSeconds   int  `json:"GRPCFunctionTimeoutSeconds"`
-	DAGMode                      bool `json:"DAGMode"`
-	EnableDAGDataset             bool `json:"EnableDAGDataset"`
-	Width                        int  `json:"Width"`
-	Depth                        int  `json:"Depth"`
-	VSwarm                       bool `json:"VSwarm"`
+	GRPCConnectionTimeoutSeconds int    `json:"GRPCConnectionTimeoutSeconds"`
+	GRPCFunctionTimeoutSeconds   int    `json:"GRPCFunctionTimeoutSeconds"`
+	GRPCConnectionReuse          string `json:"GRPCConnectionReuse"`
+	GRPCConnectionPoolSize       int    `json:"GRPCConnectionPoolSize"`
+	DAGMode                      bool   `json:"DAGMode"`
+	EnableDAGDataset             bool   `json:"EnableDAGDataset"`
+	Width                        int    `json:"Width"`
+	Depth                        int    `json:"Depth"`
+	VSwarm                       bool   `json:"VSwarm"`
+
+	// results of asynchronous invocations are polled with exponential backoff until the collection deadline,
+	// which follows the last issued invocation
+	AsyncCollectionTimeoutSeconds int `json:"AsyncCollectionTimeoutSeconds"`
+	AsyncPollInitialBackoffMs     int `json:"AsyncPollInitialBackoffMs"`
+	AsyncPollMaxBackoffMs         int `json:"AsyncPollMaxBackoffMs"`
+	// enables the callback receiver, to which functions or the platform POST the results of asynchronous invocations
+	AsyncCallbackAddress string `json:"AsyncCallbackAddress"`
+	// URL of the callback receiver as seen by functions, derived from AsyncCallbackAddress if empty
+	AsyncCallbackURL string `json:"AsyncCallbackURL"`
+
+	// used only if platform is awslambda, functions are invoked through their URL with RequestResponse and
+	// asynchronously through the Invoke API with Event
+	AWSLambdaInvocationType string `json:"AWSLambdaInvocationType"`
+
+	// used only if platform is knative, time given to each service to become ready
+	KnativeReadyTimeoutSeconds int `json:"KnativeReadyTimeoutSeconds"`
+
+	// deployed functions are probed until they succeed or the timeout expires, disabled if not positive
+	ReadinessTimeoutSeconds int    `json:"ReadinessTimeoutSeconds"`
+	ReadinessPolicy         string `json:"ReadinessPolicy"`
+	PreWarm                 bool   `json:"PreWarm"`
 
 	// used only if platform is dirigent
 	DirigentConfigPath string `json:"DirigentConfigPath"`
+	// used only if platform is generichttp
+	GenericHTTPConfigPath string `json:"GenericHTTPConfigPath"`
+	// used only if platform is openfaas
+	OpenFaaSConfigPath string `json:"OpenFaaSConfigPath"`
+	// used only if platform is fission
+	FissionConfigPath string `json:"FissionConfigPath"`
+	// used only if platform is simulated
+	SimulatedConfigPath string `json:"SimulatedConfigPath"`
+	// used only if platform is local
+	LocalConfigPath string `json:"LocalConfigPath"`
+	// used only with the grpc invoke protocol, replaces the built-in faas.proto and vSwarm helloworld requests
+	DynamicGRPCConfigPath string `json:"DynamicGRPCConfigPath"`
+	// used only if platform is knative or dirigent, derives the scaling settings of each function from the trace
+	AutoscalingConfigPath string `json:"AutoscalingConfigPath"`
 }
 
 type WorkflowFunction struct {
@@ -117,6 +188,122 @@ type DirigentConfig struct {
 	WorkflowConfigPath string `json:"WorkflowConfigPath"`
 }
 
+// GenericHTTPConfig describes how to invoke functions through an arbitrary HTTP gateway. URL, header and body
+// templates are Go templates (text/template) rendered for every invocation.
+type GenericHTTPConfig struct {
+	URLTemplate     string            `json:"URLTemplate"`
+	Method          string            `json:"Method"`
+	HeaderTemplates map[string]string `json:"HeaderTemplates"`
+	BodyTemplate    string            `json:"BodyTemplate"`
+
+	// Dot-separated paths into the JSON response body, e.g. "result.durationUs" or "items.0.instance"
+	DurationJSONPath string `json:"DurationJSONPath"`
+	DurationUnit     string `json:"DurationUnit"`
+	InstanceJSONPath string `json:"InstanceJSONPath"`
+
+	// Asynchronous invocations are enabled by AsyncPollURLTemplate. The response ID of a submitted invocation is read
+	// from a header or a JSON path of the submission response, and the result is polled from the rendered URL.
+	AsyncResponseIDHeader   string `json:"AsyncResponseIDHeader"`
+	AsyncResponseIDJSONPath string `json:"AsyncResponseIDJSONPath"`
+	AsyncPollURLTemplate    string `json:"AsyncPollURLTemplate"`
+}
+
+type OpenFaaSConfig struct {
+	GatewayURL string `json:"GatewayURL"`
+	Username   string `json:"Username"`
+	// read from the OPENFAAS_PASSWORD environment variable if empty
+	Password  string `json:"Password"`
+	Namespace string `json:"Namespace"`
+	Image     string `json:"Image"`
+}
+
+type FissionConfig struct {
+	RouterURL string `json:"RouterURL"`
+	// namespace of the environment, packages, functions and HTTP triggers
+	Namespace string `json:"Namespace"`
+	Image     string `json:"Image"`
+
+	// poolmgr or newdeploy
+	ExecutorType string `json:"ExecutorType"`
+	// number of pre-warmed pods of the environment (poolmgr only)
+	PoolSize int `json:"PoolSize"`
+	// upper bound of the function scale (newdeploy only), the lower bound is the initial scale from the trace
+	MaxScale int `json:"MaxScale"`
+}
+
+// SimulatedConfig describes the in-process model of a FaaS platform running on a single node.
+type SimulatedConfig struct {
+	// idle instances are removed after the keep-alive
+	KeepAliveSeconds int `json:"KeepAliveSeconds"`
+	// cold start latencies follow a log-normal distribution with the given median and shape
+	ColdStartMedianMs float64 `json:"ColdStartMedianMs"`
+	ColdStartSigma    float64 `json:"ColdStartSigma"`
+	// number of requests an instance serves at a time
+	InstanceConcurrency int `json:"InstanceConcurrency"`
+	// memory of the node, shared by the instances of all functions
+	NodeMemoryMiB int `json:"NodeMemoryMiB"`
+	// time a request waits for an instance before failing
+	QueueTimeoutSeconds int   `json:"QueueTimeoutSeconds"`
+	Seed                int64 `json:"Seed"`
+}
+
+// LocalConfig describes how the trace function is run as one local process per function.
+type LocalConfig struct {
+	// prebuilt trace function binary, built from the source path if empty
+	BinaryPath string `json:"BinaryPath"`
+	SourcePath string `json:"SourcePath"`
+	// address the functions are served on
+	Address string `json:"Address"`
+	// idle processes are killed after the keep-alive
+	KeepAliveSeconds      int `json:"KeepAliveSeconds"`
+	StartupTimeoutSeconds int `json:"StartupTimeoutSeconds"`
+	// passed to the trace function, which defaults to the calibration of a Cloudlab xl170 node if zero
+	IterationsMultiplier int `json:"IterationsMultiplier"`
+}
+
+// AutoscalingConfig selects the policy computing the scaling settings of each function from the trace, and
+// overrides the policy or the computed settings of individual functions.
+type AutoscalingConfig struct {
+	Policy string `json:"Policy"`
+	// factor applied to the peak concurrency of the trace to obtain the maximum scale
+	MaxScaleHeadroom float64 `json:"MaxScaleHeadroom"`
+	// keyed by function name
+	Overrides map[string]AutoscalingOverride `json:"Overrides"`
+}
+
+// AutoscalingOverride replaces the policy or the settings of a function, where set.
+type AutoscalingOverride struct {
+	Policy                      string   `json:"Policy"`
+	MinScale                    *int     `json:"MinScale"`
+	MaxScale                    *int     `json:"MaxScale"`
+	Metric                      string   `json:"Metric"`
+	Target                      *float64 `json:"Target"`
+	StableWindowSeconds         *int     `json:"StableWindowSeconds"`
+	ScaleToZeroRetentionSeconds *int     `json:"ScaleToZeroRetentionSeconds"`
+}
+
+// DynamicGRPCConfig describes gRPC methods invoked with requests built at runtime. Message types are resolved
+// through server reflection unless a descriptor set (protoc --include_imports --descriptor_set_out) is given.
+type DynamicGRPCConfig struct {
+	DescriptorSetPath string `json:"DescriptorSetPath"`
+
+	Default DynamicGRPCMethod `json:"Default"`
+	// Methods to use instead of the default one, keyed by function name prefix
+	Functions map[string]DynamicGRPCMethod `json:"Functions"`
+}
+
+type DynamicGRPCMethod struct {
+	// Fully-qualified method name, e.g. "helloworld.Greeter/SayHello"
+	Method string `json:"Method"`
+	// Go template (text/template) of the request message in the protobuf JSON format
+	PayloadTemplate string `json:"PayloadTemplate"`
+
+	// Dot-separated paths into the response message in the protobuf JSON format
+	DurationJSONPath string `json:"DurationJSONPath"`
+	DurationUnit     string `json:"DurationUnit"`
+	InstanceJSONPath string `json:"InstanceJSONPath"`
+}
+
 func ReadConfigurationFile(path string) LoaderConfiguration {
 	byteValue, err := os.ReadFile(path)
 	if err != nil {
@@ -132,9 +319,31 @@ func ReadConfigurationFile(path string) LoaderConfiguration {
 	// set to lower in order to always match constants
 	config.Platform = strings.ToLower(config.Platform)
 
+	if config.AsyncCallbackAddress != "" && config.AsyncCallbackURL == "" {
+		config.AsyncCallbackURL = defaultAsyncCallbackURL(config.AsyncCallbackAddress)
+	}
+
 	return config
 }
 
+// defaultAsyncCallbackURL returns the URL of the callback receiver listening on the given address, using the
+// hostname of the loader machine if the address does not specify a host.
+func defaultAsyncCallbackURL(address string) string {
+	host, port, err := net.SplitHostPort(address)
+	if err != nil {
+		log.Fatalf("Invalid AsyncCallbackAddress %s - %v", address, err)
+	}
+
+	if host == "" || net.ParseIP(host).IsUnspecified() {
+		host, err = os.Hostname()
+		if err != nil {
+			log.Fatalf("Failed to resolve the hostname for AsyncCallbackURL - %v", err)
+		}
+	}
+
+	return "http://" + net.JoinHostPort(host, port) + "/callback"
+}
+
 func ReadFailureConfiguration(path string) *FailureConfiguration {
 	byteValue, err := os.ReadFile(path)
 	if err != nil {
@@ -193,3 +402,289 @@ func ReadDirigentConfig(cfg *LoaderConfiguration) *DirigentConfig {
 
 	return &config
 }
+
+func ReadGenericHTTPConfig(cfg *LoaderConfiguration) *GenericHTTPConfig {
+	if cfg.Platform != common.PlatformGenericHTTP {
+		return nil
+	}
+
+	if cfg.GenericHTTPConfigPath == "" {
+		log.Fatal("Missing GenericHTTPConfigPath in loader configuration!")
+	}
+	byteValue, err := os.ReadFile(cfg.GenericHTTPConfigPath)
+	if err != nil {
+		log.Fatalf("Failed to read generic HTTP config: %v", err)
+	}
+	var config GenericHTTPConfig
+	err = json.Unmarshal(byteValue, &config)
+	if err != nil {
+		log.Fatalf("Failed to unmarshal generic HTTP config json: %v", err)
+	}
+
+	if config.URLTemplate == "" {
+		log.Fatal("Missing URLTemplate in generic HTTP configuration!")
+	}
+
+	// defaults
+	if config.Method == "" {
+		config.Method = "POST"
+	}
+	if config.DurationUnit == "" {
+		config.DurationUnit = common.DurationUnitMicroseconds
+	}
+	if !slices.Contains(common.ValidDurationUnits, config.DurationUnit) {
+		log.Fatalf("Invalid DurationUnit '%s' in generic HTTP configuration", config.DurationUnit)
+	}
+	if config.AsyncPollURLTemplate != "" && config.AsyncResponseIDHeader == "" && config.AsyncResponseIDJSONPath == "" {
+		log.Fatal("Either AsyncResponseIDHeader or AsyncResponseIDJSONPath is required with AsyncPollURLTemplate!")
+	}
+	if (config.AsyncResponseIDHeader != "" || config.AsyncResponseIDJSONPath != "") &&
+		config.AsyncPollURLTemplate == "" && cfg.AsyncCallbackAddress == "" {
+		log.Fatal("Either AsyncPollURLTemplate or AsyncCallbackAddress is required for asynchronous invocations!")
+	}
+
+	return &config
+}
+
+func ReadDynamicGRPCConfig(cfg *LoaderConfiguration) *DynamicGRPCConfig {
+	if cfg.DynamicGRPCConfigPath == "" {
+		return nil
+	}
+
+	byteValue, err := os.ReadFile(cfg.DynamicGRPCConfigPath)
+	if err != nil {
+		log.Fatalf("Failed to read dynamic gRPC config: %v", err)
+	}
+	var config DynamicGRPCConfig
+	err = json.Unmarshal(byteValue, &config)
+	if err != nil {
+		log.Fatalf("Failed to unmarshal dynamic gRPC config json: %v", err)
+	}
+
+	validateDynamicGRPCMethod("Default", &config.Default)
+	for prefix, method := range config.Functions {
+		validateDynamicGRPCMethod(prefix, &method)
+		config.Functions[prefix] = method
+	}
+
+	return &config
+}
+
+func validateDynamicGRPCMethod(name string, method *DynamicGRPCMethod) {
+	if strings.Count(method.Method, "/") != 1 {
+		log.Fatalf("Invalid method '%s' of '%s' in dynamic gRPC configuration", method.Method, name)
+	}
+
+	// defaults
+	if method.PayloadTemplate == "" {
+		method.PayloadTemplate = "{}"
+	}
+	if method.DurationUnit == "" {
+		method.DurationUnit = common.DurationUnitMicroseconds
+	}
+	if !slices.Contains(common.ValidDurationUnits, method.DurationUnit) {
+		log.Fatalf("Invalid DurationUnit '%s' of '%s' in dynamic gRPC configuration", method.DurationUnit, name)
+	}
+}
+
+func ReadOpenFaaSConfig(cfg *LoaderConfiguration) *OpenFaaSConfig {
+	if cfg.Platform != common.PlatformOpenFaaS {
+		return nil
+	}
+
+	var config OpenFaaSConfig
+	if cfg.OpenFaaSConfigPath != "" {
+		byteValue, err := os.ReadFile(cfg.OpenFaaSConfigPath)
+		if err != nil {
+			log.Fatalf("Failed to read OpenFaaS config: %v", err)
+		}
+		err = json.Unmarshal(byteValue, &config)
+		if err != nil {
+			log.Fatalf("Failed to unmarshal OpenFaaS config json: %v", err)
+		}
+	}
+
+	// defaults
+	if config.GatewayURL == "" {
+		config.GatewayURL = "http://127.0.0.1:8080"
+	}
+	config.GatewayURL = strings.TrimSuffix(config.GatewayURL, "/")
+	if config.Username == "" {
+		config.Username = "admin"
+	}
+	if config.Password == "" {
+		config.Password = os.Getenv("OPENFAAS_PASSWORD")
+	}
+	if config.Image == "" {
+		config.Image = "ghcr.io/vhive-serverless/invitro_trace_function:latest"
+	}
+
+	return &config
+}
+
+func ReadAutoscalingConfig(cfg *LoaderConfiguration) *AutoscalingConfig {
+	if cfg.AutoscalingConfigPath == "" {
+		return nil
+	}
+	if cfg.Platform != common.PlatformKnative && cfg.Platform != common.PlatformDirigent {
+		log.Warnf("Autoscaling configuration is ignored on platform %s", cfg.Platform)
+		return nil
+	}
+
+	byteValue, err := os.ReadFile(cfg.AutoscalingConfigPath)
+	if err != nil {
+		log.Fatalf("Failed to read autoscaling config: %v", err)
+	}
+
+	var config AutoscalingConfig
+	err = json.Unmarshal(byteValue, &config)
+	if err != nil {
+		log.Fatalf("Failed to unmarshal autoscaling config json: %v", err)
+	}
+
+	// defaults
+	if config.Policy == "" {
+		config.Policy = common.AutoscalingPolicyTrace
+	}
+	if config.MaxScaleHeadroom <= 0 {
+		config.MaxScaleHeadroom = 1.2
+	}
+
+	policies := []string{config.Policy}
+	for name, override := range config.Overrides {
+		if override.Policy != "" {
+			policies = append(policies, override.Policy)
+		}
+		if override.Metric != "" && override.Metric != "concurrency" && override.Metric != "rps" {
+			log.Fatalf("Invalid Metric '%s' for function %s in autoscaling configuration", override.Metric, name)
+		}
+	}
+	for _, policy := range policies {
+		if !slices.Contains(common.ValidAutoscalingPolicies, policy) {
+			log.Fatalf("Invalid Policy '%s' in autoscaling configuration", policy)
+		}
+	}
+
+	return &config
+}
+
+func ReadSimulatedConfig(cfg *LoaderConfiguration) *SimulatedConfig {
+	if cfg.Platform != common.PlatformSimulated {
+		return nil
+	}
+
+	var config SimulatedConfig
+	if cfg.SimulatedConfigPath != "" {
+		byteValue, err := os.ReadFile(cfg.SimulatedConfigPath)
+		if err != nil {
+			log.Fatalf("Failed to read simulated platform config: %v", err)
+		}
+		err = json.Unmarshal(byteValue, &config)
+		if err != nil {
+			log.Fatalf("Failed to unmarshal simulated platform config json: %v", err)
+		}
+	}
+
+	// defaults
+	if config.KeepAliveSeconds <= 0 {
+		config.KeepAliveSeconds = 600
+	}
+	if config.ColdStartMedianMs <= 0 {
+		config.ColdStartMedianMs = 500
+	}
+	if config.ColdStartSigma <= 0 {
+		config.ColdStartSigma = 0.5
+	}
+	if config.InstanceConcurrency <= 0 {
+		config.InstanceConcurrency = 1
+	}
+	if config.NodeMemoryMiB <= 0 {
+		config.NodeMemoryMiB = 65536
+	}
+	if config.QueueTimeoutSeconds <= 0 {
+		config.QueueTimeoutSeconds = max(cfg.GRPCFunctionTimeoutSeconds, 60)
+	}
+	if config.Seed == 0 {
+		config.Seed = cfg.Seed
+	}
+
+	return &config
+}
+
+func ReadLocalConfig(cfg *LoaderConfiguration) *LocalConfig {
+	if cfg.Platform != common.PlatformLocal {
+		return nil
+	}
+
+	var config LocalConfig
+	if cfg.LocalConfigPath != "" {
+		byteValue, err := os.ReadFile(cfg.LocalConfigPath)
+		if err != nil {
+			log.Fatalf("Failed to read local platform config: %v", err)
+		}
+		err = json.Unmarshal(byteValue, &config)
+		if err != nil {
+			log.Fatalf("Failed to unmarshal local platform config json: %v", err)
+		}
+	}
+
+	// defaults
+	if config.BinaryPath == "" && config.SourcePath == "" {
+		config.SourcePath = "./server/trace-func-go"
+	}
+	if config.Address == "" {
+		config.Address = "127.0.0.1"
+	}
+	if config.KeepAliveSeconds <= 0 {
+		config.KeepAliveSeconds = 600
+	}
+	if config.StartupTimeoutSeconds <= 0 {
+		config.StartupTimeoutSeconds = 30
+	}
+
+	return &config
+}
+
+func ReadFissionConfig(cfg *LoaderConfiguration) *FissionConfig {
+	if cfg.Platform != common.PlatformFission {
+		return nil
+	}
+
+	var config FissionConfig
+	if cfg.FissionConfigPath != "" {
+		byteValue, err := os.ReadFile(cfg.FissionConfigPath)
+		if err != nil {
+			log.Fatalf("Failed to read Fission config: %v", err)
+		}
+		err = json.Unmarshal(byteValue, &config)
+		if err != nil {
+			log.Fatalf("Failed to unmarshal Fission config json: %v", err)
+		}
+	}
+
+	// defaults
+	if config.RouterURL == "" {
+		config.RouterURL = "http://router.fission"
+	}
+	config.RouterURL = strings.TrimSuffix(config.RouterURL, "/")
+	if config.Namespace == "" {
+		config.Namespace = "default"
+	}
+	if config.Image == "" {
+		config.Image = "ghcr.io/vhive-serverless/invitro_trace_function:latest"
+	}
+	if config.ExecutorType == "" {
+		config.ExecutorType = common.FissionExecutorPoolManager
+	}
+	if !slices.Contains(common.ValidFissionExecutors, config.ExecutorType) {
+		log.Fatalf("Invalid ExecutorType '%s' in Fission configuration", config.ExecutorType)
+	}
+	if config.PoolSize <= 0 {
+		config.PoolSize = 3
+	}
+	if config.MaxScale <= 0 {
+		config.MaxScale = 100
+	}
+
+	return &config
+}
@@ -71,3 +71,185 @@ func TestConfigParser(t *testing.T) {
 		t.Error("Unexpected configuration read.")
 	}
 }
+
+func TestGenericHTTPConfigParser(t *testing.T) {
+	config := ReadGenericHTTPConfig(&LoaderConfiguration{
+		Platform:              common.PlatformGenericHTTP,
+		GenericHTTPConfigPath: "../../cmd/generic_http/config_openfaas.json",
+	})
+
+	if config.URLTemplate != "http://127.0.0.1:8080/function/{{.FunctionName}}" ||
+		config.Method != "POST" ||
+		config.HeaderTemplates["Content-Type"] != "application/json" ||
+		config.BodyTemplate == "" ||
+		config.DurationJSONPath != "DurationInMicroSec" ||
+		config.DurationUnit != common.DurationUnitMicroseconds ||
+		config.InstanceJSONPath != "Function" {
+
+		t.Error("Unexpected generic HTTP configuration structure.")
+	}
+
+	if ReadGenericHTTPConfig(&LoaderConfiguration{Platform: common.PlatformKnative}) != nil {
+		t.Error("Generic HTTP configuration should only be read for the generic HTTP platform.")
+	}
+}
+
+func TestDefaultAsyncCallbackURL(t *testing.T) {
+	if url := defaultAsyncCallbackURL("10.0.1.1:9093"); url != "http://10.0.1.1:9093/callback" {
+		t.Errorf("Unexpected callback URL %s", url)
+	}
+
+	hostname, _ := os.Hostname()
+	for _, address := range []string{":9093", "0.0.0.0:9093"} {
+		if url := defaultAsyncCallbackURL(address); url != "http://"+hostname+":9093/callback" {
+			t.Errorf("Unexpected callback URL %s for %s", url, address)
+		}
+	}
+}
+
+func TestDynamicGRPCConfigParser(t *testing.T) {
+	config := ReadDynamicGRPCConfig(&LoaderConfiguration{
+		DynamicGRPCConfigPath: "../../cmd/dynamic_grpc/config_helloworld.json",
+	})
+
+	if config.DescriptorSetPath != "" ||
+		config.Default.Method != "helloworld.Greeter/SayHello" ||
+		config.Default.DurationUnit != common.DurationUnitMicroseconds ||
+		config.Default.InstanceJSONPath != "message" ||
+		config.Functions["trace-func"].Method != "faas.Executor/Execute" ||
+		config.Functions["trace-func"].DurationJSONPath != "durationInMicroSec" {
+
+		t.Error("Unexpected dynamic gRPC configuration structure.")
+	}
+
+	if ReadDynamicGRPCConfig(&LoaderConfiguration{}) != nil {
+		t.Error("Dynamic gRPC configuration should only be read if configured.")
+	}
+}
+
+func TestOpenFaaSConfigParser(t *testing.T) {
+	t.Setenv("OPENFAAS_PASSWORD", "secret")
+
+	config := ReadOpenFaaSConfig(&LoaderConfiguration{
+		Platform:           common.PlatformOpenFaaS,
+		OpenFaaSConfigPath: "../../cmd/openfaas/config.json",
+	})
+
+	if config.GatewayURL != "http://127.0.0.1:8080" ||
+		config.Username != "admin" ||
+		config.Password != "secret" ||
+		config.Image != "ghcr.io/vhive-serverless/invitro_trace_function:latest" {
+
+		t.Error("Unexpected OpenFaaS configuration structure.")
+	}
+}
+
+func TestFissionConfigParser(t *testing.T) {
+	config := ReadFissionConfig(&LoaderConfiguration{
+		Platform:          common.PlatformFission,
+		FissionConfigPath: "../../cmd/fission/config.json",
+	})
+
+	if config.RouterURL != "http://router.fission" ||
+		config.Namespace != "default" ||
+		config.ExecutorType != common.FissionExecutorPoolManager ||
+		config.PoolSize != 3 ||
+		config.MaxScale != 100 {
+
+		t.Error("Unexpected Fission configuration structure.")
+	}
+
+	if ReadFissionConfig(&LoaderConfiguration{Platform: common.PlatformKnative}) != nil {
+		t.Error("Fission configuration should only be read for the Fission platform.")
+	}
+}
+
+func TestAutoscalingConfigParser(t *testing.T) {
+	config := ReadAutoscalingConfig(&LoaderConfiguration{
+		Platform:              common.PlatformKnative,
+		AutoscalingConfigPath: "../../cmd/autoscaling/config.json",
+	})
+
+	provisioned := config.Overrides["trace-func-0-2642643831809466437"]
+	rps := config.Overrides["trace-func-1-5103455542103016411"]
+	if config.Policy != common.AutoscalingPolicyTrace ||
+		config.MaxScaleHeadroom != 1.2 ||
+		provisioned.Policy != common.AutoscalingPolicyProvisioned ||
+		provisioned.MaxScale == nil || *provisioned.MaxScale != 20 ||
+		provisioned.MinScale != nil ||
+		rps.Metric != "rps" ||
+		rps.Target == nil || *rps.Target != 10 {
+
+		t.Error("Unexpected autoscaling configuration structure.")
+	}
+
+	if ReadAutoscalingConfig(&LoaderConfiguration{Platform: common.PlatformKnative}) != nil {
+		t.Error("Autoscaling configuration should only be read if its path is set.")
+	}
+}
+
+func TestSimulatedConfigParser(t *testing.T) {
+	config := ReadSimulatedConfig(&LoaderConfiguration{
+		Platform:            common.PlatformSimulated,
+		SimulatedConfigPath: "../../cmd/simulated/config.json",
+		Seed:                42,
+	})
+
+	if config.KeepAliveSeconds != 600 ||
+		config.ColdStartMedianMs != 500 ||
+		config.ColdStartSigma != 0.5 ||
+		config.InstanceConcurrency != 1 ||
+		config.NodeMemoryMiB != 65536 ||
+		config.QueueTimeoutSeconds != 60 ||
+		config.Seed != 42 {
+
+		t.Error("Unexpected simulated platform configuration structure.")
+	}
+
+	defaults := ReadSimulatedConfig(&LoaderConfiguration{Platform: common.PlatformSimulated, GRPCFunctionTimeoutSeconds: 900})
+	if defaults == nil || defaults.QueueTimeoutSeconds != 900 || defaults.InstanceConcurrency != 1 {
+		t.Error("Simulated platform configuration should fall back to the defaults without a path.")
+	}
+}
+
+func TestLocalConfigParser(t *testing.T) {
+	config := ReadLocalConfig(&LoaderConfiguration{
+		Platform:        common.PlatformLocal,
+		LocalConfigPath: "../../cmd/local/config.json",
+	})
+
+	if config.BinaryPath != "" ||
+		config.SourcePath != "./server/trace-func-go" ||
+		config.Address != "127.0.0.1" ||
+		config.KeepAliveSeconds != 600 ||
+		config.StartupTimeoutSeconds != 30 ||
+		config.IterationsMultiplier != 0 {
+
+		t.Error("Unexpected local platform configuration structure.")
+	}
+
+	if ReadLocalConfig(&LoaderConfiguration{Platform: common.PlatformKnative}) != nil {
+		t.Error("Local platform configuration should only be read for the local platform.")
+	}
+}
+
+func TestFailureScheduleParser(t *testing.T) {
+	config := ReadFailureConfiguration("../../cmd/failure_schedule.json")
+
+	if !config.FailureEnabled ||
+		config.DryRun ||
+		config.FailAt != 0 ||
+		len(config.Events) != 4 ||
+		config.Events[0].Component != "data_plane" ||
+		config.Events[0].DurationSeconds != 60 ||
+		config.Events[0].Recovery != "start" ||
+		len(config.Events[1].Nodes) != 2 ||
+		config.Events[1].RepeatEverySeconds != 300 ||
+		config.Events[1].RepeatCount != 2 ||
+		config.Events[2].At != 900 ||
+		config.Events[3].Function != "trace-func-0-2642643831809466437" ||
+		config.Events[3].Percentage != 50 {
+
+		t.Error("Unexpected failure schedule structure.")
+	}
+}
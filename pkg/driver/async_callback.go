@@ -0,0 +1,231 @@
+package driver
+
+import (
+	"encoding/json"
+	"errors"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/vhive-serverless/loader/pkg/common"
+	"github.com/vhive-serverless/loader/pkg/driver/clients"
+	"github.com/vhive-serverless/loader/pkg/metric"
+)
+
+const asyncCallbackPath = "/callback"
+
+type receivedCallback struct {
+	common.AsyncCallback
+	receivedAt time.Time
+}
+
+// pendingArrival is the notification of a record waiting for its callback under each of its callback keys.
+type pendingArrival struct {
+	arrived      chan struct{}
+	keys         []string
+	registeredAt time.Time
+}
+
+// callbackReceiver is an embedded HTTP server to which the results of asynchronous invocations are delivered. It
+// acts as the result source of the asyncCollector, which is notified as soon as a callback arrives, so records are
+// completed with the time the callback was received rather than the time a poll found the result.
+type callbackReceiver struct {
+	server   *http.Server
+	listener net.Listener
+	parser   clients.AsyncResponseParser
+
+	// callbacks and notifications older than the retention can no longer be collected and are dropped
+	retention time.Duration
+
+	mutex     sync.Mutex
+	callbacks map[string]*receivedCallback
+	arrivals  map[string]*pendingArrival
+	lastSweep time.Time
+}
+
+// newCallbackReceiver starts listening on the given address. The parser, if not nil, is used to parse the payload
+// of callbacks sent by the platform, while the payload of callbacks sent by functions is parsed as the reply of the
+// trace function. Unmatched callbacks are kept for the given retention, i.e., the collection timeout.
+func newCallbackReceiver(address string, parser clients.AsyncResponseParser, retention time.Duration) (*callbackReceiver, error) {
+	listener, err := net.Listen("tcp", address)
+	if err != nil {
+		return nil, err
+	}
+
+	r := &callbackReceiver{
+		listener:  listener,
+		parser:    parser,
+		retention: retention,
+		callbacks: make(map[string]*receivedCallback),
+		arrivals:  make(map[string]*pendingArrival),
+		lastSweep: time.Now(),
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc(asyncCallbackPath, r.handleCallback)
+	r.server = &http.Server{Handler: mux, ReadHeaderTimeout: 5 * time.Second}
+
+	go func() {
+		if err := r.server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Errorf("Callback receiver stopped - %v", err)
+		}
+	}()
+
+	log.Infof("Receiving asynchronous invocation callbacks on http://%s%s", listener.Addr(), asyncCallbackPath)
+
+	return r, nil
+}
+
+// responseKey and invocationKey identify a callback by the response ID returned by the platform, or by the
+// invocation ID and the function name forwarded to the function, as invocation IDs are only unique per function.
+func responseKey(responseID string) string {
+	return "response/" + responseID
+}
+
+func invocationKey(function string, invocationID string) string {
+	return "invocation/" + function + "/" + invocationID
+}
+
+func recordCallbackKeys(record *metric.ExecutionRecord) []string {
+	return []string{responseKey(record.AsyncResponseID), invocationKey(record.Function, record.InvocationID)}
+}
+
+func (r *callbackReceiver) address() string {
+	return r.listener.Addr().String()
+}
+
+func (r *callbackReceiver) handleCallback(w http.ResponseWriter, req *http.Request) {
+	receivedAt := time.Now()
+
+	if req.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	var callback common.AsyncCallback
+	if err := json.NewDecoder(req.Body).Decode(&callback); err != nil {
+		log.Debugf("Received a malformed callback - %v", err)
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	var key string
+	if callback.AsyncResponseID != "" {
+		key = responseKey(callback.AsyncResponseID)
+	} else if callback.InvocationID != "" && callback.Function != "" {
+		key = invocationKey(callback.Function, callback.InvocationID)
+	} else {
+		log.Debug("Received a callback without response ID or invocation ID")
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	r.mutex.Lock()
+	r.sweep(receivedAt)
+	// callbacks may arrive before the invocation returned its response ID, so they are kept until collected
+	r.callbacks[key] = &receivedCallback{AsyncCallback: callback, receivedAt: receivedAt}
+	if pending, ok := r.arrivals[key]; ok {
+		close(pending.arrived)
+		for _, k := range pending.keys {
+			delete(r.arrivals, k)
+		}
+	}
+	r.mutex.Unlock()
+
+	w.WriteHeader(http.StatusAccepted)
+}
+
+func (r *callbackReceiver) notify(record *metric.ExecutionRecord) <-chan struct{} {
+	now := time.Now()
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.sweep(now)
+
+	pending := &pendingArrival{arrived: make(chan struct{}), keys: recordCallbackKeys(record), registeredAt: now}
+	for _, key := range pending.keys {
+		if _, ok := r.callbacks[key]; ok {
+			close(pending.arrived)
+			return pending.arrived
+		}
+	}
+	for _, key := range pending.keys {
+		r.arrivals[key] = pending
+	}
+
+	return pending.arrived
+}
+
+// sweep drops the callbacks and the notifications older than the retention, i.e., those of invocations that were
+// never submitted, e.g., since the invocation failed after the function completed, or that have expired. Sweeps run
+// at most once per retention, so entries are dropped between one and two retentions after their arrival.
+func (r *callbackReceiver) sweep(now time.Time) {
+	if r.retention <= 0 || now.Sub(r.lastSweep) < r.retention {
+		return
+	}
+	r.lastSweep = now
+
+	for key, callback := range r.callbacks {
+		if now.Sub(callback.receivedAt) >= r.retention {
+			delete(r.callbacks, key)
+		}
+	}
+	for key, pending := range r.arrivals {
+		if now.Sub(pending.registeredAt) >= r.retention {
+			delete(r.arrivals, key)
+		}
+	}
+}
+
+// Collect completes the record with its callback, if it has been received.
+func (r *callbackReceiver) Collect(record *metric.ExecutionRecord) (bool, error) {
+	var callback *receivedCallback
+	r.mutex.Lock()
+	for _, key := range recordCallbackKeys(record) {
+		if received, ok := r.callbacks[key]; ok {
+			callback = received
+			delete(r.callbacks, key)
+			break
+		}
+	}
+	r.mutex.Unlock()
+
+	if callback == nil {
+		return false, nil
+	}
+
+	record.ResponseTime = callback.receivedAt.UnixMicro() - record.StartTime
+	if callback.EndToEndDurationInMicroSec > 0 {
+		record.UserCodeExecutionMs = callback.EndToEndDurationInMicroSec
+	}
+
+	if len(callback.Payload) > 0 {
+		payload := []byte(callback.Payload)
+		// payloads may also be sent as JSON strings
+		var unquoted string
+		if json.Unmarshal(payload, &unquoted) == nil {
+			payload = []byte(unquoted)
+		}
+
+		var err error
+		if callback.AsyncResponseID == "" {
+			err = clients.ParseTraceFunctionReply(payload, record)
+		} else if r.parser != nil {
+			err = r.parser.ParseAsyncResponse(payload, record)
+		}
+		if err != nil {
+			log.Debugf("Failed to parse the callback payload of %s - %v", record.InvocationID, err)
+		}
+	}
+
+	return true, nil
+}
+
+func (r *callbackReceiver) close() {
+	if err := r.server.Close(); err != nil {
+		log.Warnf("Failed to stop the callback receiver - %v", err)
+	}
+}
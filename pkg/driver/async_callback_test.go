@@ -0,0 +1,156 @@
+package driver
+
+import (
+	"bytes"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/vhive-serverless/loader/pkg/common"
+	"github.com/vhive-serverless/loader/pkg/config"
+	"github.com/vhive-serverless/loader/pkg/driver/clients"
+	"github.com/vhive-serverless/loader/pkg/metric"
+)
+
+func postCallback(t *testing.T, receiver *callbackReceiver, body string) int {
+	resp, err := http.Post("http://"+receiver.address()+asyncCallbackPath, "application/json", bytes.NewBufferString(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer clients.HandleBodyClosing(resp)
+
+	return resp.StatusCode
+}
+
+func TestCallbackReceiver(t *testing.T) {
+	receiver, err := newCallbackReceiver("127.0.0.1:0", clients.CreateInvoker(&config.Configuration{
+		LoaderConfiguration: &config.LoaderConfiguration{Platform: "dirigent", InvokeProtocol: "http1"},
+		DirigentConfiguration: &config.DirigentConfig{
+			AsyncMode: true,
+		},
+	}).(clients.AsyncResponseParser), time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer receiver.close()
+
+	logCh := make(chan *metric.ExecutionRecord, 3)
+	collector := newAsyncCollector(receiver, channelCollector(logCh), &config.Configuration{LoaderConfiguration: &config.LoaderConfiguration{
+		AsyncCollectionTimeoutSeconds: 2,
+		// polling alone would not complete the records in time
+		AsyncPollInitialBackoffMs: 10000,
+		AsyncPollMaxBackoffMs:     10000,
+	}})
+
+	// callbacks may arrive before the invocation is submitted for collection
+	if status := postCallback(t, receiver, `{"AsyncResponseID": "early", "EndToEndDurationInMicroSec": 500}`); status != http.StatusAccepted {
+		t.Fatalf("Unexpected status code %d", status)
+	}
+
+	start := time.Now()
+	for _, id := range []string{"early", "late", "never"} {
+		collector.submit(&metric.ExecutionRecord{
+			ExecutionRecordBase: metric.ExecutionRecordBase{InvocationID: id, StartTime: start.UnixMicro()},
+			AsyncResponseID:     id,
+		})
+	}
+
+	time.Sleep(200 * time.Millisecond)
+	postCallback(t, receiver, `{"AsyncResponseID": "late", "EndToEndDurationInMicroSec": 1500, "Payload": "{\"Function\": \"instance-1\", \"ExecutionTime\": 1000}"}`)
+
+	if status := postCallback(t, receiver, `{"EndToEndDurationInMicroSec": 1}`); status != http.StatusBadRequest {
+		t.Errorf("Callbacks without response ID should be rejected, got %d", status)
+	}
+
+	collector.finish()
+	close(logCh)
+
+	records := make(map[string]*metric.ExecutionRecord)
+	for record := range logCh {
+		records[record.InvocationID] = record
+	}
+
+	if early := records["early"]; early.FunctionTimeout || early.UserCodeExecutionMs != 500 {
+		t.Errorf("Unexpected record of early callback %+v", early)
+	}
+
+	late := records["late"]
+	if late.FunctionTimeout || late.UserCodeExecutionMs != 1500 || late.Instance != "instance-1" || late.ActualDuration != 1000 {
+		t.Errorf("Unexpected record of late callback %+v", late)
+	}
+	if late.ResponseTime < (200*time.Millisecond).Microseconds() || late.ResponseTime > time.Second.Microseconds() {
+		t.Errorf("Response time should be the arrival time of the callback, got %d", late.ResponseTime)
+	}
+
+	if !records["never"].FunctionTimeout {
+		t.Errorf("Records without callback should expire")
+	}
+}
+
+func TestCallbackReceiverFunctionCallback(t *testing.T) {
+	receiver, err := newCallbackReceiver("127.0.0.1:0", nil, time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer receiver.close()
+
+	// invocation IDs are only unique per function
+	records := []*metric.ExecutionRecord{
+		{ExecutionRecordBase: metric.ExecutionRecordBase{InvocationID: "min0.inv0"}, AsyncResponseID: "a", Function: "f"},
+		{ExecutionRecordBase: metric.ExecutionRecordBase{InvocationID: "min0.inv0"}, AsyncResponseID: "b", Function: "g"},
+	}
+	arrived := receiver.notify(records[1])
+
+	url := "http://" + receiver.address() + asyncCallbackPath
+	err = common.PostAsyncCallback(url, &common.AsyncCallback{
+		InvocationID: "min0.inv0",
+		Function:     "g",
+		Payload:      []byte(`{"DurationInMicroSec": 1000, "MemoryUsageInKb": 2048, "InstanceID": "instance-1"}`),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case <-arrived:
+	case <-time.After(time.Second):
+		t.Fatal("The collection should be notified of the callback")
+	}
+
+	if done, _ := receiver.Collect(records[0]); done {
+		t.Errorf("The callback of another function should not be collected")
+	}
+	if done, _ := receiver.Collect(records[1]); !done ||
+		records[1].ActualDuration != 1000 || records[1].ActualMemoryUsage != 2 || records[1].InstanceID != "instance-1" {
+
+		t.Errorf("Unexpected record %+v", records[1])
+	}
+
+	if err = common.PostAsyncCallback(url, &common.AsyncCallback{InvocationID: "min0.inv1"}); err == nil {
+		t.Errorf("Callbacks without function name should be rejected")
+	}
+}
+
+func TestCallbackReceiverExpiry(t *testing.T) {
+	receiver, err := newCallbackReceiver("127.0.0.1:0", nil, 100*time.Millisecond)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer receiver.close()
+
+	postCallback(t, receiver, `{"AsyncResponseID": "unmatched"}`)
+	receiver.notify(&metric.ExecutionRecord{AsyncResponseID: "never", Function: "f"})
+
+	time.Sleep(250 * time.Millisecond)
+	postCallback(t, receiver, `{"AsyncResponseID": "recent"}`)
+
+	receiver.mutex.Lock()
+	defer receiver.mutex.Unlock()
+
+	if _, ok := receiver.callbacks[responseKey("recent")]; len(receiver.callbacks) != 1 || !ok {
+		t.Errorf("Only the recent callback should be kept, got %v", receiver.callbacks)
+	}
+	if len(receiver.arrivals) != 0 {
+		t.Errorf("Expired notifications should be dropped, got %v", receiver.arrivals)
+	}
+}
@@ -1,121 +1,186 @@
 package driver
 
 import (
-	"bytes"
 	log "github.com/sirupsen/logrus"
+	"github.com/vhive-serverless/loader/pkg/config"
 	"github.com/vhive-serverless/loader/pkg/driver/clients"
 	"github.com/vhive-serverless/loader/pkg/metric"
-	"io"
-	"math"
-	"net"
-	"net/http"
-	"strconv"
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
-func (d *Driver) writeAsyncRecordsToLog(logCh chan *metric.ExecutionRecord) {
-	const batchSize = 50
-
-	client := &http.Client{
-		Timeout: 5 * time.Second,
-		Transport: &http.Transport{
-			DialContext: (&net.Dialer{
-				Timeout: 2 * time.Second,
-			}).DialContext,
-			IdleConnTimeout:     time.Second,
-			MaxIdleConns:        batchSize,
-			MaxIdleConnsPerHost: batchSize,
-		},
-	}
+const (
+	defaultAsyncCollectionTimeout = 10 * time.Minute
+	defaultAsyncInitialBackoff    = 100 * time.Millisecond
+	defaultAsyncMaxBackoff        = 10 * time.Second
 
-	currentBatch := 0
-	totalBatches := int(math.Ceil(float64(d.AsyncRecords.Length()) / float64(batchSize)))
+	// limits the number of concurrent collection requests
+	maxConcurrentAsyncCollections = 50
+)
 
-	log.Infof("Gathering functions responses...")
-	for d.AsyncRecords.Length() > 0 {
-		currentBatch++
-
-		toProcess := min(d.AsyncRecords.Length(), batchSize)
-
-		wg := sync.WaitGroup{}
-		wg.Add(toProcess)
-
-		for range toProcess {
-			go func() {
-				defer wg.Done()
-
-				start := time.Now()
-
-				record := d.AsyncRecords.Dequeue()
-				response, e2e := d.getAsyncResponseData(
-					client,
-					d.Configuration.DirigentConfiguration.AsyncResponseURL,
-					record.AsyncResponseID,
-				)
-
-				if string(response) != "" {
-					err := clients.DeserializeDirigentResponse(response, record)
-					if err != nil {
-						log.Errorf("Failed to deserialize Dirigent response - %v - %v", string(response), err)
-					}
-				} else {
-					record.FunctionTimeout = true
-					record.AsyncResponseID = ""
-					log.Errorf("Failed to fetch response. The function has probably not yet completed.")
-				}
-
-				// loader send request + request e2e + loader get response
-				timeToFetchResponse := time.Since(start).Microseconds()
-				record.UserCodeExecutionMs = int64(e2e)
-				record.TimeToGetResponseMs = timeToFetchResponse
-				record.ResponseTime += int64(e2e)
-				record.ResponseTime += timeToFetchResponse
-
-				logCh <- record
-			}()
-		}
+// asyncCollector collects the results of asynchronous invocations while the experiment runs. Each record is
+// collected with exponential backoff until it completes or the deadline, set once all invocations have been issued,
+// expires. Records that could not be collected are written as failed.
+type asyncCollector struct {
+	source  clients.AsyncCollector
+	records metric.RecordCollector
 
-		wg.Wait()
+	timeout        time.Duration
+	initialBackoff time.Duration
+	maxBackoff     time.Duration
 
-		log.Infof("Processed %d/%d batches of async response gatherings", currentBatch, totalBatches)
-	}
+	semaphore chan struct{}
+	deadline  chan struct{}
+	pending   sync.WaitGroup
 
-	log.Infof("Finished gathering async reponse answers")
+	submitted atomic.Int64
+	collected atomic.Int64
+	expired   atomic.Int64
 }
 
-func (d *Driver) getAsyncResponseData(client *http.Client, endpoint string, guid string) ([]byte, int) {
-	req, err := http.NewRequest("GET", "http://"+endpoint, bytes.NewReader([]byte(guid)))
-	if err != nil {
-		log.Errorf("Failed to retrieve Dirigent response for %s - %v", guid, err)
-		return []byte{}, 0
+// asyncNotifier is implemented by asynchronous result sources that are notified of completions, e.g., the callback
+// receiver.
+type asyncNotifier interface {
+	// notify returns a channel closed once the result of the record has arrived
+	notify(record *metric.ExecutionRecord) <-chan struct{}
+}
+
+func newAsyncCollector(source clients.AsyncCollector, records metric.RecordCollector, cfg *config.Configuration) *asyncCollector {
+	lcfg := cfg.LoaderConfiguration
+
+	c := &asyncCollector{
+		source:  source,
+		records: records,
+
+		timeout:        asyncCollectionTimeout(cfg),
+		initialBackoff: time.Duration(lcfg.AsyncPollInitialBackoffMs) * time.Millisecond,
+		maxBackoff:     time.Duration(lcfg.AsyncPollMaxBackoffMs) * time.Millisecond,
+
+		semaphore: make(chan struct{}, maxConcurrentAsyncCollections),
+		deadline:  make(chan struct{}),
 	}
 
-	// TODO: set function name for load-balancing purpose
-	//req.Header.Set("function", function.Name)
+	if c.initialBackoff <= 0 {
+		c.initialBackoff = defaultAsyncInitialBackoff
+	}
+	if c.maxBackoff <= 0 {
+		c.maxBackoff = defaultAsyncMaxBackoff
+	}
 
-	resp, err := client.Do(req)
-	if err != nil {
-		log.Errorf("Failed to retrieve Dirigent response for %s - %v", guid, err)
-		return []byte{}, 0
+	return c
+}
+
+// asyncCollectionTimeout returns how long results are collected for once all invocations have been issued.
+func asyncCollectionTimeout(cfg *config.Configuration) time.Duration {
+	if timeout := time.Duration(cfg.LoaderConfiguration.AsyncCollectionTimeoutSeconds) * time.Second; timeout > 0 {
+		return timeout
 	}
 
-	defer clients.HandleBodyClosing(resp)
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		log.Errorf("Failed to read Dirigent response body for %s - %v", guid, err)
-		return []byte{}, 0
+	// NOTE: kept for compatibility with the configuration of Dirigent
+	if cfg.DirigentConfiguration != nil && cfg.DirigentConfiguration.AsyncWaitToCollectMin > 0 {
+		return time.Duration(cfg.DirigentConfiguration.AsyncWaitToCollectMin) * time.Minute
+	}
+
+	return defaultAsyncCollectionTimeout
+}
+
+func (c *asyncCollector) submit(record *metric.ExecutionRecord) {
+	c.submitted.Add(1)
+	c.pending.Add(1)
+	go c.collect(record)
+}
+
+func (c *asyncCollector) collect(record *metric.ExecutionRecord) {
+	defer c.pending.Done()
+
+	// sources pushing results to the loader wake the collection up as soon as the result arrives
+	var arrived <-chan struct{}
+	if notifier, ok := c.source.(asyncNotifier); ok {
+		arrived = notifier.notify(record)
 	}
 
-	hdr := resp.Header.Get("Duration-Microseconds")
-	e2e := 0
+	backoff := c.initialBackoff
+	for attempt := 1; ; attempt++ {
+		select {
+		case <-time.After(backoff):
+		case <-arrived:
+			// further attempts fall back to the backoff
+			arrived = nil
+		case <-c.deadline:
+			log.Debugf("Result of %s (%s) not collected after %d attempts", record.InvocationID, record.AsyncResponseID, attempt-1)
+
+			record.FunctionTimeout = true
+			record.AsyncResponseID = ""
+			c.expired.Add(1)
+			c.records.Collect(record)
+
+			return
+		}
+
+		c.semaphore <- struct{}{}
+		done, err := c.source.Collect(record)
+		<-c.semaphore
+
+		if done {
+			c.collected.Add(1)
+			c.records.Collect(record)
 
-	if hdr != "" {
-		e2e, err = strconv.Atoi(hdr)
+			return
+		}
 		if err != nil {
-			log.Errorf("Failed to parse end-to-end latency for %s - %v", guid, err)
+			log.Debugf("Failed to collect the result of %s (attempt %d) - %v", record.InvocationID, attempt, err)
 		}
+
+		backoff = min(2*backoff, c.maxBackoff)
+	}
+}
+
+// finish waits for the pending records to be collected, for at most the collection timeout.
+func (c *asyncCollector) finish() {
+	if c.submitted.Load() == 0 {
+		return
+	}
+
+	log.Infof("Gathering functions responses (timeout %v)...", c.timeout)
+
+	allCollected := make(chan struct{})
+	go func() {
+		c.pending.Wait()
+		close(allCollected)
+	}()
+
+	timer := time.NewTimer(c.timeout)
+	select {
+	case <-allCollected:
+		timer.Stop()
+	case <-timer.C:
+		close(c.deadline)
+		<-allCollected
+	}
+
+	log.Infof("Finished gathering async responses - %d collected, %d not completed", c.collected.Load(), c.expired.Load())
+}
+
+// writeOpenWhiskRecordsToLog completes the records of OpenWhisk invocations with their activation records, which
+// are fetched in bulk once all the invocations have returned.
+func (d *Driver) writeOpenWhiskRecordsToLog(collector metric.RecordCollector) {
+	records := make([]*metric.ExecutionRecord, 0, d.AsyncRecords.Length())
+	for d.AsyncRecords.Length() > 0 {
+		records = append(records, d.AsyncRecords.Dequeue())
+	}
+
+	log.Infof("Fetching %d OpenWhisk activation records...", len(records))
+	client, err := clients.NewOpenWhiskActivationClientFromProperties()
+	if err != nil {
+		log.Errorf("Failed to read the OpenWhisk properties, activation records will not be fetched - %v", err)
+	} else if incomplete := client.CompleteRecords(records); incomplete > 0 {
+		log.Warnf("Failed to fetch %d/%d OpenWhisk activation records", incomplete, len(records))
+	}
+
+	for _, record := range records {
+		collector.Collect(record)
 	}
 
-	return body, e2e
+	log.Infof("Finished fetching OpenWhisk activation records")
 }
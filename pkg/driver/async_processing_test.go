@@ -0,0 +1,95 @@
+package driver
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/vhive-serverless/loader/pkg/config"
+	"github.com/vhive-serverless/loader/pkg/metric"
+)
+
+// fakeAsyncSource completes each invocation after the given number of collection attempts, or never if negative.
+type fakeAsyncSource struct {
+	mutex    sync.Mutex
+	attempts map[string]int
+	required map[string]int
+}
+
+func (s *fakeAsyncSource) Collect(record *metric.ExecutionRecord) (bool, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.attempts[record.AsyncResponseID]++
+	required := s.required[record.AsyncResponseID]
+	if required < 0 || s.attempts[record.AsyncResponseID] < required {
+		return false, nil
+	}
+
+	record.ActualDuration = 1000
+	return true, nil
+}
+
+func TestAsyncCollector(t *testing.T) {
+	source := &fakeAsyncSource{
+		attempts: make(map[string]int),
+		required: map[string]int{"fast": 1, "slow": 4, "never": -1},
+	}
+	logCh := make(chan *metric.ExecutionRecord, 3)
+
+	collector := newAsyncCollector(source, channelCollector(logCh), &config.Configuration{LoaderConfiguration: &config.LoaderConfiguration{
+		AsyncCollectionTimeoutSeconds: 1,
+		AsyncPollInitialBackoffMs:     10,
+		AsyncPollMaxBackoffMs:         40,
+	}})
+
+	start := time.Now()
+	for _, id := range []string{"fast", "slow", "never"} {
+		collector.submit(&metric.ExecutionRecord{AsyncResponseID: id})
+	}
+	collector.finish()
+	close(logCh)
+
+	if elapsed := time.Since(start); elapsed < time.Second || elapsed > 3*time.Second {
+		t.Errorf("Collection should end at the deadline, took %v", elapsed)
+	}
+
+	completed, expired := 0, 0
+	for record := range logCh {
+		if record.FunctionTimeout {
+			expired++
+			if record.AsyncResponseID != "" {
+				t.Errorf("Expired record should not keep its response ID: %+v", record)
+			}
+		} else if record.ActualDuration == 1000 {
+			completed++
+		}
+	}
+
+	if completed != 2 || expired != 1 || source.attempts["slow"] != 4 || source.attempts["never"] < 5 {
+		t.Errorf("Unexpected collection: %d completed, %d expired, attempts: %v", completed, expired, source.attempts)
+	}
+}
+
+func TestAsyncCollectorEndsEarly(t *testing.T) {
+	source := &fakeAsyncSource{attempts: make(map[string]int), required: map[string]int{"fast": 2}}
+	logCh := make(chan *metric.ExecutionRecord, 1)
+
+	collector := newAsyncCollector(source, channelCollector(logCh), &config.Configuration{LoaderConfiguration: &config.LoaderConfiguration{
+		AsyncPollInitialBackoffMs: 10,
+	}})
+	if collector.timeout != defaultAsyncCollectionTimeout || collector.maxBackoff != defaultAsyncMaxBackoff {
+		t.Errorf("Unexpected defaults: %+v", collector)
+	}
+
+	start := time.Now()
+	collector.submit(&metric.ExecutionRecord{AsyncResponseID: "fast"})
+	collector.finish()
+
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Collection should end once all records are collected, took %v", elapsed)
+	}
+	if record := <-logCh; record.FunctionTimeout {
+		t.Errorf("Unexpected record: %+v", record)
+	}
+}
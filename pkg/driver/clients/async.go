@@ -0,0 +1,20 @@
+package clients
+
+import (
+	mc "github.com/vhive-serverless/loader/pkg/metric"
+)
+
+// AsyncCollector is implemented by invokers that can submit invocations without waiting for their completion. Such
+// invokers return from Invoke as soon as the invocation is accepted, with AsyncResponseID set in the record. The
+// result is then obtained through Collect, either by polling the platform or from results delivered to the loader.
+type AsyncCollector interface {
+	// Collect completes the record of a submitted invocation. It returns false if the invocation has not completed
+	// yet, in which case it is retried later. Errors are transient too, unless the collection deadline expires.
+	Collect(record *mc.ExecutionRecord) (bool, error)
+}
+
+// AsyncResponseParser is implemented by invokers whose asynchronous results can be delivered to the loader, e.g., to
+// its callback receiver, and parsed as the response body of a synchronous invocation.
+type AsyncResponseParser interface {
+	ParseAsyncResponse(body []byte, record *mc.ExecutionRecord) error
+}
@@ -0,0 +1,182 @@
+package clients
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/aws/middleware"
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs"
+	"github.com/aws/aws-sdk-go-v2/service/lambda"
+	"github.com/aws/aws-sdk-go-v2/service/lambda/types"
+	log "github.com/sirupsen/logrus"
+	"github.com/vhive-serverless/loader/pkg/common"
+	mc "github.com/vhive-serverless/loader/pkg/metric"
+)
+
+const lambdaRequestTimeout = 30 * time.Second
+
+var (
+	lambdaDurationPattern     = regexp.MustCompile(`\tDuration: ([0-9.]+) ms`)
+	lambdaInitDurationPattern = regexp.MustCompile(`Init Duration: ([0-9.]+) ms`)
+	lambdaMaxMemoryPattern    = regexp.MustCompile(`Max Memory Used: ([0-9]+) MB`)
+	lambdaStatusPattern       = regexp.MustCompile(`Status: (\S+)`)
+)
+
+// lambdaEventClient submits Event invocations through the Lambda Invoke API and collects their results from the
+// REPORT line that Lambda writes to the CloudWatch log group of the function once an invocation completes.
+type lambdaEventClient struct {
+	lambda *lambda.Client
+	logs   *cloudwatchlogs.Client
+
+	// URL of the callback receiver of the loader forwarded to the function, if enabled
+	callbackURL string
+}
+
+func newLambdaEventClient(callbackURL string) *lambdaEventClient {
+	ctx, cancel := context.WithTimeout(context.Background(), lambdaRequestTimeout)
+	defer cancel()
+
+	cfg, err := awsconfig.LoadDefaultConfig(ctx, awsconfig.WithRegion(common.AwsRegion))
+	if err != nil {
+		log.Fatalf("Failed to load the AWS configuration - %v", err)
+	}
+
+	return &lambdaEventClient{
+		lambda: lambda.NewFromConfig(cfg),
+		logs:   cloudwatchlogs.NewFromConfig(cfg),
+
+		callbackURL: callbackURL,
+	}
+}
+
+// invokeEvent submits the invocation, which returns as soon as Lambda queues it. The payload is shaped as a function
+// URL request, so that the trace function serves both types of invocations.
+func (c *lambdaEventClient) invokeEvent(function *common.Function, dataString string, invocationID string) (bool, *mc.ExecutionRecord) {
+	record := &mc.ExecutionRecord{ExecutionRecordBase: mc.ExecutionRecordBase{InvocationID: invocationID}}
+
+	start := time.Now()
+	record.StartTime = start.UnixMicro()
+	record.Instance = function.Name
+
+	header := http.Header{}
+	setInvocationHeaders(header, function, invocationID)
+	setAsyncCallbackHeader(header, c.callbackURL)
+
+	request := events.LambdaFunctionURLRequest{Headers: make(map[string]string), Body: dataString}
+	for name := range header {
+		// function URL requests carry lowercase header names
+		request.Headers[strings.ToLower(name)] = header.Get(name)
+	}
+
+	payload, err := json.Marshal(request)
+	if err != nil {
+		log.Errorf("Failed to create the payload of function %s - %v", function.Name, err)
+
+		record.ResponseTime = time.Since(start).Microseconds()
+		record.ConnectionTimeout = true
+
+		return false, record
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), lambdaRequestTimeout)
+	defer cancel()
+
+	output, err := c.lambda.Invoke(ctx, &lambda.InvokeInput{
+		FunctionName:   aws.String(common.AWSLambdaFunctionName(function)),
+		InvocationType: types.InvocationTypeEvent,
+		Payload:        payload,
+	})
+	record.ResponseTime = time.Since(start).Microseconds()
+	if err != nil {
+		log.Debugf("Event invocation of function %s failed - %v", function.Name, err)
+
+		record.ConnectionTimeout = true
+
+		return false, record
+	}
+
+	requestID, _ := middleware.GetRequestIDMetadata(output.ResultMetadata)
+	if output.StatusCode != http.StatusAccepted || requestID == "" {
+		log.Debugf("Event invocation of function %s returned status code %d and request ID '%s'", function.Name, output.StatusCode, requestID)
+
+		record.FunctionTimeout = true
+
+		return false, record
+	}
+
+	record.AsyncResponseID = requestID
+	record.ActivationID = requestID
+
+	return true, record
+}
+
+// collect looks up the REPORT line of the invocation in the log group of the function. Log events are delivered to
+// CloudWatch with a delay, so the response time is derived from the timestamp of the REPORT line rather than from
+// the time it is found, albeit with a millisecond resolution.
+func (c *lambdaEventClient) collect(record *mc.ExecutionRecord) (bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), lambdaRequestTimeout)
+	defer cancel()
+
+	paginator := cloudwatchlogs.NewFilterLogEventsPaginator(c.logs, &cloudwatchlogs.FilterLogEventsInput{
+		LogGroupName:  aws.String("/aws/lambda/" + common.AWSLambdaFunctionName(&common.Function{Name: record.Function})),
+		FilterPattern: aws.String(fmt.Sprintf(`"REPORT RequestId: %s"`, record.AsyncResponseID)),
+		StartTime:     aws.Int64(record.StartTime/1000 - 1),
+	})
+
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return false, err
+		}
+
+		for _, event := range page.Events {
+			if event.Message == nil || event.Timestamp == nil {
+				continue
+			}
+
+			parseLambdaReport(*event.Message, record)
+			record.ResponseTime = max(*event.Timestamp*1000-record.StartTime, record.ResponseTime)
+
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// parseLambdaReport fills the record with the duration, the memory usage and the init duration of the REPORT line.
+// Lambda reports an init duration only for the invocations that initialized the execution environment.
+func parseLambdaReport(report string, record *mc.ExecutionRecord) {
+	if match := lambdaDurationPattern.FindStringSubmatch(report); match != nil {
+		if duration, err := strconv.ParseFloat(match[1], 64); err == nil {
+			record.ActualDuration = uint32(duration * 1000) //ms to micro sec
+		}
+	}
+	if match := lambdaMaxMemoryPattern.FindStringSubmatch(report); match != nil {
+		if memory, err := strconv.ParseUint(match[1], 10, 32); err == nil {
+			record.ActualMemoryUsage = uint32(memory)
+		}
+	}
+
+	record.StartType = mc.Hot
+	if match := lambdaInitDurationPattern.FindStringSubmatch(report); match != nil {
+		record.StartType = mc.Cold
+		if initDuration, err := strconv.ParseFloat(match[1], 64); err == nil {
+			record.InitTime = int64(initDuration * 1000) //ms to micro sec
+		}
+	}
+
+	// failed invocations, e.g., timeouts, are reported with their status
+	if match := lambdaStatusPattern.FindStringSubmatch(report); match != nil && match[1] != "success" {
+		record.FunctionTimeout = true
+	}
+}
@@ -1,30 +1,48 @@
 package clients
 
 import (
-	"encoding/json"
+	"errors"
 	"fmt"
+	"sync"
+
 	log "github.com/sirupsen/logrus"
 	"github.com/vhive-serverless/loader/pkg/common"
+	"github.com/vhive-serverless/loader/pkg/config"
 	mc "github.com/vhive-serverless/loader/pkg/metric"
-	"io"
-	"sync"
 )
 
+// lambdaRequestIDHeader carries the ID of the Lambda request in the responses of function URLs
+const lambdaRequestIDHeader = "X-Amzn-Requestid"
+
 type awsLambdaInvoker struct {
-	announceDoneExe *sync.WaitGroup
+	// IDs of the execution environments that served an invocation
+	instances sync.Map
+
+	// nil unless functions are invoked asynchronously
+	events *lambdaEventClient
 }
 
-func newAWSLambdaInvoker(announceDoneExe *sync.WaitGroup) *awsLambdaInvoker {
-	return &awsLambdaInvoker{
-		announceDoneExe: announceDoneExe,
+func newAWSLambdaInvoker(cfg *config.LoaderConfiguration) *awsLambdaInvoker {
+	invoker := &awsLambdaInvoker{}
+	if cfg.AWSLambdaInvocationType == common.AWSLambdaInvocationEvent {
+		invoker.events = newLambdaEventClient(cfg.AsyncCallbackURL)
 	}
+
+	return invoker
 }
 
-func (i *awsLambdaInvoker) Invoke(function *common.Function, runtimeSpec *common.RuntimeSpecification) (bool, *mc.ExecutionRecord) {
+func (i *awsLambdaInvoker) Invoke(function *common.Function, runtimeSpec *common.RuntimeSpecification, invocationID string) (bool, *mc.ExecutionRecord) {
 	log.Tracef("(Invoke)\t %s: %d[ms], %d[MiB]", function.Name, runtimeSpec.Runtime, runtimeSpec.Memory)
 
 	dataString := fmt.Sprintf(`{"RuntimeInMilliSec": %d, "MemoryInMebiBytes": %d}`, runtimeSpec.Runtime, runtimeSpec.Memory)
-	success, executionRecordBase, res := httpInvocation(dataString, function, i.announceDoneExe, false)
+	if i.events != nil {
+		success, record := i.events.invokeEvent(function, dataString, invocationID)
+		record.RequestedDuration = uint32(runtimeSpec.Runtime * 1e3)
+
+		return success, record
+	}
+
+	success, executionRecordBase, res, bodyBytes := postInvocation(dataString, function, invocationID)
 
 	executionRecordBase.RequestedDuration = uint32(runtimeSpec.Runtime * 1e3)
 	record := &mc.ExecutionRecord{ExecutionRecordBase: *executionRecordBase}
@@ -33,26 +51,36 @@ func (i *awsLambdaInvoker) Invoke(function *common.Function, runtimeSpec *common
 		return false, record
 	}
 
-	// Read the response body
-	responseBody, err := io.ReadAll(res.Body)
-	if err != nil {
-		log.Debugf("Error reading response body:%s", err)
+	if err := ParseTraceFunctionReply(bodyBytes, record); err != nil {
+		log.Debugf("Error unmarshaling JSON:%s", err)
 		return false, record
 	}
+	i.recordStartType(record)
+	record.ActivationID = res.Header.Get(lambdaRequestIDHeader)
 
-	// Create a variable to store the JSON data
-	var httpResBody HTTPResBody
+	logInvocationSummary(function, &record.ExecutionRecordBase, res)
 
-	// Unmarshal the response body into the JSON object
-	if err := json.Unmarshal(responseBody, &httpResBody); err != nil {
-		log.Debugf("Error unmarshaling JSON:%s", err)
-		return false, record
+	return true, record
+}
+
+// recordStartType marks the first invocation served by an execution environment as a cold start if the function did
+// not report the start type, e.g., for function images predating the cold start flag.
+func (i *awsLambdaInvoker) recordStartType(record *mc.ExecutionRecord) {
+	if record.StartType != "" || record.InstanceID == "" {
+		return
 	}
 
-	record.ActualDuration = httpResBody.DurationInMicroSec
-	record.ActualMemoryUsage = common.Kib2Mib(httpResBody.MemoryUsageInKb)
+	record.StartType = mc.Hot
+	if _, seen := i.instances.LoadOrStore(record.InstanceID, struct{}{}); !seen {
+		record.StartType = mc.Cold
+	}
+}
 
-	logInvocationSummary(function, &record.ExecutionRecordBase, res)
+// Collect completes the record of an Event invocation with the report of its execution.
+func (i *awsLambdaInvoker) Collect(record *mc.ExecutionRecord) (bool, error) {
+	if i.events == nil {
+		return false, errors.New("results can only be collected for Event invocations")
+	}
 
-	return true, record
+	return i.events.collect(record)
 }
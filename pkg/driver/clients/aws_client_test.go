@@ -0,0 +1,192 @@
+package clients
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs"
+	"github.com/aws/aws-sdk-go-v2/service/lambda"
+	"github.com/vhive-serverless/loader/pkg/common"
+	"github.com/vhive-serverless/loader/pkg/config"
+	mc "github.com/vhive-serverless/loader/pkg/metric"
+)
+
+func newFakeCloudFunction(t *testing.T, coldStart bool, timestamps bool) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req map[string]int
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil || r.Method != http.MethodPost ||
+			req["RuntimeInMilliSec"] != testRuntimeSpecs.Runtime {
+
+			t.Errorf("Unexpected request: %s %v", r.Method, req)
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		reply := map[string]any{
+			"DurationInMicroSec": 10_000,
+			"MemoryUsageInKb":    131072,
+			"InstanceID":         "0123456789abcdef",
+		}
+		if timestamps {
+			reply["ReceiveTimestampInMicroSec"] = 1_000_000
+			reply["StartTimestampInMicroSec"] = 1_000_010
+			reply["EndTimestampInMicroSec"] = 1_010_010
+			reply["ColdStart"] = coldStart
+		}
+
+		w.Header().Set(lambdaRequestIDHeader, "8f5a")
+		_ = json.NewEncoder(w).Encode(reply)
+	}))
+}
+
+func TestAWSLambdaInvokerColdStart(t *testing.T) {
+	server := newFakeCloudFunction(t, true, true)
+	defer server.Close()
+
+	cfg := &config.Configuration{LoaderConfiguration: &config.LoaderConfiguration{Platform: common.PlatformAWSLambda}}
+	function := &common.Function{Name: "test-function", Endpoint: server.URL}
+
+	success, record := CreateInvoker(cfg).Invoke(function, &testRuntimeSpecs, "min0.inv0")
+	if !success ||
+		record.Instance != "test-function" ||
+		record.InstanceID != "0123456789abcdef" ||
+		record.StartType != mc.Cold ||
+		record.FunctionReceiveTime != 1_000_000 ||
+		record.ActivationID != "8f5a" ||
+		record.ActualDuration != 10_000 {
+
+		t.Errorf("Unexpected record: %+v", record)
+	}
+}
+
+func TestAWSLambdaInvokerStartTypeFromInstance(t *testing.T) {
+	server := newFakeCloudFunction(t, false, false)
+	defer server.Close()
+
+	cfg := &config.Configuration{LoaderConfiguration: &config.LoaderConfiguration{Platform: common.PlatformAWSLambda}}
+	function := &common.Function{Name: "test-function", Endpoint: server.URL}
+	invoker := CreateInvoker(cfg)
+
+	// without the cold start flag, the first invocation served by an execution environment is a cold start
+	for i, expected := range []mc.StartType{mc.Cold, mc.Hot} {
+		success, record := invoker.Invoke(function, &testRuntimeSpecs, fmt.Sprintf("min0.inv%d", i))
+		if !success || record.StartType != expected || record.FunctionReceiveTime != 0 {
+			t.Errorf("Unexpected record: %+v", record)
+		}
+	}
+}
+
+func TestAzureFunctionsInvokerColdStart(t *testing.T) {
+	server := newFakeCloudFunction(t, true, true)
+	defer server.Close()
+
+	cfg := &config.Configuration{LoaderConfiguration: &config.LoaderConfiguration{Platform: common.PlatformAzureFunctions}}
+	function := &common.Function{Name: "test-function", Endpoint: server.URL}
+
+	success, record := CreateInvoker(cfg).Invoke(function, &testRuntimeSpecs, "min0.inv2")
+	if !success || record.StartType != mc.Cold || record.InstanceID != "0123456789abcdef" || record.FunctionReceiveTime != 1_000_000 {
+		t.Errorf("Unexpected record: %+v", record)
+	}
+}
+
+// newFakeLambdaAPI stands in for the Invoke API of Lambda and the FilterLogEvents API of CloudWatch Logs, which
+// reports the invocation once it has been looked up reportAfter times.
+func newFakeLambdaAPI(t *testing.T, reportAfter int) *httptest.Server {
+	lookups := 0
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Amz-Target") == "Logs_20140328.FilterLogEvents" {
+			var input map[string]any
+			_ = json.NewDecoder(r.Body).Decode(&input)
+			if input["logGroupName"] != "/aws/lambda/trace-func-0" || input["filterPattern"] != `"REPORT RequestId: 8f5a"` {
+				t.Errorf("Unexpected log query %v", input)
+			}
+
+			var events []map[string]any
+			if lookups++; lookups > reportAfter {
+				events = append(events, map[string]any{
+					"timestamp": 1_700_000_000_250,
+					"message": "REPORT RequestId: 8f5a\tDuration: 10.50 ms\tBilled Duration: 11 ms\tMemory Size: 128 MB\t" +
+						"Max Memory Used: 40 MB\tInit Duration: 152.25 ms\t\n",
+				})
+			}
+
+			w.Header().Set("Content-Type", "application/x-amz-json-1.1")
+			_ = json.NewEncoder(w).Encode(map[string]any{"events": events})
+			return
+		}
+
+		var request events.LambdaFunctionURLRequest
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil ||
+			r.URL.Path != "/2015-03-31/functions/trace-func-0/invocations" ||
+			r.Header.Get("X-Amz-Invocation-Type") != "Event" ||
+			request.Headers[common.InvocationIDHeader] != "min0.inv0" {
+
+			t.Errorf("Unexpected invocation %s %v", r.URL.Path, request)
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		w.Header().Set(lambdaRequestIDHeader, "8f5a")
+		w.WriteHeader(http.StatusAccepted)
+	}))
+}
+
+func TestAWSLambdaInvokerEvent(t *testing.T) {
+	server := newFakeLambdaAPI(t, 1)
+	defer server.Close()
+
+	cfg := aws.Config{
+		Region:      common.AwsRegion,
+		Credentials: credentials.NewStaticCredentialsProvider("key", "secret", ""),
+	}
+	endpoint := func(o *lambda.Options) { o.BaseEndpoint = aws.String(server.URL) }
+	logsEndpoint := func(o *cloudwatchlogs.Options) { o.BaseEndpoint = aws.String(server.URL) }
+	invoker := &awsLambdaInvoker{events: &lambdaEventClient{
+		lambda: lambda.NewFromConfig(cfg, endpoint),
+		logs:   cloudwatchlogs.NewFromConfig(cfg, logsEndpoint),
+	}}
+
+	function := &common.Function{Name: "trace-func-0-2642643831809466437"}
+	success, record := invoker.Invoke(function, &testRuntimeSpecs, "min0.inv0")
+	if !success || record.AsyncResponseID != "8f5a" || record.ActivationID != "8f5a" || record.RequestedDuration == 0 {
+		t.Fatalf("Unexpected record of the submission %+v", record)
+	}
+
+	record.Function = function.Name
+	record.StartTime = 1_700_000_000_000_000
+
+	// the report is not written yet
+	if done, err := invoker.Collect(record); done || err != nil {
+		t.Fatalf("The invocation should not be completed yet - %v", err)
+	}
+
+	if done, err := invoker.Collect(record); !done || err != nil {
+		t.Fatalf("The invocation should be completed - %v", err)
+	}
+	if record.ResponseTime != 250_000 ||
+		record.ActualDuration != 10_500 ||
+		record.ActualMemoryUsage != 40 ||
+		record.StartType != mc.Cold ||
+		record.InitTime != 152_250 ||
+		record.FunctionTimeout {
+
+		t.Errorf("Unexpected record of the completion %+v", record)
+	}
+}
+
+func TestParseLambdaReport(t *testing.T) {
+	record := &mc.ExecutionRecord{}
+	parseLambdaReport("REPORT RequestId: 8f5a\tDuration: 3000.00 ms\tBilled Duration: 3000 ms\tMemory Size: 128 MB\t"+
+		"Max Memory Used: 41 MB\tStatus: timeout", record)
+
+	if record.StartType != mc.Hot || record.ActualDuration != 3_000_000 || !record.FunctionTimeout {
+		t.Errorf("Unexpected record %+v", record)
+	}
+}
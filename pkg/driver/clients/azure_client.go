@@ -6,7 +6,6 @@ import (
 	"fmt"
 	"io"
 	"net/http"
-	"sync"
 	"time"
 
 	log "github.com/sirupsen/logrus"
@@ -14,21 +13,17 @@ import (
 	mc "github.com/vhive-serverless/loader/pkg/metric"
 )
 
-type azureFunctionsInvoker struct {
-	announceDoneExe *sync.WaitGroup
-}
+type azureFunctionsInvoker struct{}
 
-func newAzureFunctionsInvoker(announceDoneExe *sync.WaitGroup) *azureFunctionsInvoker {
-	return &azureFunctionsInvoker{
-		announceDoneExe: announceDoneExe,
-	}
+func newAzureFunctionsInvoker() *azureFunctionsInvoker {
+	return &azureFunctionsInvoker{}
 }
 
-func (i *azureFunctionsInvoker) Invoke(function *common.Function, runtimeSpec *common.RuntimeSpecification) (bool, *mc.ExecutionRecord) {
+func (i *azureFunctionsInvoker) Invoke(function *common.Function, runtimeSpec *common.RuntimeSpecification, invocationID string) (bool, *mc.ExecutionRecord) {
 	log.Tracef("(Invoke)\t %s: %d[ms], %d[MiB]", function.Name, runtimeSpec.Runtime, runtimeSpec.Memory)
 
 	dataString := fmt.Sprintf(`{"RuntimeInMilliSec": %d, "MemoryInMebiBytes": %d}`, runtimeSpec.Runtime, runtimeSpec.Memory)
-	success, executionRecordBase, res, bodyBytes := azureHttpInvocation(dataString, function)
+	success, executionRecordBase, res, bodyBytes := postInvocation(dataString, function, invocationID)
 
 	executionRecordBase.RequestedDuration = uint32(runtimeSpec.Runtime * 1e3)
 	record := &mc.ExecutionRecord{ExecutionRecordBase: *executionRecordBase}
@@ -47,14 +42,20 @@ func (i *azureFunctionsInvoker) Invoke(function *common.Function, runtimeSpec *c
 
 	record.ActualDuration = httpResBody.DurationInMicroSec
 	record.ActualMemoryUsage = common.Kib2Mib(httpResBody.MemoryUsageInKb)
+	record.InstanceID = httpResBody.InstanceID
+	recordInstanceTimestamps(record, httpResBody.ReceiveTimestampInMicroSec, httpResBody.StartTimestampInMicroSec,
+		httpResBody.EndTimestampInMicroSec, httpResBody.InstanceUptimeInMicroSec, httpResBody.ColdStart)
+	deriveQueueingTime(record)
 
 	logInvocationSummary(function, &record.ExecutionRecordBase, res)
 
 	return true, record
 }
 
-func azureHttpInvocation(dataString string, function *common.Function) (bool, *mc.ExecutionRecordBase, *http.Response, []byte) {
-	record := &mc.ExecutionRecordBase{}
+// postInvocation sends the JSON payload to the HTTP trigger of a public cloud function (i.e., an AWS Lambda function
+// URL or an Azure Functions HTTP trigger) and returns the response body.
+func postInvocation(dataString string, function *common.Function, invocationID string) (bool, *mc.ExecutionRecordBase, *http.Response, []byte) {
+	record := &mc.ExecutionRecordBase{InvocationID: invocationID}
 
 	start := time.Now()
 	record.StartTime = start.UnixMicro()
@@ -76,6 +77,7 @@ func azureHttpInvocation(dataString string, function *common.Function) (bool, *m
 	}
 
 	req.Header.Set("Content-Type", "application/json") // JSON payload for POST
+	setInvocationHeaders(req.Header, function, invocationID)
 
 	resp, err := http.DefaultClient.Do(req)
 	if err != nil {
@@ -118,7 +120,9 @@ func azureHttpInvocation(dataString string, function *common.Function) (bool, *m
 		return false, record, resp, nil
 	}
 
-	record.Instance = deserializedResponse.Function
+	if deserializedResponse.Function != "" {
+		record.Instance = deserializedResponse.Function
+	}
 	record.ResponseTime = time.Since(start).Microseconds()
 	record.ActualDuration = uint32(deserializedResponse.ExecutionTime)
 
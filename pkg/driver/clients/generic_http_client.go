@@ -0,0 +1,353 @@
+package clients
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/vhive-serverless/loader/pkg/common"
+	"github.com/vhive-serverless/loader/pkg/config"
+	mc "github.com/vhive-serverless/loader/pkg/metric"
+)
+
+// invocationTemplateData is what request templates can reference, e.g. {{.FunctionName}}.
+type invocationTemplateData struct {
+	FunctionName      string
+	Endpoint          string
+	InvocationID      string
+	RuntimeInMilliSec int
+	MemoryInMebiBytes int
+
+	// URL of the callback receiver of the loader, if enabled
+	CallbackURL string
+	// set only when rendering AsyncPollURLTemplate
+	ResponseID string
+}
+
+type genericHTTPInvoker struct {
+	client      *http.Client
+	cfg         *config.GenericHTTPConfig
+	callbackURL string
+	async       bool
+
+	url     *template.Template
+	headers map[string]*template.Template
+	body    *template.Template
+	// nil unless invocations are asynchronous
+	poll *template.Template
+}
+
+func newGenericHTTPInvoker(cfg *config.Configuration) *genericHTTPInvoker {
+	lcfg := cfg.LoaderConfiguration
+	hcfg := cfg.GenericHTTPConfiguration
+
+	invoker := &genericHTTPInvoker{
+		client:      CreateHTTPClient(lcfg.GRPCFunctionTimeoutSeconds, lcfg.InvokeProtocol),
+		cfg:         hcfg,
+		callbackURL: lcfg.AsyncCallbackURL,
+		async:       hcfg.AsyncResponseIDHeader != "" || hcfg.AsyncResponseIDJSONPath != "",
+		url:         mustParseTemplate("url", hcfg.URLTemplate),
+		headers:     make(map[string]*template.Template),
+		body:        mustParseTemplate("body", hcfg.BodyTemplate),
+	}
+
+	for name, value := range hcfg.HeaderTemplates {
+		invoker.headers[name] = mustParseTemplate(name, value)
+	}
+	if hcfg.AsyncPollURLTemplate != "" {
+		invoker.poll = mustParseTemplate("poll", hcfg.AsyncPollURLTemplate)
+	}
+
+	return invoker
+}
+
+func mustParseTemplate(name string, text string) *template.Template {
+	t, err := template.New(name).Option("missingkey=error").Parse(text)
+	if err != nil {
+		log.Fatalf("Failed to parse generic HTTP template '%s' - %v", name, err)
+	}
+
+	return t
+}
+
+func renderTemplate(t *template.Template, data *invocationTemplateData) (string, error) {
+	var buffer bytes.Buffer
+	if err := t.Execute(&buffer, data); err != nil {
+		return "", err
+	}
+
+	return buffer.String(), nil
+}
+
+func (i *genericHTTPInvoker) request(function *common.Function, runtimeSpec *common.RuntimeSpecification, invocationID string) (*http.Request, error) {
+	data := &invocationTemplateData{
+		FunctionName:      function.Name,
+		Endpoint:          function.Endpoint,
+		InvocationID:      invocationID,
+		RuntimeInMilliSec: runtimeSpec.Runtime,
+		MemoryInMebiBytes: runtimeSpec.Memory,
+		CallbackURL:       i.callbackURL,
+	}
+
+	url, err := renderTemplate(i.url, data)
+	if err != nil {
+		return nil, err
+	}
+	body, err := renderTemplate(i.body, data)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest(i.cfg.Method, url, strings.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+
+	setInvocationHeaders(req.Header, function, invocationID)
+	for name, t := range i.headers {
+		value, err := renderTemplate(t, data)
+		if err != nil {
+			return nil, err
+		}
+
+		req.Header.Set(name, value)
+	}
+
+	return req, nil
+}
+
+func (i *genericHTTPInvoker) Invoke(function *common.Function, runtimeSpec *common.RuntimeSpecification, invocationID string) (bool, *mc.ExecutionRecord) {
+	log.Tracef("(Invoke)\t %s: %d[ms], %d[MiB]", function.Name, runtimeSpec.Runtime, runtimeSpec.Memory)
+
+	record := &mc.ExecutionRecord{
+		ExecutionRecordBase: mc.ExecutionRecordBase{
+			InvocationID:      invocationID,
+			RequestedDuration: uint32(runtimeSpec.Runtime * 1e3),
+		},
+	}
+	start := time.Now()
+	record.StartTime = start.UnixMicro()
+	record.Instance = function.Name // may get overwritten
+
+	req, err := i.request(function, runtimeSpec, invocationID)
+	if err != nil {
+		log.Errorf("Failed to create a HTTP request for function %s - %v", function.Name, err)
+
+		record.ResponseTime = time.Since(start).Microseconds()
+		record.ConnectionTimeout = true
+
+		return false, record
+	}
+
+	resp, err := i.client.Do(req)
+	if err != nil {
+		log.Debugf("HTTP request for function %s failed - %v", function.Name, err)
+
+		record.ResponseTime = time.Since(start).Microseconds()
+		record.ConnectionTimeout = true
+
+		return false, record
+	}
+	defer HandleBodyClosing(resp)
+
+	body, err := io.ReadAll(resp.Body)
+	record.ResponseTime = time.Since(start).Microseconds()
+
+	if err != nil || resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		log.Debugf("HTTP request for function %s failed - status code: %d - %v", function.Name, resp.StatusCode, err)
+
+		record.FunctionTimeout = true
+
+		return false, record
+	}
+
+	if i.async {
+		record.AsyncResponseID, err = i.responseID(resp.Header, body)
+		if err != nil {
+			log.Debugf("Failed to read the response ID of function %s - %v", function.Name, err)
+
+			record.FunctionTimeout = true
+
+			return false, record
+		}
+	} else if err = i.parseResponse(body, record); err != nil {
+		log.Warnf("Failed to parse response of function %s - %v", function.Name, err)
+	}
+
+	log.Tracef("(Replied)\t %s: %s", function.Name, string(body))
+	log.Tracef("(E2E Latency) %s: %.2f[ms]\n", function.Name, float64(record.ResponseTime)/1e3)
+
+	return true, record
+}
+
+// responseID reads the ID of an asynchronous invocation from the submission response.
+func (i *genericHTTPInvoker) responseID(header http.Header, body []byte) (string, error) {
+	if i.cfg.AsyncResponseIDHeader != "" {
+		if id := header.Get(i.cfg.AsyncResponseIDHeader); id != "" {
+			return id, nil
+		}
+	}
+
+	if i.cfg.AsyncResponseIDJSONPath != "" {
+		var document any
+		if err := json.Unmarshal(body, &document); err != nil {
+			return "", err
+		}
+
+		value, err := lookupJSONPath(document, i.cfg.AsyncResponseIDJSONPath)
+		if err != nil {
+			return "", err
+		}
+
+		return fmt.Sprint(value), nil
+	}
+
+	return "", fmt.Errorf("missing %s header", i.cfg.AsyncResponseIDHeader)
+}
+
+// Collect polls the result of an asynchronous invocation. The poll URL returns 202 Accepted, 204 No Content or
+// 404 Not Found while the invocation has not completed, and 200 OK with the same body as a synchronous invocation
+// once it has.
+func (i *genericHTTPInvoker) Collect(record *mc.ExecutionRecord) (bool, error) {
+	if i.poll == nil {
+		return false, errors.New("results can only be collected with AsyncPollURLTemplate or the callback receiver")
+	}
+
+	start := time.Now()
+
+	url, err := renderTemplate(i.poll, &invocationTemplateData{
+		FunctionName: record.Function,
+		InvocationID: record.InvocationID,
+		ResponseID:   record.AsyncResponseID,
+	})
+	if err != nil {
+		return false, err
+	}
+
+	resp, err := i.client.Get(url)
+	if err != nil {
+		return false, err
+	}
+	defer HandleBodyClosing(resp)
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return false, err
+	}
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusAccepted, http.StatusNoContent, http.StatusNotFound:
+		return false, nil
+	default:
+		return false, fmt.Errorf("unexpected status code %d", resp.StatusCode)
+	}
+
+	if err = i.parseResponse(body, record); err != nil {
+		log.Warnf("Failed to parse response of function %s - %v", record.Function, err)
+	}
+
+	record.TimeToGetResponseMs = time.Since(start).Microseconds()
+	// completion is only observed when polled, so the response time is an upper bound
+	record.ResponseTime = time.Now().UnixMicro() - record.StartTime
+
+	return true, nil
+}
+
+func (i *genericHTTPInvoker) ParseAsyncResponse(body []byte, record *mc.ExecutionRecord) error {
+	return i.parseResponse(body, record)
+}
+
+// parseResponse extracts the execution time and the instance name from the JSON response body.
+func (i *genericHTTPInvoker) parseResponse(body []byte, record *mc.ExecutionRecord) error {
+	if i.cfg.DurationJSONPath == "" && i.cfg.InstanceJSONPath == "" {
+		return nil
+	}
+
+	var document any
+	if err := json.Unmarshal(body, &document); err != nil {
+		return err
+	}
+
+	if i.cfg.DurationJSONPath != "" {
+		value, err := lookupJSONPath(document, i.cfg.DurationJSONPath)
+		if err != nil {
+			return err
+		}
+
+		duration, err := jsonNumber(value)
+		if err != nil {
+			return fmt.Errorf("value at '%s' - %w", i.cfg.DurationJSONPath, err)
+		}
+		record.ActualDuration = uint32(durationToMicroseconds(duration, i.cfg.DurationUnit))
+	}
+
+	if i.cfg.InstanceJSONPath != "" {
+		value, err := lookupJSONPath(document, i.cfg.InstanceJSONPath)
+		if err != nil {
+			return err
+		}
+
+		record.Instance = fmt.Sprint(value)
+	}
+
+	return nil
+}
+
+// jsonNumber converts a decoded JSON value to a number. Numeric strings are accepted as well,
+// since the protobuf JSON format encodes 64-bit integers as strings.
+func jsonNumber(value any) (float64, error) {
+	switch v := value.(type) {
+	case float64:
+		return v, nil
+	case string:
+		return strconv.ParseFloat(v, 64)
+	default:
+		return 0, fmt.Errorf("%v is not a number", value)
+	}
+}
+
+func durationToMicroseconds(duration float64, unit string) float64 {
+	switch unit {
+	case common.DurationUnitMilliseconds:
+		return duration * 1e3
+	case common.DurationUnitSeconds:
+		return duration * 1e6
+	default:
+		return duration
+	}
+}
+
+// lookupJSONPath resolves a dot-separated path in a decoded JSON document. Numeric path elements index arrays.
+func lookupJSONPath(document any, path string) (any, error) {
+	current := document
+
+	for _, key := range strings.Split(path, ".") {
+		switch node := current.(type) {
+		case map[string]any:
+			value, ok := node[key]
+			if !ok {
+				return nil, fmt.Errorf("key '%s' of path '%s' not found", key, path)
+			}
+			current = value
+		case []any:
+			idx, err := strconv.Atoi(key)
+			if err != nil || idx < 0 || idx >= len(node) {
+				return nil, fmt.Errorf("invalid index '%s' of path '%s'", key, path)
+			}
+			current = node[idx]
+		default:
+			return nil, fmt.Errorf("cannot resolve '%s' of path '%s' in a scalar value", key, path)
+		}
+	}
+
+	return current, nil
+}
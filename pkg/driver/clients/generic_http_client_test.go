@@ -0,0 +1,168 @@
+package clients
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/vhive-serverless/loader/pkg/common"
+	"github.com/vhive-serverless/loader/pkg/config"
+)
+
+func TestGenericHTTPInvoker(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+
+		if r.Method != http.MethodPut ||
+			r.URL.Path != "/function/test-function" ||
+			r.URL.Query().Get("id") != "min1.inv2" ||
+			r.Header.Get("X-Memory") != "128" ||
+			r.Header.Get(common.InvocationIDHeader) != "min1.inv2" ||
+			string(body) != `{"runtime": 10}` {
+
+			t.Errorf("Unexpected request: %s %s, headers: %v, body: %s", r.Method, r.URL, r.Header, string(body))
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"result": map[string]any{
+				"durationMs": 10.5,
+				"pods":       []any{"test-function-00001"},
+			},
+		})
+	}))
+	defer server.Close()
+
+	cfg := &config.Configuration{
+		LoaderConfiguration: &config.LoaderConfiguration{
+			Platform:                   common.PlatformGenericHTTP,
+			InvokeProtocol:             "http1",
+			GRPCFunctionTimeoutSeconds: 5,
+		},
+		GenericHTTPConfiguration: &config.GenericHTTPConfig{
+			URLTemplate:      server.URL + "/function/{{.FunctionName}}?id={{.InvocationID}}",
+			Method:           http.MethodPut,
+			HeaderTemplates:  map[string]string{"X-Memory": "{{.MemoryInMebiBytes}}"},
+			BodyTemplate:     `{"runtime": {{.RuntimeInMilliSec}}}`,
+			DurationJSONPath: "result.durationMs",
+			DurationUnit:     common.DurationUnitMilliseconds,
+			InstanceJSONPath: "result.pods.0",
+		},
+	}
+
+	invoker := CreateInvoker(cfg)
+	success, record := invoker.Invoke(&common.Function{Name: "test-function"}, &testRuntimeSpecs, "min1.inv2")
+
+	if !success ||
+		record.ActualDuration != 10_500 ||
+		record.Instance != "test-function-00001" ||
+		record.InvocationID != "min1.inv2" ||
+		record.ResponseTime == 0 {
+
+		t.Errorf("Unexpected record: %+v", record)
+	}
+}
+
+func TestGenericHTTPInvokerFailure(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	cfg := &config.Configuration{
+		LoaderConfiguration: &config.LoaderConfiguration{
+			Platform:                   common.PlatformGenericHTTP,
+			InvokeProtocol:             "http1",
+			GRPCFunctionTimeoutSeconds: 5,
+		},
+		GenericHTTPConfiguration: &config.GenericHTTPConfig{
+			URLTemplate: server.URL,
+			Method:      http.MethodPost,
+		},
+	}
+
+	success, record := CreateInvoker(cfg).Invoke(&testFunction, &testRuntimeSpecs, "min0.inv0")
+	if success || !record.FunctionTimeout {
+		t.Error("Expected a failed invocation on a non-2xx status code.")
+	}
+}
+
+func TestLookupJSONPath(t *testing.T) {
+	var document any
+	_ = json.Unmarshal([]byte(`{"a": {"b": [1, {"c": "x"}]}}`), &document)
+
+	if value, err := lookupJSONPath(document, "a.b.1.c"); err != nil || value != "x" {
+		t.Errorf("Unexpected value %v - %v", value, err)
+	}
+	if value, err := lookupJSONPath(document, "a.b.0"); err != nil || value != 1.0 {
+		t.Errorf("Unexpected value %v - %v", value, err)
+	}
+
+	for _, path := range []string{"a.d", "a.b.2", "a.b.x", "a.b.0.c"} {
+		if _, err := lookupJSONPath(document, path); err == nil {
+			t.Errorf("Expected an error for path '%s'", path)
+		}
+	}
+}
+
+func TestGenericHTTPInvokerAsync(t *testing.T) {
+	var polls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/async-function/test-function":
+			w.Header().Set("X-Request-Id", "request-42")
+			w.WriteHeader(http.StatusAccepted)
+		case "/results/request-42":
+			if polls.Add(1) < 3 {
+				w.WriteHeader(http.StatusAccepted)
+				return
+			}
+			_ = json.NewEncoder(w).Encode(map[string]any{"durationUs": 1000})
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+		}
+	}))
+	defer server.Close()
+
+	cfg := &config.Configuration{
+		LoaderConfiguration: &config.LoaderConfiguration{
+			Platform:                   common.PlatformGenericHTTP,
+			InvokeProtocol:             "http1",
+			GRPCFunctionTimeoutSeconds: 5,
+		},
+		GenericHTTPConfiguration: &config.GenericHTTPConfig{
+			URLTemplate:           server.URL + "/async-function/{{.FunctionName}}",
+			Method:                http.MethodPost,
+			DurationJSONPath:      "durationUs",
+			DurationUnit:          common.DurationUnitMicroseconds,
+			AsyncResponseIDHeader: "X-Request-Id",
+			AsyncPollURLTemplate:  server.URL + "/results/{{.ResponseID}}",
+		},
+	}
+
+	invoker := CreateInvoker(cfg)
+	success, record := invoker.Invoke(&common.Function{Name: "test-function"}, &testRuntimeSpecs, "min0.inv0")
+	if !success || record.AsyncResponseID != "request-42" || record.ActualDuration != 0 {
+		t.Fatalf("Unexpected record: %+v", record)
+	}
+
+	collector, ok := invoker.(AsyncCollector)
+	if !ok {
+		t.Fatal("Generic HTTP invoker should support asynchronous collection.")
+	}
+
+	for attempt := 1; attempt <= 3; attempt++ {
+		done, err := collector.Collect(record)
+		if err != nil || done != (attempt == 3) {
+			t.Fatalf("Unexpected collection result at attempt %d: %t, %v", attempt, done, err)
+		}
+	}
+
+	if record.ActualDuration != 1000 || record.ResponseTime <= 0 {
+		t.Errorf("Unexpected record: %+v", record)
+	}
+}
@@ -35,6 +35,7 @@ import (
 	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
 	"strings"
 	"time"
 
@@ -68,6 +69,8 @@ func (i ExecutorRPC) Invoke(function *common.Function, runtimeSpec *common.Runti
 
 	record.Instance = extractInstanceName(response.GetMessage())
 	record.ActualDuration = response.DurationInMicroSec
+	recordInstanceTimestamps(record, response.GetReceiveTimestampInMicroSec(), response.GetStartTimestampInMicroSec(),
+		response.GetEndTimestampInMicroSec(), response.GetInstanceUptimeInMicroSec(), response.GetColdStart())
 
 	if strings.HasPrefix(response.GetMessage(), "FAILURE - mem_alloc") {
 		record.MemoryAllocationTimeout = true
@@ -111,20 +114,30 @@ func (i SayHelloRPC) Invoke(function *common.Function, runtimeSpec *common.Runti
 type grpcInvoker struct {
 	cfg     *config.LoaderConfiguration
 	invoker invoker
+
+	// nil when a new connection is established for every invocation
+	pool *grpcConnectionPool
 }
 
 func newGRPCInvoker(cfg *config.LoaderConfiguration, invoker invoker) *grpcInvoker {
-	return &grpcInvoker{
+	i := &grpcInvoker{
 		cfg:     cfg,
 		invoker: invoker,
 	}
+
+	if cfg.GRPCConnectionReuse == common.GRPCConnectionPool {
+		i.pool = newGRPCConnectionPool(cfg.GRPCConnectionPoolSize)
+	}
+
+	return i
 }
 
-func (i *grpcInvoker) Invoke(function *common.Function, runtimeSpec *common.RuntimeSpecification) (bool, *mc.ExecutionRecord) {
+func (i *grpcInvoker) Invoke(function *common.Function, runtimeSpec *common.RuntimeSpecification, invocationID string) (bool, *mc.ExecutionRecord) {
 	logrus.Tracef("(Invoke)\t %s: %d[ms], %d[MiB]", function.Name, runtimeSpec.Runtime, runtimeSpec.Memory)
 
 	record := &mc.ExecutionRecord{
 		ExecutionRecordBase: mc.ExecutionRecordBase{
+			InvocationID:      invocationID,
 			RequestedDuration: uint32(runtimeSpec.Runtime * 1e3),
 		},
 	}
@@ -135,18 +148,9 @@ func (i *grpcInvoker) Invoke(function *common.Function, runtimeSpec *common.Runt
 	start := time.Now()
 	record.StartTime = start.UnixMicro()
 
-	var dialOptions []grpc.DialOption
-	dialOptions = append(dialOptions, grpc.WithTransportCredentials(insecure.NewCredentials()))
-	if strings.Contains(i.cfg.Platform, common.PlatformDirigent) {
-		dialOptions = append(dialOptions, grpc.WithAuthority(function.Name)) // Dirigent specific
-	}
-	if i.cfg.EnableZipkinTracing {
-		dialOptions = append(dialOptions, grpc.WithStatsHandler(otelgrpc.NewClientHandler()))
-	}
-
 	grpcStart := time.Now()
 
-	conn, err := grpc.NewClient("passthrough:///" + function.Endpoint, dialOptions...)
+	conn, err := i.connection(function)
 	if err != nil {
 		logrus.Debugf("Failed to establish a gRPC connection - %v\n", err)
 
@@ -155,17 +159,60 @@ func (i *grpcInvoker) Invoke(function *common.Function, runtimeSpec *common.Runt
 
 		return false, record
 	}
-	defer gRPCConnectionClose(conn)
+	if i.pool == nil {
+		defer gRPCConnectionClose(conn)
+	}
 
 	record.GRPCConnectionEstablishTime = time.Since(grpcStart).Microseconds()
 	executionCxt, cancelExecution := context.WithTimeout(context.Background(), time.Duration(i.cfg.GRPCFunctionTimeoutSeconds)*time.Second)
 	defer cancelExecution()
+	executionCxt = withInvocationMetadata(executionCxt, function, invocationID)
 	success := i.invoker.Invoke(function, runtimeSpec, conn, record, executionCxt)
 	record.ResponseTime = time.Since(start).Microseconds()
+	deriveQueueingTime(record)
 	logrus.Tracef("(E2E Latency) %s: %.2f[ms]\n", function.Name, float64(record.ResponseTime)/1e3)
 	return success, record
 }
 
+// connection returns a pooled connection to the function endpoint, or a new one if pooling is disabled.
+func (i *grpcInvoker) connection(function *common.Function) (*grpc.ClientConn, error) {
+	target := "passthrough:///" + function.Endpoint
+
+	var dialOptions []grpc.DialOption
+	dialOptions = append(dialOptions, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if strings.Contains(i.cfg.Platform, common.PlatformDirigent) {
+		dialOptions = append(dialOptions, grpc.WithAuthority(function.Name)) // Dirigent specific
+	}
+	if i.cfg.EnableZipkinTracing {
+		dialOptions = append(dialOptions, grpc.WithStatsHandler(otelgrpc.NewClientHandler()))
+	}
+
+	if i.pool == nil {
+		return grpc.NewClient(target, dialOptions...)
+	}
+
+	// Dirigent serves all functions from the same endpoint and routes them by authority
+	return i.pool.get(function.Name+"@"+function.Endpoint, target, dialOptions)
+}
+
+// Close releases the pooled connections, if any.
+func (i *grpcInvoker) Close() error {
+	if i.pool == nil {
+		return nil
+	}
+
+	return i.pool.Close()
+}
+
+// withInvocationMetadata attaches the invocation ID and the function name to the outgoing gRPC metadata,
+// so that the function instance can log them and the loader records can be joined with pod logs.
+func withInvocationMetadata(ctx context.Context, function *common.Function, invocationID string) context.Context {
+	return metadata.AppendToOutgoingContext(ctx,
+		common.InvocationIDHeader, invocationID,
+		common.FunctionNameHeader, function.Name,
+	)
+}
+
 func extractInstanceName(data string) string {
 	indexOfHyphen := strings.LastIndex(data, common.FunctionNamePrefix)
 	if indexOfHyphen == -1 {
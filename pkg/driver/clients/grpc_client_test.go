@@ -33,8 +33,11 @@ import (
 	"github.com/sirupsen/logrus"
 	"github.com/vhive-serverless/loader/pkg/common"
 	"github.com/vhive-serverless/loader/pkg/config"
+	mc "github.com/vhive-serverless/loader/pkg/metric"
 	"github.com/vhive-serverless/loader/pkg/workload/standard"
 	"github.com/vhive-serverless/loader/pkg/workload/vswarm"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
 )
 
 func createFakeLoaderConfiguration() *config.LoaderConfiguration {
@@ -72,8 +75,8 @@ func TestGRPCClientWithServerUnreachable(t *testing.T) {
 	cfg := createFakeLoaderConfiguration()
 	cfg.EnableZipkinTracing = true
 
-	invoker := CreateInvoker(&config.Configuration{LoaderConfiguration: cfg}, nil, nil)
-	success, record := invoker.Invoke(&testFunction, &testRuntimeSpecs)
+	invoker := CreateInvoker(&config.Configuration{LoaderConfiguration: cfg})
+	success, record := invoker.Invoke(&testFunction, &testRuntimeSpecs, "min0.inv0")
 
 	if record.Instance != "" ||
 		record.RequestedDuration != uint32(testRuntimeSpecs.Runtime*1000) ||
@@ -89,8 +92,8 @@ func TestGRPCClientWithServerUnreachable(t *testing.T) {
 func TestVSwarmClientUnreachable(t *testing.T) {
 	cfgSwarm := createFakeVSwarmLoaderConfiguration()
 
-	vSwarmInvoker := CreateInvoker(&config.Configuration{LoaderConfiguration: cfgSwarm}, nil, nil)
-	success, record := vSwarmInvoker.Invoke(&testFunction, &testRuntimeSpecs)
+	vSwarmInvoker := CreateInvoker(&config.Configuration{LoaderConfiguration: cfgSwarm})
+	success, record := vSwarmInvoker.Invoke(&testFunction, &testRuntimeSpecs, "min0.inv0")
 
 	if record.Instance != "" ||
 		record.RequestedDuration != uint32(testRuntimeSpecs.Runtime*1000) ||
@@ -113,10 +116,10 @@ func TestGRPCClientWithServerReachable(t *testing.T) {
 	time.Sleep(2 * time.Second)
 
 	cfg := createFakeLoaderConfiguration()
-	invoker := CreateInvoker(&config.Configuration{LoaderConfiguration: cfg}, nil, nil)
+	invoker := CreateInvoker(&config.Configuration{LoaderConfiguration: cfg})
 
 	start := time.Now()
-	success, record := invoker.Invoke(&testFunction, &testRuntimeSpecs)
+	success, record := invoker.Invoke(&testFunction, &testRuntimeSpecs, "min0.inv0")
 	logrus.Info("Elapsed: ", time.Since(start).Milliseconds(), " ms")
 
 	if !success ||
@@ -125,10 +128,36 @@ func TestGRPCClientWithServerReachable(t *testing.T) {
 		record.FunctionTimeout != false ||
 		record.ResponseTime == 0 ||
 		record.ActualDuration == 0 ||
-		record.ActualMemoryUsage == 0 {
+		record.ActualMemoryUsage == 0 ||
+		record.InvocationID != "min0.inv0" {
 
 		t.Error("Failed gRPC invocations for trace function.")
 	}
+
+	if record.FunctionReceiveTime == 0 ||
+		record.FunctionStartTime < record.FunctionReceiveTime ||
+		record.FunctionEndTime < record.FunctionStartTime ||
+		record.StartType == "" ||
+		record.QueueingTime > record.ResponseTime {
+
+		t.Error("Function instance timestamps were not recorded correctly.")
+	}
+}
+
+func TestDeriveQueueingTime(t *testing.T) {
+	record := &mc.ExecutionRecord{}
+	record.ResponseTime = 15_000
+
+	deriveQueueingTime(record)
+	if record.QueueingTime != 0 {
+		t.Error("Queueing time should not be derived without function timestamps.")
+	}
+
+	recordInstanceTimestamps(record, 1_000_000, 1_000_100, 1_010_000, 500, true)
+	deriveQueueingTime(record)
+	if record.QueueingTime != 5_000 || record.StartType != mc.Cold {
+		t.Errorf("Unexpected latency breakdown - queueing time: %d, start type: %s", record.QueueingTime, record.StartType)
+	}
 }
 
 func TestVSwarmClientWithServerReachable(t *testing.T) {
@@ -139,10 +168,10 @@ func TestVSwarmClientWithServerReachable(t *testing.T) {
 	time.Sleep(2 * time.Second)
 
 	cfgSwarm := createFakeVSwarmLoaderConfiguration()
-	vSwarmInvoker := CreateInvoker(&config.Configuration{LoaderConfiguration: cfgSwarm}, nil, nil)
+	vSwarmInvoker := CreateInvoker(&config.Configuration{LoaderConfiguration: cfgSwarm})
 
 	start := time.Now()
-	success, record := vSwarmInvoker.Invoke(&testFunction, &testRuntimeSpecs)
+	success, record := vSwarmInvoker.Invoke(&testFunction, &testRuntimeSpecs, "min0.inv0")
 	logrus.Info("Elapsed: ", time.Since(start).Milliseconds(), " ms")
 	if !success ||
 		record.MemoryAllocationTimeout != false ||
@@ -171,10 +200,10 @@ func TestGRPCClientWithServerBatchWorkload(t *testing.T) {
 
 	cfg := createFakeLoaderConfiguration()
 
-	invoker := CreateInvoker(&config.Configuration{LoaderConfiguration: cfg}, nil, nil)
+	invoker := CreateInvoker(&config.Configuration{LoaderConfiguration: cfg})
 
 	for range 50 {
-		success, record := invoker.Invoke(&testFunction, &testRuntimeSpecs)
+		success, record := invoker.Invoke(&testFunction, &testRuntimeSpecs, "min0.inv0")
 
 		if !success ||
 			record.MemoryAllocationTimeout != false ||
@@ -188,3 +217,72 @@ func TestGRPCClientWithServerBatchWorkload(t *testing.T) {
 		}
 	}
 }
+
+func TestGRPCClientWithConnectionPool(t *testing.T) {
+	address, port := "localhost", 18083
+	testFunction.Endpoint = fmt.Sprintf("%s:%d", address, port)
+
+	go standard.StartGRPCServer(address, port, standard.TraceFunction, "")
+
+	// make sure that the gRPC server is running
+	time.Sleep(2 * time.Second)
+
+	cfg := createFakeLoaderConfiguration()
+	cfg.GRPCConnectionReuse = common.GRPCConnectionPool
+	cfg.GRPCConnectionPoolSize = 2
+
+	invoker := CreateInvoker(&config.Configuration{LoaderConfiguration: cfg})
+	grpcInvoker := invoker.(*grpcInvoker)
+	defer grpcInvoker.Close()
+
+	for range 10 {
+		success, record := invoker.Invoke(&testFunction, &testRuntimeSpecs, "min0.inv0")
+
+		if !success ||
+			record.ConnectionTimeout != false ||
+			record.FunctionTimeout != false ||
+			record.ActualDuration == 0 {
+
+			t.Error("Failed gRPC invocations over pooled connections.")
+		}
+	}
+
+	if len(grpcInvoker.pool.endpoints) != 1 {
+		t.Errorf("Expected connections to a single endpoint, got %d.", len(grpcInvoker.pool.endpoints))
+	}
+}
+
+func TestGRPCConnectionPoolRoundRobin(t *testing.T) {
+	pool := newGRPCConnectionPool(3)
+	defer pool.Close()
+
+	dialOptions := []grpc.DialOption{grpc.WithTransportCredentials(insecure.NewCredentials())}
+
+	var order []*grpc.ClientConn
+	for range 6 {
+		conn, err := pool.get("f@localhost:18084", "passthrough:///localhost:18084", dialOptions)
+		if err != nil {
+			t.Fatal(err)
+		}
+		order = append(order, conn)
+	}
+
+	for i := 0; i < 3; i++ {
+		if order[i] != order[i+3] {
+			t.Error("Pooled connections are not used in a round-robin fashion.")
+		}
+		if order[i] == order[(i+1)%3] {
+			t.Error("Expected distinct connections in the pool.")
+		}
+	}
+
+	other, err := pool.get("g@localhost:18085", "passthrough:///localhost:18085", dialOptions)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, conn := range order {
+		if conn == other {
+			t.Error("Connections must not be shared between endpoints.")
+		}
+	}
+}
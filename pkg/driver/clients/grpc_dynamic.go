@@ -0,0 +1,418 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2023 EASL and the vHive community
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package clients
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"text/template"
+
+	"github.com/sirupsen/logrus"
+	"github.com/vhive-serverless/loader/pkg/common"
+	"github.com/vhive-serverless/loader/pkg/config"
+	mc "github.com/vhive-serverless/loader/pkg/metric"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	reflectionpb "google.golang.org/grpc/reflection/grpc_reflection_v1"
+	reflectionalphapb "google.golang.org/grpc/reflection/grpc_reflection_v1alpha"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+// DynamicRPC invokes arbitrary gRPC methods, building request messages from JSON payload templates.
+type DynamicRPC struct {
+	defaultMethod *dynamicMethod
+	methods       map[string]*dynamicMethod
+
+	// nil if message types are discovered through server reflection
+	files *protoregistry.Files
+	// method descriptors by full method name
+	resolved sync.Map
+	// payload validation errors by method, nil for valid payloads
+	validated sync.Map
+}
+
+// errInvalidPayload marks payload templates not producing a request message of the method.
+var errInvalidPayload = errors.New("invalid payload")
+
+type dynamicMethod struct {
+	cfg        config.DynamicGRPCMethod
+	fullMethod string
+	payload    *template.Template
+}
+
+func newDynamicRPC(cfg *config.DynamicGRPCConfig) *DynamicRPC {
+	rpc := &DynamicRPC{
+		defaultMethod: newDynamicMethod(cfg.Default),
+		methods:       make(map[string]*dynamicMethod),
+	}
+
+	for prefix, method := range cfg.Functions {
+		rpc.methods[prefix] = newDynamicMethod(method)
+	}
+
+	if cfg.DescriptorSetPath != "" {
+		files, err := readDescriptorSet(cfg.DescriptorSetPath)
+		if err != nil {
+			logrus.Fatalf("Failed to read descriptor set %s - %v", cfg.DescriptorSetPath, err)
+		}
+
+		rpc.files = files
+
+		// all message types are known, so invalid methods and payloads fail before the experiment starts
+		if err = rpc.validateMethods(); err != nil {
+			logrus.Fatalf("Invalid dynamic gRPC configuration - %v", err)
+		}
+	}
+
+	return rpc
+}
+
+// validateMethods resolves every method in the descriptor set and validates its payload.
+func (i *DynamicRPC) validateMethods() error {
+	methods := []*dynamicMethod{i.defaultMethod}
+	for _, method := range i.methods {
+		methods = append(methods, method)
+	}
+
+	var errs []error
+	for _, method := range methods {
+		if _, err := i.resolve(context.Background(), nil, method); err != nil {
+			errs = append(errs, fmt.Errorf("method %s - %w", method.cfg.Method, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func newDynamicMethod(cfg config.DynamicGRPCMethod) *dynamicMethod {
+	return &dynamicMethod{
+		cfg:        cfg,
+		fullMethod: "/" + cfg.Method,
+		payload:    mustParseTemplate(cfg.Method, cfg.PayloadTemplate),
+	}
+}
+
+// validate checks that the payload template renders a request message of the method.
+func (m *dynamicMethod) validate(descriptor protoreflect.MethodDescriptor) error {
+	payload, err := renderTemplate(m.payload, &invocationTemplateData{
+		FunctionName:      "function",
+		Endpoint:          "localhost",
+		InvocationID:      "min0.inv0",
+		RuntimeInMilliSec: 1,
+		MemoryInMebiBytes: 1,
+	})
+	if err != nil {
+		return fmt.Errorf("%w - failed to render the payload template - %w", errInvalidPayload, err)
+	}
+
+	if err = protojson.Unmarshal([]byte(payload), dynamicpb.NewMessage(descriptor.Input())); err != nil {
+		return fmt.Errorf("%w - payload does not match %s - %w", errInvalidPayload, descriptor.Input().FullName(), err)
+	}
+
+	return nil
+}
+
+func readDescriptorSet(path string) (*protoregistry.Files, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var set descriptorpb.FileDescriptorSet
+	if err = proto.Unmarshal(data, &set); err != nil {
+		return nil, err
+	}
+
+	return protodesc.NewFiles(&set)
+}
+
+// method returns the method configured for the longest matching function name prefix.
+func (i *DynamicRPC) method(function *common.Function) *dynamicMethod {
+	result, longest := i.defaultMethod, -1
+
+	for prefix, method := range i.methods {
+		if strings.HasPrefix(function.Name, prefix) && len(prefix) > longest {
+			result, longest = method, len(prefix)
+		}
+	}
+
+	return result
+}
+
+func (i *DynamicRPC) Invoke(function *common.Function, runtimeSpec *common.RuntimeSpecification, conn *grpc.ClientConn, record *mc.ExecutionRecord, executionCxt context.Context) bool {
+	method := i.method(function)
+
+	descriptor, err := i.resolve(executionCxt, conn, method)
+	if errors.Is(err, errInvalidPayload) {
+		logrus.Debugf("Invalid payload of gRPC method %s for function %s - %v", method.cfg.Method, function.Name, err)
+
+		// the request never left the loader
+		record.GRPCStatusCode = codes.InvalidArgument.String()
+
+		return false
+	} else if err != nil {
+		logrus.Debugf("Failed to resolve gRPC method %s for function %s - %v", method.cfg.Method, function.Name, err)
+
+		record.ConnectionTimeout = true
+		record.FunctionTimeout = true
+		if s, ok := status.FromError(err); ok {
+			record.GRPCStatusCode = s.Code().String()
+		}
+
+		return false
+	}
+
+	payload, err := renderTemplate(method.payload, &invocationTemplateData{
+		FunctionName:      function.Name,
+		Endpoint:          function.Endpoint,
+		InvocationID:      record.InvocationID,
+		RuntimeInMilliSec: runtimeSpec.Runtime,
+		MemoryInMebiBytes: runtimeSpec.Memory,
+	})
+	if err != nil {
+		logrus.Errorf("Failed to render the payload of function %s - %v", function.Name, err)
+
+		record.GRPCStatusCode = codes.InvalidArgument.String()
+
+		return false
+	}
+
+	request := dynamicpb.NewMessage(descriptor.Input())
+	if err = protojson.Unmarshal([]byte(payload), request); err != nil {
+		logrus.Errorf("Payload of function %s does not match %s - %v", function.Name, descriptor.Input().FullName(), err)
+
+		record.GRPCStatusCode = codes.InvalidArgument.String()
+
+		return false
+	}
+
+	response := dynamicpb.NewMessage(descriptor.Output())
+	if err = conn.Invoke(executionCxt, method.fullMethod, request, response); err != nil {
+		logrus.Debugf("gRPC invocation of function %s failed with status %s - %s", function.Name, status.Code(err), err)
+
+		record.ConnectionTimeout = true // WithBlock deprecated in new gRPC interface
+		record.FunctionTimeout = true
+		record.GRPCStatusCode = status.Code(err).String()
+
+		return false
+	}
+
+	record.Instance = function.Name // may get overwritten
+	if err = parseDynamicResponse(response, &method.cfg, record); err != nil {
+		logrus.Warnf("Failed to parse the response of function %s - %v", function.Name, err)
+	}
+
+	logrus.Tracef("(Replied)\t %s: %v", function.Name, response)
+
+	return true
+}
+
+// resolve returns the descriptor of the method, validating its payload the first time it is resolved.
+func (i *DynamicRPC) resolve(ctx context.Context, conn *grpc.ClientConn, method *dynamicMethod) (protoreflect.MethodDescriptor, error) {
+	descriptor, err := i.resolveDescriptor(ctx, conn, method.cfg.Method)
+	if err != nil {
+		return nil, err
+	}
+
+	validation, ok := i.validated.Load(method)
+	if !ok {
+		err = method.validate(descriptor)
+		if err != nil {
+			logrus.Errorf("Invalid payload of gRPC method %s - %v", method.cfg.Method, err)
+		}
+
+		validation, _ = i.validated.LoadOrStore(method, err)
+	}
+	if err, _ = validation.(error); err != nil {
+		return nil, err
+	}
+
+	return descriptor, nil
+}
+
+// resolveDescriptor returns the descriptor of a method given as "package.Service/Method".
+func (i *DynamicRPC) resolveDescriptor(ctx context.Context, conn *grpc.ClientConn, method string) (protoreflect.MethodDescriptor, error) {
+	if descriptor, ok := i.resolved.Load(method); ok {
+		return descriptor.(protoreflect.MethodDescriptor), nil
+	}
+
+	serviceName, methodName, _ := strings.Cut(method, "/")
+
+	files := i.files
+	if files == nil {
+		var err error
+		if files, err = fetchServiceDescriptors(ctx, conn, serviceName); err != nil {
+			return nil, err
+		}
+	}
+
+	serviceDescriptor, err := files.FindDescriptorByName(protoreflect.FullName(serviceName))
+	if err != nil {
+		return nil, err
+	}
+	service, ok := serviceDescriptor.(protoreflect.ServiceDescriptor)
+	if !ok {
+		return nil, fmt.Errorf("%s is not a service", serviceName)
+	}
+
+	descriptor := service.Methods().ByName(protoreflect.Name(methodName))
+	if descriptor == nil {
+		return nil, fmt.Errorf("service %s has no method %s", serviceName, methodName)
+	}
+	if descriptor.IsStreamingClient() || descriptor.IsStreamingServer() {
+		return nil, fmt.Errorf("streaming method %s is not supported", method)
+	}
+
+	i.resolved.Store(method, descriptor)
+	return descriptor, nil
+}
+
+// fetchServiceDescriptors retrieves the file defining the service and its dependencies through server reflection,
+// falling back to the v1alpha reflection service for servers built with older gRPC versions.
+func fetchServiceDescriptors(ctx context.Context, conn *grpc.ClientConn, serviceName string) (*protoregistry.Files, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	rawFiles, err := fetchFileDescriptors(ctx, conn, serviceName)
+	if status.Code(err) == codes.Unimplemented {
+		rawFiles, err = fetchFileDescriptorsV1Alpha(ctx, conn, serviceName)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	set := &descriptorpb.FileDescriptorSet{}
+	for _, raw := range rawFiles {
+		file := &descriptorpb.FileDescriptorProto{}
+		if err = proto.Unmarshal(raw, file); err != nil {
+			return nil, err
+		}
+
+		set.File = append(set.File, file)
+	}
+
+	return protodesc.NewFiles(set)
+}
+
+func fetchFileDescriptors(ctx context.Context, conn *grpc.ClientConn, symbol string) ([][]byte, error) {
+	stream, err := reflectionpb.NewServerReflectionClient(conn).ServerReflectionInfo(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	err = stream.Send(&reflectionpb.ServerReflectionRequest{
+		MessageRequest: &reflectionpb.ServerReflectionRequest_FileContainingSymbol{FileContainingSymbol: symbol},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := stream.Recv()
+	if err != nil {
+		return nil, err
+	}
+	if e := response.GetErrorResponse(); e != nil {
+		return nil, status.Error(codes.Code(e.GetErrorCode()), e.GetErrorMessage())
+	}
+
+	return response.GetFileDescriptorResponse().GetFileDescriptorProto(), stream.CloseSend()
+}
+
+func fetchFileDescriptorsV1Alpha(ctx context.Context, conn *grpc.ClientConn, symbol string) ([][]byte, error) {
+	stream, err := reflectionalphapb.NewServerReflectionClient(conn).ServerReflectionInfo(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	err = stream.Send(&reflectionalphapb.ServerReflectionRequest{
+		MessageRequest: &reflectionalphapb.ServerReflectionRequest_FileContainingSymbol{FileContainingSymbol: symbol},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := stream.Recv()
+	if err != nil {
+		return nil, err
+	}
+	if e := response.GetErrorResponse(); e != nil {
+		return nil, status.Error(codes.Code(e.GetErrorCode()), e.GetErrorMessage())
+	}
+
+	return response.GetFileDescriptorResponse().GetFileDescriptorProto(), stream.CloseSend()
+}
+
+// parseDynamicResponse extracts the execution time and the instance name from the response message.
+func parseDynamicResponse(response proto.Message, cfg *config.DynamicGRPCMethod, record *mc.ExecutionRecord) error {
+	if cfg.DurationJSONPath == "" && cfg.InstanceJSONPath == "" {
+		return nil
+	}
+
+	data, err := protojson.MarshalOptions{EmitUnpopulated: true}.Marshal(response)
+	if err != nil {
+		return err
+	}
+
+	var document any
+	if err = json.Unmarshal(data, &document); err != nil {
+		return err
+	}
+
+	if cfg.DurationJSONPath != "" {
+		value, err := lookupJSONPath(document, cfg.DurationJSONPath)
+		if err != nil {
+			return err
+		}
+
+		duration, err := jsonNumber(value)
+		if err != nil {
+			return fmt.Errorf("value at '%s' - %w", cfg.DurationJSONPath, err)
+		}
+		record.ActualDuration = uint32(durationToMicroseconds(duration, cfg.DurationUnit))
+	}
+
+	if cfg.InstanceJSONPath != "" {
+		value, err := lookupJSONPath(document, cfg.InstanceJSONPath)
+		if err != nil {
+			return err
+		}
+
+		record.Instance = fmt.Sprint(value)
+	}
+
+	return nil
+}
@@ -0,0 +1,170 @@
+package clients
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/vhive-serverless/loader/pkg/common"
+	"github.com/vhive-serverless/loader/pkg/config"
+	_ "github.com/vhive-serverless/loader/pkg/workload/proto"
+	"github.com/vhive-serverless/loader/pkg/workload/standard"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+func createDynamicGRPCConfiguration() *config.DynamicGRPCConfig {
+	return &config.DynamicGRPCConfig{
+		Default: config.DynamicGRPCMethod{
+			Method:           "faas.Executor/Execute",
+			PayloadTemplate:  `{"message": "{{.InvocationID}}", "runtimeInMilliSec": {{.RuntimeInMilliSec}}, "memoryInMebiBytes": {{.MemoryInMebiBytes}}}`,
+			DurationJSONPath: "durationInMicroSec",
+			DurationUnit:     common.DurationUnitMicroseconds,
+			InstanceJSONPath: "message",
+		},
+	}
+}
+
+// addInvalidDynamicGRPCMethods adds methods failing to resolve or to build their request.
+func addInvalidDynamicGRPCMethods(dynamicCfg *config.DynamicGRPCConfig) {
+	dynamicCfg.Functions = map[string]config.DynamicGRPCMethod{
+		"unknown-": {Method: "faas.Executor/Unknown", PayloadTemplate: "{}"},
+		"invalid-": {Method: "faas.Executor/Execute", PayloadTemplate: `{"unknownField": 1}`},
+	}
+}
+
+func invokeDynamicGRPC(t *testing.T, dynamicCfg *config.DynamicGRPCConfig, function *common.Function) Invoker {
+	cfg := createFakeLoaderConfiguration()
+	cfg.EnableZipkinTracing = false
+
+	invoker := CreateInvoker(&config.Configuration{LoaderConfiguration: cfg, DynamicGRPCConfiguration: dynamicCfg})
+	success, record := invoker.Invoke(function, &testRuntimeSpecs, "min0.inv0")
+
+	if !success ||
+		record.ConnectionTimeout != false ||
+		record.FunctionTimeout != false ||
+		record.ActualDuration == 0 ||
+		record.ResponseTime == 0 {
+
+		t.Errorf("Failed dynamic gRPC invocation - %+v", record)
+	}
+
+	success, record = invoker.Invoke(&common.Function{Name: "test-function", Endpoint: "localhost:1"}, &testRuntimeSpecs, "min0.inv1")
+	if success || !record.FunctionTimeout || record.GRPCStatusCode != "Unavailable" {
+		t.Errorf("Invocation of an unreachable function should fail with status Unavailable, got %s.", record.GRPCStatusCode)
+	}
+
+	return invoker
+}
+
+func TestDynamicGRPCWithReflection(t *testing.T) {
+	address, port := "localhost", 18086
+	function := &common.Function{Name: "test-function", Endpoint: fmt.Sprintf("%s:%d", address, port)}
+
+	go standard.StartGRPCServer(address, port, standard.TraceFunction, "")
+	time.Sleep(2 * time.Second)
+
+	dynamicCfg := createDynamicGRPCConfiguration()
+	addInvalidDynamicGRPCMethods(dynamicCfg)
+	invoker := invokeDynamicGRPC(t, dynamicCfg, function)
+
+	success, record := invoker.Invoke(&common.Function{Name: "unknown-function", Endpoint: function.Endpoint}, &testRuntimeSpecs, "min0.inv2")
+	if success || !record.FunctionTimeout {
+		t.Error("Invocation of an unknown method should fail.")
+	}
+
+	// invalid payloads are reported without timeouts, as the request never reaches the function
+	for _, invocationID := range []string{"min0.inv3", "min0.inv4"} {
+		success, record = invoker.Invoke(&common.Function{Name: "invalid-function", Endpoint: function.Endpoint}, &testRuntimeSpecs, invocationID)
+		if success || record.ConnectionTimeout || record.FunctionTimeout || record.GRPCStatusCode != "InvalidArgument" {
+			t.Errorf("Invocation with a payload not matching the request message should fail with status InvalidArgument - %+v", record)
+		}
+	}
+}
+
+func TestDynamicGRPCWithDescriptorSet(t *testing.T) {
+	address, port := "localhost", 18087
+	function := &common.Function{Name: "test-function", Endpoint: fmt.Sprintf("%s:%d", address, port)}
+
+	go standard.StartGRPCServer(address, port, standard.TraceFunction, "")
+	time.Sleep(2 * time.Second)
+
+	dynamicCfg := createDynamicGRPCConfiguration()
+	dynamicCfg.DescriptorSetPath = writeFaaSDescriptorSet(t)
+
+	invokeDynamicGRPC(t, dynamicCfg, function)
+}
+
+func writeFaaSDescriptorSet(t *testing.T) string {
+	file, err := protoregistry.GlobalFiles.FindFileByPath("server/faas.proto")
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := proto.Marshal(&descriptorpb.FileDescriptorSet{
+		File: []*descriptorpb.FileDescriptorProto{protodesc.ToFileDescriptorProto(file)},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	path := filepath.Join(t.TempDir(), "faas.protoset")
+	if err = os.WriteFile(path, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	return path
+}
+
+func TestDynamicRPCValidateMethods(t *testing.T) {
+	dynamicCfg := createDynamicGRPCConfiguration()
+	addInvalidDynamicGRPCMethods(dynamicCfg)
+	rpc := newDynamicRPC(dynamicCfg)
+
+	files, err := readDescriptorSet(writeFaaSDescriptorSet(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	rpc.files = files
+
+	// the methods of a descriptor set are validated without connecting to the functions
+	err = rpc.validateMethods()
+	if err == nil {
+		t.Fatal("Unknown methods and invalid payloads should fail the validation.")
+	}
+	for _, expected := range []string{"has no method Unknown", "does not match faas.FaasRequest"} {
+		if !strings.Contains(err.Error(), expected) {
+			t.Errorf("Validation error should contain '%s', got %v", expected, err)
+		}
+	}
+
+	rpc = newDynamicRPC(createDynamicGRPCConfiguration())
+	rpc.files = files
+	if err = rpc.validateMethods(); err != nil {
+		t.Errorf("Valid methods should pass the validation - %v", err)
+	}
+}
+
+func TestDynamicRPCMethodSelection(t *testing.T) {
+	rpc := newDynamicRPC(&config.DynamicGRPCConfig{
+		Default: config.DynamicGRPCMethod{Method: "a.A/Default", PayloadTemplate: "{}"},
+		Functions: map[string]config.DynamicGRPCMethod{
+			"image-":        {Method: "a.A/Image", PayloadTemplate: "{}"},
+			"image-rotate-": {Method: "a.A/Rotate", PayloadTemplate: "{}"},
+		},
+	})
+
+	for name, expected := range map[string]string{
+		"image-rotate-1-42": "a.A/Rotate",
+		"image-resize-2-42": "a.A/Image",
+		"trace-func-3":      "a.A/Default",
+	} {
+		if method := rpc.method(&common.Function{Name: name}); method.cfg.Method != expected {
+			t.Errorf("Function %s should use %s, got %s", name, expected, method.cfg.Method)
+		}
+	}
+}
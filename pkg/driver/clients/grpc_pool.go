@@ -0,0 +1,105 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2023 EASL and the vHive community
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package clients
+
+import (
+	"sync"
+	"sync/atomic"
+
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+)
+
+// grpcConnectionPool keeps a fixed number of client connections per function endpoint and hands them out
+// in a round-robin fashion, so that invocations do not pay for establishing a new connection.
+type grpcConnectionPool struct {
+	size int
+
+	mutex     sync.Mutex
+	endpoints map[string]*pooledEndpoint
+}
+
+type pooledEndpoint struct {
+	connections []*grpc.ClientConn
+	next        atomic.Uint64
+}
+
+func newGRPCConnectionPool(size int) *grpcConnectionPool {
+	if size <= 0 {
+		size = 1
+	}
+
+	return &grpcConnectionPool{
+		size:      size,
+		endpoints: make(map[string]*pooledEndpoint),
+	}
+}
+
+// get returns the next connection to the given target. Connections are created on first use of the key with
+// the provided dial options, which must not change for the same key.
+func (p *grpcConnectionPool) get(key string, target string, dialOptions []grpc.DialOption) (*grpc.ClientConn, error) {
+	p.mutex.Lock()
+	endpoint, ok := p.endpoints[key]
+	if !ok {
+		endpoint = &pooledEndpoint{connections: make([]*grpc.ClientConn, 0, p.size)}
+
+		for i := 0; i < p.size; i++ {
+			conn, err := grpc.NewClient(target, dialOptions...)
+			if err != nil {
+				p.mutex.Unlock()
+
+				for _, c := range endpoint.connections {
+					gRPCConnectionClose(c)
+				}
+				return nil, err
+			}
+
+			endpoint.connections = append(endpoint.connections, conn)
+		}
+
+		p.endpoints[key] = endpoint
+	}
+	p.mutex.Unlock()
+
+	idx := (endpoint.next.Add(1) - 1) % uint64(len(endpoint.connections))
+	return endpoint.connections[idx], nil
+}
+
+// Close closes all pooled connections. The pool must not be used afterwards.
+func (p *grpcConnectionPool) Close() error {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	for key, endpoint := range p.endpoints {
+		for _, conn := range endpoint.connections {
+			gRPCConnectionClose(conn)
+		}
+
+		delete(p.endpoints, key)
+	}
+
+	logrus.Debug("Closed all pooled gRPC connections.")
+	return nil
+}
@@ -11,6 +11,7 @@ import (
 	mc "github.com/vhive-serverless/loader/pkg/metric"
 	"io"
 	"mime/multipart"
+	"net"
 	"net/http"
 	"os"
 	"strconv"
@@ -27,6 +28,7 @@ type FunctionResponse struct {
 
 type httpInvoker struct {
 	client      *http.Client
+	asyncClient *http.Client
 	loaderCfg   *config.LoaderConfiguration
 	dirigentCfg *config.DirigentConfig
 
@@ -41,6 +43,7 @@ func newHTTPInvoker(cfg *config.Configuration) *httpInvoker {
 
 	return &httpInvoker{
 		client:      CreateHTTPClient(lcfg.GRPCFunctionTimeoutSeconds, lcfg.InvokeProtocol),
+		asyncClient: newAsyncResponseClient(),
 		loaderCfg:   lcfg,
 		dirigentCfg: dcfg,
 
@@ -160,11 +163,12 @@ func (i *httpInvoker) workflowInvocationRequest(wf *common.Function) *http.Reque
 	return req
 }
 
-func (i *httpInvoker) Invoke(function *common.Function, runtimeSpec *common.RuntimeSpecification) (bool, *mc.ExecutionRecord) {
+func (i *httpInvoker) Invoke(function *common.Function, runtimeSpec *common.RuntimeSpecification, invocationID string) (bool, *mc.ExecutionRecord) {
 	log.Tracef("(Invoke)\t %s: %d[ms], %d[MiB]", function.Name, runtimeSpec.Runtime, runtimeSpec.Memory)
 
 	record := &mc.ExecutionRecord{
 		ExecutionRecordBase: mc.ExecutionRecordBase{
+			InvocationID:      invocationID,
 			RequestedDuration: uint32(runtimeSpec.Runtime * 1e3),
 		},
 	}
@@ -187,6 +191,10 @@ func (i *httpInvoker) Invoke(function *common.Function, runtimeSpec *common.Runt
 		record.ConnectionTimeout = true
 		return false, record
 	}
+	setInvocationHeaders(req.Header, function, invocationID)
+	if i.dirigentCfg.AsyncMode {
+		setAsyncCallbackHeader(req.Header, i.loaderCfg.AsyncCallbackURL)
+	}
 
 	// send request
 	resp, err := i.client.Do(req)
@@ -260,6 +268,74 @@ func DeserializeDirigentResponse(body []byte, record *mc.ExecutionRecord) error
 	return nil
 }
 
+func newAsyncResponseClient() *http.Client {
+	return &http.Client{
+		Timeout: 5 * time.Second,
+		Transport: &http.Transport{
+			DialContext: (&net.Dialer{
+				Timeout: 2 * time.Second,
+			}).DialContext,
+			IdleConnTimeout:     time.Second,
+			MaxIdleConns:        50,
+			MaxIdleConnsPerHost: 50,
+		},
+	}
+}
+
+// Collect fetches the response of an asynchronous Dirigent invocation. Dirigent replies with an empty body while
+// the invocation has not completed.
+func (i *httpInvoker) Collect(record *mc.ExecutionRecord) (bool, error) {
+	start := time.Now()
+
+	req, err := http.NewRequest("GET", "http://"+i.dirigentCfg.AsyncResponseURL, bytes.NewReader([]byte(record.AsyncResponseID)))
+	if err != nil {
+		return false, err
+	}
+
+	// TODO: set function name for load-balancing purpose
+	//req.Header.Set("function", function.Name)
+
+	resp, err := i.asyncClient.Do(req)
+	if err != nil {
+		return false, err
+	}
+
+	defer HandleBodyClosing(resp)
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return false, err
+	}
+	if len(body) == 0 {
+		return false, nil
+	}
+
+	err = DeserializeDirigentResponse(body, record)
+	if err != nil {
+		log.Errorf("Failed to deserialize Dirigent response - %v - %v", string(body), err)
+	}
+
+	e2e := 0
+	if hdr := resp.Header.Get("Duration-Microseconds"); hdr != "" {
+		e2e, err = strconv.Atoi(hdr)
+		if err != nil {
+			log.Errorf("Failed to parse end-to-end latency for %s - %v", record.AsyncResponseID, err)
+		}
+	}
+
+	// loader send request + request e2e + loader get response
+	timeToFetchResponse := time.Since(start).Microseconds()
+	record.UserCodeExecutionMs = int64(e2e)
+	record.TimeToGetResponseMs = timeToFetchResponse
+	record.ResponseTime += int64(e2e)
+	record.ResponseTime += timeToFetchResponse
+
+	return true, nil
+}
+
+func (i *httpInvoker) ParseAsyncResponse(body []byte, record *mc.ExecutionRecord) error {
+	return DeserializeDirigentResponse(body, record)
+}
+
 func HandleBodyClosing(response *http.Response) {
 	if response == nil || response.Body == nil {
 		return
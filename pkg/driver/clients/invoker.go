@@ -1,8 +1,8 @@
 package clients
 
 import (
+	"net/http"
 	"strings"
-	"sync"
 
 	"github.com/sirupsen/logrus"
 	"github.com/vhive-serverless/loader/pkg/common"
@@ -11,27 +11,33 @@ import (
 )
 
 type Invoker interface {
-	Invoke(*common.Function, *common.RuntimeSpecification) (bool, *metric.ExecutionRecord)
+	// Invoke issues a single invocation. The invocation ID is forwarded to the function instance
+	// and stored in the returned record.
+	Invoke(function *common.Function, runtimeSpec *common.RuntimeSpecification, invocationID string) (bool, *metric.ExecutionRecord)
 }
 
-func CreateInvoker(cfg *config.Configuration, announceDoneExe *sync.WaitGroup, readOpenWhiskMetadata *sync.Mutex) Invoker {
+func CreateInvoker(cfg *config.Configuration) Invoker {
 	switch strings.ToLower(cfg.LoaderConfiguration.Platform) {
 	case common.PlatformAWSLambda:
-		return newAWSLambdaInvoker(announceDoneExe)
+		return newAWSLambdaInvoker(cfg.LoaderConfiguration)
 	case common.PlatformAzureFunctions:
-		return newAzureFunctionsInvoker(announceDoneExe)
+		return newAzureFunctionsInvoker()
 	case common.PlatformDirigent:
 		if cfg.DirigentConfiguration == nil {
 			logrus.Fatal("Failed to create invoker: dirigent configuration is required for platform 'dirigent'")
 		}
 		if strings.ToLower(cfg.DirigentConfiguration.Backend) == common.BackendDandelion || cfg.LoaderConfiguration.InvokeProtocol != "grpc" {
 			return newHTTPInvoker(cfg)
+		} else if cfg.DynamicGRPCConfiguration != nil {
+			return newGRPCInvoker(cfg.LoaderConfiguration, newDynamicRPC(cfg.DynamicGRPCConfiguration))
 		} else {
 			return newGRPCInvoker(cfg.LoaderConfiguration, ExecutorRPC{})
 		}
 	case common.PlatformKnative:
 		if cfg.LoaderConfiguration.InvokeProtocol == "grpc" {
-			if !cfg.LoaderConfiguration.VSwarm {
+			if cfg.DynamicGRPCConfiguration != nil {
+				return newGRPCInvoker(cfg.LoaderConfiguration, newDynamicRPC(cfg.DynamicGRPCConfiguration))
+			} else if !cfg.LoaderConfiguration.VSwarm {
 				return newGRPCInvoker(cfg.LoaderConfiguration, ExecutorRPC{})
 			} else {
 				return newGRPCInvoker(cfg.LoaderConfiguration, SayHelloRPC{})
@@ -40,10 +46,73 @@ func CreateInvoker(cfg *config.Configuration, announceDoneExe *sync.WaitGroup, r
 			return newHTTPInvoker(cfg)
 		}
 	case common.PlatformOpenWhisk:
-		return newOpenWhiskInvoker(announceDoneExe, readOpenWhiskMetadata)
+		return newOpenWhiskInvoker()
+	case common.PlatformOpenFaaS:
+		return newOpenFaaSInvoker(cfg.LoaderConfiguration)
+	case common.PlatformFission:
+		return newTraceFunctionHTTPInvoker(cfg.LoaderConfiguration, nil)
+	case common.PlatformGenericHTTP:
+		if cfg.GenericHTTPConfiguration == nil {
+			logrus.Fatal("Failed to create invoker: generic HTTP configuration is required for platform 'generichttp'")
+		}
+		return newGenericHTTPInvoker(cfg)
+	case common.PlatformSimulated:
+		if cfg.SimulatedConfiguration == nil {
+			logrus.Fatal("Failed to create invoker: simulated configuration is required for platform 'simulated'")
+		}
+		return newSimulatedInvoker(cfg)
+	case common.PlatformLocal:
+		// the local processes serve the trace function, so only its own protocol is supported
+		if cfg.LoaderConfiguration.InvokeProtocol == "grpc" {
+			return newGRPCInvoker(cfg.LoaderConfiguration, ExecutorRPC{})
+		}
+		return newTraceFunctionHTTPInvoker(cfg.LoaderConfiguration, nil)
 	default:
 		logrus.Fatal("Unsupported platform.")
 	}
 
 	return nil
 }
+
+// setInvocationHeaders forwards the invocation ID and the function name to the function instance,
+// which echoes them back and includes them in its logs.
+func setInvocationHeaders(header http.Header, function *common.Function, invocationID string) {
+	header.Set(common.InvocationIDHeader, invocationID)
+	header.Set(common.FunctionNameHeader, function.Name)
+}
+
+// setAsyncCallbackHeader forwards the URL of the callback receiver of the loader, if enabled, to the function
+// instance, which POSTs its reply there once an asynchronous invocation completes.
+func setAsyncCallbackHeader(header http.Header, callbackURL string) {
+	if callbackURL != "" {
+		header.Set(common.AsyncCallbackURLHeader, callbackURL)
+	}
+}
+
+// recordInstanceTimestamps stores the timestamps and the cold start flag reported by the function instance.
+// Function images that predate these fields report zeros, in which case the record is left untouched.
+func recordInstanceTimestamps(record *metric.ExecutionRecord, receive int64, start int64, end int64, uptime int64, coldStart bool) {
+	if receive == 0 {
+		return
+	}
+
+	record.FunctionReceiveTime = receive
+	record.FunctionStartTime = start
+	record.FunctionEndTime = end
+	record.InstanceUptime = uptime
+
+	record.StartType = metric.Hot
+	if coldStart {
+		record.StartType = metric.Cold
+	}
+}
+
+// deriveQueueingTime computes the part of the response time spent outside the function instance. Both
+// durations are measured on a single clock each, so the loader and the function need not be synchronized.
+func deriveQueueingTime(record *metric.ExecutionRecord) {
+	if record.FunctionReceiveTime == 0 || record.FunctionEndTime < record.FunctionReceiveTime {
+		return
+	}
+
+	record.QueueingTime = max(0, record.ResponseTime-(record.FunctionEndTime-record.FunctionReceiveTime))
+}
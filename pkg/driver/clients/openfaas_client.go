@@ -0,0 +1,35 @@
+package clients
+
+import (
+	"net/http"
+	"strconv"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/vhive-serverless/loader/pkg/common"
+	"github.com/vhive-serverless/loader/pkg/config"
+	mc "github.com/vhive-serverless/loader/pkg/metric"
+)
+
+const (
+	openFaaSDurationHeader = "X-Duration-Seconds"
+	openFaaSCallIDHeader   = "X-Call-Id"
+)
+
+func newOpenFaaSInvoker(cfg *config.LoaderConfiguration) *traceFunctionHTTPInvoker {
+	return newTraceFunctionHTTPInvoker(cfg, recordOpenFaaSHeaders)
+}
+
+// recordOpenFaaSHeaders stores the call ID and the upstream duration the OpenFaaS gateway attaches to responses.
+func recordOpenFaaSHeaders(header http.Header, record *mc.ExecutionRecord) {
+	record.CallID = header.Get(openFaaSCallIDHeader)
+
+	if value := header.Get(openFaaSDurationHeader); value != "" {
+		seconds, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			log.Debugf("Invalid %s header '%s' - %v", openFaaSDurationHeader, value, err)
+			return
+		}
+
+		record.GatewayDuration = int64(seconds * common.OneSecondInMicroseconds)
+	}
+}
@@ -0,0 +1,78 @@
+package clients
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/vhive-serverless/loader/pkg/common"
+	"github.com/vhive-serverless/loader/pkg/config"
+	mc "github.com/vhive-serverless/loader/pkg/metric"
+)
+
+func TestOpenFaaSInvoker(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req map[string]int
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil ||
+			r.URL.Path != "/function/test-function" ||
+			r.Header.Get(common.InvocationIDHeader) != "min0.inv3" ||
+			req["RuntimeInMilliSec"] != testRuntimeSpecs.Runtime {
+
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		w.Header().Set("X-Call-Id", "5e1b7c4f-call")
+		w.Header().Set("X-Duration-Seconds", "0.012500")
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"DurationInMicroSec":         10_000,
+			"MemoryUsageInKb":            131072,
+			"ReceiveTimestampInMicroSec": 1_000_000,
+			"StartTimestampInMicroSec":   1_000_010,
+			"EndTimestampInMicroSec":     1_010_010,
+			"ColdStart":                  true,
+		})
+	}))
+	defer server.Close()
+
+	cfg := &config.Configuration{LoaderConfiguration: &config.LoaderConfiguration{
+		Platform:                   common.PlatformOpenFaaS,
+		InvokeProtocol:             "http1",
+		GRPCFunctionTimeoutSeconds: 5,
+	}}
+	function := &common.Function{Name: "test-function", Endpoint: server.URL + "/function/test-function"}
+
+	success, record := CreateInvoker(cfg).Invoke(function, &testRuntimeSpecs, "min0.inv3")
+
+	if !success ||
+		record.CallID != "5e1b7c4f-call" ||
+		record.GatewayDuration != 12_500 ||
+		record.ActualDuration != 10_000 ||
+		record.ActualMemoryUsage != 128 ||
+		record.StartType != mc.Cold ||
+		record.InvocationID != "min0.inv3" {
+
+		t.Errorf("Unexpected record: %+v", record)
+	}
+}
+
+func TestOpenFaaSInvokerFailure(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Call-Id", "failed-call")
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer server.Close()
+
+	cfg := &config.Configuration{LoaderConfiguration: &config.LoaderConfiguration{
+		Platform:                   common.PlatformOpenFaaS,
+		InvokeProtocol:             "http1",
+		GRPCFunctionTimeoutSeconds: 5,
+	}}
+	function := &common.Function{Name: "test-function", Endpoint: server.URL}
+
+	success, record := CreateInvoker(cfg).Invoke(function, &testRuntimeSpecs, "min0.inv4")
+	if success || !record.FunctionTimeout || record.CallID != "failed-call" {
+		t.Errorf("Unexpected record: %+v", record)
+	}
+}
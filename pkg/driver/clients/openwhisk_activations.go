@@ -0,0 +1,232 @@
+package clients
+
+import (
+	"bufio"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	mc "github.com/vhive-serverless/loader/pkg/metric"
+)
+
+// maximum page size of the activation list API
+const openWhiskActivationPageSize = 200
+
+type ActivationMetadata struct {
+	Duration  uint32 //ms
+	StartType mc.StartType
+	WaitTime  int64 //ms
+	InitTime  int64 //ms
+}
+
+type openWhiskActivation struct {
+	ActivationID string `json:"activationId"`
+	Duration     uint32 `json:"duration"`
+	Annotations  []struct {
+		Key   string `json:"key"`
+		Value any    `json:"value"`
+	} `json:"annotations"`
+}
+
+func (a *openWhiskActivation) metadata() ActivationMetadata {
+	result := ActivationMetadata{
+		Duration:  a.Duration,
+		StartType: mc.Hot,
+	}
+
+	for _, annotation := range a.Annotations {
+		value, ok := annotation.Value.(float64)
+		if !ok {
+			continue
+		}
+
+		switch annotation.Key {
+		case "waitTime":
+			result.WaitTime = int64(value)
+		case "initTime":
+			// only activations that initialized a container carry this annotation
+			result.StartType = mc.Cold
+			result.InitTime = int64(value)
+		}
+	}
+
+	return result
+}
+
+// OpenWhiskActivationClient reads activation records through the REST API of OpenWhisk.
+type OpenWhiskActivationClient struct {
+	apiHost   string
+	namespace string
+	auth      string
+	client    *http.Client
+}
+
+func NewOpenWhiskActivationClient(apiHost string, namespace string, auth string) *OpenWhiskActivationClient {
+	if !strings.HasPrefix(apiHost, "http://") && !strings.HasPrefix(apiHost, "https://") {
+		apiHost = "https://" + apiHost
+	}
+	if namespace == "" {
+		// resolved to the namespace of the authentication key
+		namespace = "_"
+	}
+
+	return &OpenWhiskActivationClient{
+		apiHost:   strings.TrimSuffix(apiHost, "/"),
+		namespace: namespace,
+		auth:      auth,
+		client: &http.Client{
+			Timeout: 30 * time.Second,
+			// OpenWhisk deployments use self-signed certificates, as with 'wsk -i'
+			Transport: &http.Transport{TLSClientConfig: &tls.Config{InsecureSkipVerify: true}},
+		},
+	}
+}
+
+// NewOpenWhiskActivationClientFromProperties uses the API host, namespace and authentication key of the wsk CLI,
+// i.e., the file pointed by WSK_CONFIG_FILE or ~/.wskprops.
+func NewOpenWhiskActivationClientFromProperties() (*OpenWhiskActivationClient, error) {
+	path := os.Getenv("WSK_CONFIG_FILE")
+	if path == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return nil, err
+		}
+		path = filepath.Join(home, ".wskprops")
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	properties := make(map[string]string)
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		key, value, found := strings.Cut(strings.TrimSpace(scanner.Text()), "=")
+		if found {
+			properties[key] = value
+		}
+	}
+	if err = scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	if properties["APIHOST"] == "" || properties["AUTH"] == "" {
+		return nil, fmt.Errorf("APIHOST and AUTH must be set in %s", path)
+	}
+
+	return NewOpenWhiskActivationClient(properties["APIHOST"], properties["NAMESPACE"], properties["AUTH"]), nil
+}
+
+func (c *OpenWhiskActivationClient) get(path string, query url.Values, result any) error {
+	requestURL := fmt.Sprintf("%s/api/v1/namespaces/%s/%s", c.apiHost, url.PathEscape(c.namespace), path)
+	if len(query) > 0 {
+		requestURL += "?" + query.Encode()
+	}
+
+	req, err := http.NewRequest(http.MethodGet, requestURL, nil)
+	if err != nil {
+		return err
+	}
+	if user, password, found := strings.Cut(c.auth, ":"); found {
+		req.SetBasicAuth(user, password)
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer HandleBodyClosing(resp)
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status code %d for %s", resp.StatusCode, path)
+	}
+
+	return json.NewDecoder(resp.Body).Decode(result)
+}
+
+// ListActivations returns the activations started within [since, upto], in milliseconds since epoch.
+func (c *OpenWhiskActivationClient) ListActivations(since int64, upto int64) (map[string]ActivationMetadata, error) {
+	result := make(map[string]ActivationMetadata)
+
+	for skip := 0; ; skip += openWhiskActivationPageSize {
+		var page []openWhiskActivation
+		err := c.get("activations", url.Values{
+			"docs":  {"true"},
+			"limit": {strconv.Itoa(openWhiskActivationPageSize)},
+			"skip":  {strconv.Itoa(skip)},
+			"since": {strconv.FormatInt(since, 10)},
+			"upto":  {strconv.FormatInt(upto, 10)},
+		}, &page)
+		if err != nil {
+			return result, err
+		}
+
+		for i := range page {
+			result[page[i].ActivationID] = page[i].metadata()
+		}
+
+		if len(page) < openWhiskActivationPageSize {
+			return result, nil
+		}
+	}
+}
+
+func (c *OpenWhiskActivationClient) GetActivation(activationID string) (ActivationMetadata, error) {
+	var activation openWhiskActivation
+	if err := c.get("activations/"+url.PathEscape(activationID), nil, &activation); err != nil {
+		return ActivationMetadata{}, err
+	}
+
+	return activation.metadata(), nil
+}
+
+// CompleteRecords fills the records with the duration, the start type, and the wait and init time of their
+// activations. Activations missing from the bulk listing (e.g., due to the list being capped by the database) are
+// fetched one by one. Returns the number of records that could not be completed.
+func (c *OpenWhiskActivationClient) CompleteRecords(records []*mc.ExecutionRecord) int {
+	if len(records) == 0 {
+		return 0
+	}
+
+	since, upto := records[0].StartTime, records[0].StartTime
+	for _, record := range records {
+		since = min(since, record.StartTime)
+		upto = max(upto, record.StartTime+record.ResponseTime)
+	}
+
+	// record timestamps are in microseconds, and the API expects milliseconds
+	activations, err := c.ListActivations(since/1000-1, upto/1000+1)
+	if err != nil {
+		log.Warnf("Failed to list OpenWhisk activations, falling back to per-activation queries - %v", err)
+	}
+
+	incomplete := 0
+	for _, record := range records {
+		metadata, ok := activations[record.ActivationID]
+		if !ok {
+			metadata, err = c.GetActivation(record.ActivationID)
+			if err != nil {
+				log.Debugf("Failed to read OpenWhisk activation %s - %v", record.ActivationID, err)
+				incomplete++
+				continue
+			}
+		}
+
+		record.ActualDuration = metadata.Duration * 1000 //ms to micro sec
+		record.StartType = metadata.StartType
+		record.InitTime = metadata.InitTime * 1000 //ms to micro sec
+		record.WaitTime = metadata.WaitTime * 1000 //ms to micro sec
+	}
+
+	return incomplete
+}
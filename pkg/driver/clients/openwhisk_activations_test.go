@@ -0,0 +1,141 @@
+package clients
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/vhive-serverless/loader/pkg/common"
+	"github.com/vhive-serverless/loader/pkg/config"
+	mc "github.com/vhive-serverless/loader/pkg/metric"
+)
+
+// newFakeOpenWhiskAPI serves the activation list and get endpoints of the OpenWhisk REST API. The list endpoint
+// omits the activations in hidden, which can then only be read one by one.
+func newFakeOpenWhiskAPI(t *testing.T, activations []map[string]any, hidden map[string]bool) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if user, password, ok := r.BasicAuth(); !ok || user != "user" || password != "key" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		const prefix = "/api/v1/namespaces/_/activations"
+		switch {
+		case r.URL.Path == prefix:
+			skip, _ := strconv.Atoi(r.URL.Query().Get("skip"))
+			limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+			if r.URL.Query().Get("docs") != "true" || r.URL.Query().Get("since") == "" {
+				t.Errorf("Unexpected query: %s", r.URL.RawQuery)
+			}
+
+			var listed []map[string]any
+			for _, activation := range activations {
+				if !hidden[activation["activationId"].(string)] {
+					listed = append(listed, activation)
+				}
+			}
+			listed = listed[min(skip, len(listed)):min(skip+limit, len(listed))]
+
+			_ = json.NewEncoder(w).Encode(listed)
+		case strings.HasPrefix(r.URL.Path, prefix+"/"):
+			id := strings.TrimPrefix(r.URL.Path, prefix+"/")
+			for _, activation := range activations {
+				if activation["activationId"] == id {
+					_ = json.NewEncoder(w).Encode(activation)
+					return
+				}
+			}
+			w.WriteHeader(http.StatusNotFound)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+}
+
+func TestOpenWhiskActivationClientCompleteRecords(t *testing.T) {
+	var activations []map[string]any
+	var records []*mc.ExecutionRecord
+	// more activations than a page of the list API
+	for i := 0; i < openWhiskActivationPageSize+10; i++ {
+		id := "activation" + strconv.Itoa(i)
+		annotations := []map[string]any{{"key": "waitTime", "value": 3}, {"key": "kind", "value": "go:1.17"}}
+		if i%2 == 0 {
+			annotations = append(annotations, map[string]any{"key": "initTime", "value": 250})
+		}
+
+		activations = append(activations, map[string]any{"activationId": id, "duration": 12, "annotations": annotations})
+		records = append(records, &mc.ExecutionRecord{ExecutionRecordBase: mc.ExecutionRecordBase{StartTime: 1_700_000_000_000_000}, ActivationID: id})
+	}
+	records = append(records, &mc.ExecutionRecord{ActivationID: "missing"})
+
+	server := newFakeOpenWhiskAPI(t, activations, map[string]bool{"activation7": true})
+	defer server.Close()
+
+	client := NewOpenWhiskActivationClient(server.URL, "", "user:key")
+	if incomplete := client.CompleteRecords(records); incomplete != 1 {
+		t.Errorf("Expected 1 incomplete record, got %d", incomplete)
+	}
+
+	for i, record := range records[:len(records)-1] {
+		expectedType, expectedInit := mc.Hot, int64(0)
+		if i%2 == 0 {
+			expectedType, expectedInit = mc.Cold, 250_000
+		}
+
+		if record.ActualDuration != 12_000 || record.WaitTime != 3_000 ||
+			record.StartType != expectedType || record.InitTime != expectedInit {
+
+			t.Errorf("Unexpected record %s: %+v", record.ActivationID, record)
+		}
+	}
+}
+
+func TestOpenWhiskActivationClientFromProperties(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "wskprops")
+	if err := os.WriteFile(path, []byte("APIHOST=10.0.0.1:31001\nAUTH=user:key\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("WSK_CONFIG_FILE", path)
+
+	client, err := NewOpenWhiskActivationClientFromProperties()
+	if err != nil || client.apiHost != "https://10.0.0.1:31001" || client.namespace != "_" || client.auth != "user:key" {
+		t.Errorf("Unexpected client: %+v, %v", client, err)
+	}
+}
+
+func TestOpenWhiskInvokerRecordsActivationID(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/failing" {
+			w.Header().Set(openWhiskActivationIDHeader, "activation1")
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+
+		body, _ := json.Marshal(FunctionResponse{Status: "OK", Function: "test-function", ExecutionTime: 10_000})
+
+		w.Header().Set(openWhiskActivationIDHeader, "activation0")
+		_, _ = w.Write([]byte(base64.StdEncoding.EncodeToString(body)))
+	}))
+	defer server.Close()
+
+	cfg := &config.Configuration{LoaderConfiguration: &config.LoaderConfiguration{Platform: common.PlatformOpenWhisk}}
+	function := &common.Function{Name: "test-function", Endpoint: server.URL}
+
+	invoker := CreateInvoker(cfg)
+	success, record := invoker.Invoke(function, &testRuntimeSpecs, "min0.inv0")
+	if !success || record.ActivationID != "activation0" || record.InvocationID != "min0.inv0" || record.HttpStatusCode != http.StatusOK {
+		t.Errorf("Unexpected record: %+v", record)
+	}
+
+	function.Endpoint = server.URL + "/failing"
+	success, record = invoker.Invoke(function, &testRuntimeSpecs, "min0.inv1")
+	if success || record.ActivationID != "activation1" || record.HttpStatusCode != http.StatusBadGateway {
+		t.Errorf("Unexpected record of a failed activation: %+v", record)
+	}
+}
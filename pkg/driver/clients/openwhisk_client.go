@@ -8,9 +8,6 @@ import (
 	"fmt"
 	"io"
 	"net/http"
-	"os/exec"
-	"strings"
-	"sync"
 	"time"
 
 	log "github.com/sirupsen/logrus"
@@ -18,108 +15,53 @@ import (
 	mc "github.com/vhive-serverless/loader/pkg/metric"
 )
 
-type ActivationMetadata struct {
-	Duration  uint32 //ms
-	StartType mc.StartType
-	WaitTime  int64 //ms
-	InitTime  int64 //ms
-}
-
 type HTTPResBody struct {
 	DurationInMicroSec uint32 `json:"DurationInMicroSec"`
 	MemoryUsageInKb    uint32 `json:"MemoryUsageInKb"`
-}
 
-type openWhiskInvoker struct {
-	announceDoneExe       *sync.WaitGroup
-	readOpenWhiskMetadata *sync.Mutex
+	ReceiveTimestampInMicroSec int64  `json:"ReceiveTimestampInMicroSec"`
+	StartTimestampInMicroSec   int64  `json:"StartTimestampInMicroSec"`
+	EndTimestampInMicroSec     int64  `json:"EndTimestampInMicroSec"`
+	ColdStart                  bool   `json:"ColdStart"`
+	InstanceUptimeInMicroSec   int64  `json:"InstanceUptimeInMicroSec"`
+	InstanceID                 string `json:"InstanceID"`
 }
 
-func newOpenWhiskInvoker(announceDoneExe *sync.WaitGroup, readOpenWhiskMetadata *sync.Mutex) *openWhiskInvoker {
-	return &openWhiskInvoker{
-		announceDoneExe:       announceDoneExe,
-		readOpenWhiskMetadata: readOpenWhiskMetadata,
-	}
+const openWhiskActivationIDHeader = "X-Openwhisk-Activation-Id"
+
+// openWhiskInvoker only records the activation ID of each invocation. Querying OpenWhisk during the experiment
+// interferes with the measurements (Issue 329: https://github.com/vhive-serverless/invitro/issues/329), so the
+// activation records are fetched in bulk through the REST API once the experiment ends.
+type openWhiskInvoker struct{}
+
+func newOpenWhiskInvoker() *openWhiskInvoker {
+	return &openWhiskInvoker{}
 }
 
-func (i *openWhiskInvoker) Invoke(function *common.Function, runtimeSpec *common.RuntimeSpecification) (bool, *mc.ExecutionRecord) {
+func (i *openWhiskInvoker) Invoke(function *common.Function, runtimeSpec *common.RuntimeSpecification, invocationID string) (bool, *mc.ExecutionRecord) {
 	log.Tracef("(Invoke)\t %s: %d[ms], %d[MiB]", function.Name, runtimeSpec.Runtime, runtimeSpec.Memory)
 
 	qs := fmt.Sprintf("cpu=%d", runtimeSpec.Runtime)
 
-	success, executionRecordBase, res := httpInvocation(qs, function, i.announceDoneExe, true)
-	i.announceDoneExe.Wait() // To postpone querying OpenWhisk during the experiment for performance reasons (Issue 329: https://github.com/vhive-serverless/invitro/issues/329)
+	success, executionRecordBase, res := httpInvocation(qs, function, invocationID, true)
 
 	executionRecordBase.RequestedDuration = uint32(runtimeSpec.Runtime * 1e3)
 	record := &mc.ExecutionRecord{ExecutionRecordBase: *executionRecordBase}
-	if !success {
-		return false, record
-	}
-
-	activationID := res.Header.Get("X-Openwhisk-Activation-Id")
-	i.readOpenWhiskMetadata.Lock()
-
-	//read data from OpenWhisk based on the activation ID
-	cmd := exec.Command("wsk", "-i", "activation", "get", activationID)
-	var out bytes.Buffer
-	cmd.Stdout = &out
-	err := cmd.Run()
-	if err != nil {
-		log.Debugf("error reading activation information from OpenWhisk %s - %s", function.Name, err)
-		i.readOpenWhiskMetadata.Unlock()
-		return false, record
+	if res != nil {
+		record.ActivationID = res.Header.Get(openWhiskActivationIDHeader)
+		record.HttpStatusCode = res.StatusCode
 	}
-
-	i.readOpenWhiskMetadata.Unlock()
-	activationMetadata, err := parseActivationMetadata(out.String())
-	if err != nil {
-		log.Debugf("error parsing activation metadata %s - %s", function.Name, err)
+	if !success {
 		return false, record
 	}
 
-	record.ActualDuration = activationMetadata.Duration * 1000 //ms to micro sec
-	/*record.StartType = activationMetadata.StartType
-	record.InitTime = activationMetadata.InitTime * 1000 //ms to micro sec
-	record.WaitTime = activationMetadata.WaitTime * 1000 //ms to micro sec*/
-
 	logInvocationSummary(function, &record.ExecutionRecordBase, res)
 
 	return true, record
 }
 
-func parseActivationMetadata(response string) (ActivationMetadata, error) {
-	var result ActivationMetadata
-	var jsonMap map[string]any
-
-	ind := strings.Index(response, "{")
-	err := json.Unmarshal([]byte(response[ind:]), &jsonMap)
-	if err != nil {
-		return result, err
-	}
-
-	result.Duration = uint32(jsonMap["duration"].(float64))
-	result.StartType = mc.Hot
-	result.InitTime = 0
-	annotations := jsonMap["annotations"].([]any)
-	for i := range annotations {
-		annotation := annotations[i].(map[string]any)
-
-		switch annotation["key"] {
-		case "waitTime":
-			result.WaitTime = int64(annotation["value"].(float64))
-		case "initTime":
-			result.StartType = mc.Cold
-			result.InitTime = int64(annotation["value"].(float64))
-		}
-	}
-
-	return result, nil
-}
-
-func httpInvocation(dataString string, function *common.Function, AnnounceDoneExe *sync.WaitGroup, tlsSkipVerify bool) (bool, *mc.ExecutionRecordBase, *http.Response) {
-	defer AnnounceDoneExe.Done()
-
-	record := &mc.ExecutionRecordBase{}
+func httpInvocation(dataString string, function *common.Function, invocationID string, tlsSkipVerify bool) (bool, *mc.ExecutionRecordBase, *http.Response) {
+	record := &mc.ExecutionRecordBase{InvocationID: invocationID}
 
 	start := time.Now()
 	record.StartTime = start.UnixMicro()
@@ -143,6 +85,7 @@ func httpInvocation(dataString string, function *common.Function, AnnounceDoneEx
 	}
 
 	req.Header.Set("Content-Type", "application/json") // To avoid data being base64encoded
+	setInvocationHeaders(req.Header, function, invocationID)
 
 	resp, err := http.DefaultClient.Do(req)
 	if err != nil {
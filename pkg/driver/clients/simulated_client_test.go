@@ -0,0 +1,54 @@
+package clients
+
+import (
+	"testing"
+
+	"github.com/vhive-serverless/loader/pkg/common"
+	"github.com/vhive-serverless/loader/pkg/config"
+	mc "github.com/vhive-serverless/loader/pkg/metric"
+	"github.com/vhive-serverless/loader/pkg/simulator"
+)
+
+func TestSimulatedInvoker(t *testing.T) {
+	cfg := &config.Configuration{
+		LoaderConfiguration: &config.LoaderConfiguration{Platform: common.PlatformSimulated},
+		SimulatedConfiguration: &config.SimulatedConfig{
+			KeepAliveSeconds:    60,
+			ColdStartMedianMs:   20,
+			ColdStartSigma:      0.1,
+			InstanceConcurrency: 1,
+			NodeMemoryMiB:       1024,
+			QueueTimeoutSeconds: 1,
+		},
+	}
+	if err := simulator.ForConfig(cfg.SimulatedConfiguration).Deploy("test-function", 128, 0, 0); err != nil {
+		t.Fatal(err)
+	}
+
+	invoker := CreateInvoker(cfg)
+	function := &common.Function{Name: "test-function"}
+
+	success, cold := invoker.Invoke(function, &testRuntimeSpecs, "min0.inv0")
+	if !success ||
+		cold.InvocationID != "min0.inv0" ||
+		cold.StartType != mc.Cold ||
+		cold.InitTime <= 0 ||
+		cold.InstanceID == "" ||
+		cold.RequestedDuration != 10_000 ||
+		cold.ActualDuration < 10_000 ||
+		cold.ResponseTime < int64(cold.ActualDuration)+cold.InitTime ||
+		cold.FunctionEndTime-cold.FunctionStartTime != int64(cold.ActualDuration) {
+
+		t.Errorf("Unexpected record of the cold invocation: %+v", cold)
+	}
+
+	success, warm := invoker.Invoke(function, &testRuntimeSpecs, "min0.inv1")
+	if !success || warm.StartType != mc.Hot || warm.InitTime != 0 || warm.InstanceID != cold.InstanceID {
+		t.Errorf("Unexpected record of the warm invocation: %+v", warm)
+	}
+
+	success, missing := invoker.Invoke(&common.Function{Name: "missing"}, &testRuntimeSpecs, "min0.inv2")
+	if success || !missing.ConnectionTimeout {
+		t.Errorf("Invocations of functions that are not deployed should fail: %+v", missing)
+	}
+}
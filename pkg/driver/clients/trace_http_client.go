@@ -0,0 +1,113 @@
+package clients
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/vhive-serverless/loader/pkg/common"
+	"github.com/vhive-serverless/loader/pkg/config"
+	mc "github.com/vhive-serverless/loader/pkg/metric"
+)
+
+// traceFunctionHTTPInvoker invokes the trace function served over plain HTTP (FUNC_PROTOCOL_ENV=http) behind the
+// gateway or the router of a platform.
+type traceFunctionHTTPInvoker struct {
+	client *http.Client
+	// recordHeaders stores platform-specific response headers in the record, may be nil
+	recordHeaders func(header http.Header, record *mc.ExecutionRecord)
+}
+
+func newTraceFunctionHTTPInvoker(cfg *config.LoaderConfiguration, recordHeaders func(http.Header, *mc.ExecutionRecord)) *traceFunctionHTTPInvoker {
+	return &traceFunctionHTTPInvoker{
+		client:        CreateHTTPClient(cfg.GRPCFunctionTimeoutSeconds, cfg.InvokeProtocol),
+		recordHeaders: recordHeaders,
+	}
+}
+
+func (i *traceFunctionHTTPInvoker) Invoke(function *common.Function, runtimeSpec *common.RuntimeSpecification, invocationID string) (bool, *mc.ExecutionRecord) {
+	log.Tracef("(Invoke)\t %s: %d[ms], %d[MiB]", function.Name, runtimeSpec.Runtime, runtimeSpec.Memory)
+
+	record := &mc.ExecutionRecord{
+		ExecutionRecordBase: mc.ExecutionRecordBase{
+			InvocationID:      invocationID,
+			RequestedDuration: uint32(runtimeSpec.Runtime * 1e3),
+		},
+	}
+	start := time.Now()
+	record.StartTime = start.UnixMicro()
+	record.Instance = function.Name
+
+	dataString := fmt.Sprintf(`{"RuntimeInMilliSec": %d, "MemoryInMebiBytes": %d}`, runtimeSpec.Runtime, runtimeSpec.Memory)
+	req, err := http.NewRequest(http.MethodPost, function.Endpoint, bytes.NewBufferString(dataString))
+	if err != nil {
+		log.Warnf("http request creation failed for function %s - %s", function.Name, err)
+
+		record.ResponseTime = time.Since(start).Microseconds()
+		record.ConnectionTimeout = true
+
+		return false, record
+	}
+	req.Header.Set("Content-Type", "application/json")
+	setInvocationHeaders(req.Header, function, invocationID)
+
+	resp, err := i.client.Do(req)
+	if err != nil {
+		log.Debugf("http request for function %s failed - %s", function.Name, err)
+
+		record.ResponseTime = time.Since(start).Microseconds()
+		record.ConnectionTimeout = true
+
+		return false, record
+	}
+	defer HandleBodyClosing(resp)
+
+	body, err := io.ReadAll(resp.Body)
+	record.ResponseTime = time.Since(start).Microseconds()
+	if i.recordHeaders != nil {
+		i.recordHeaders(resp.Header, record)
+	}
+
+	if err != nil || resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		log.Debugf("http request for function %s failed - status code: %d - %v", function.Name, resp.StatusCode, err)
+
+		record.FunctionTimeout = true
+
+		return false, record
+	}
+
+	if err = ParseTraceFunctionReply(body, record); err != nil {
+		log.Debugf("Error unmarshaling JSON of function %s - %s", function.Name, err)
+		// fall back to the duration measured by the gateway, if any
+		record.ActualDuration = uint32(record.GatewayDuration)
+
+		return true, record
+	}
+
+	log.Tracef("(Replied)\t %s: %d[us], call ID: %s", function.Name, record.ActualDuration, record.CallID)
+	log.Tracef("(E2E Latency) %s: %.2f[ms]\n", function.Name, float64(record.ResponseTime)/1e3)
+
+	return true, record
+}
+
+// ParseTraceFunctionReply completes the record with the JSON reply of the trace function, which is also the payload
+// of the callbacks the trace function sends for asynchronous invocations.
+func ParseTraceFunctionReply(body []byte, record *mc.ExecutionRecord) error {
+	var httpResBody HTTPResBody
+	if err := json.Unmarshal(body, &httpResBody); err != nil {
+		return err
+	}
+
+	record.ActualDuration = httpResBody.DurationInMicroSec
+	record.ActualMemoryUsage = common.Kib2Mib(httpResBody.MemoryUsageInKb)
+	record.InstanceID = httpResBody.InstanceID
+	recordInstanceTimestamps(record, httpResBody.ReceiveTimestampInMicroSec, httpResBody.StartTimestampInMicroSec,
+		httpResBody.EndTimestampInMicroSec, httpResBody.InstanceUptimeInMicroSec, httpResBody.ColdStart)
+	deriveQueueingTime(record)
+
+	return nil
+}
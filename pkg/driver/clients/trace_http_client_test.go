@@ -0,0 +1,48 @@
+package clients
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/vhive-serverless/loader/pkg/common"
+	"github.com/vhive-serverless/loader/pkg/config"
+	mc "github.com/vhive-serverless/loader/pkg/metric"
+)
+
+func TestFissionInvoker(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/test-function" || r.Header.Get(common.FunctionNameHeader) != "test-function" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"DurationInMicroSec":         10_000,
+			"MemoryUsageInKb":            131072,
+			"ReceiveTimestampInMicroSec": 1_000_000,
+			"StartTimestampInMicroSec":   1_000_010,
+			"EndTimestampInMicroSec":     1_010_010,
+			"ColdStart":                  false,
+		})
+	}))
+	defer server.Close()
+
+	cfg := &config.Configuration{LoaderConfiguration: &config.LoaderConfiguration{
+		Platform:                   common.PlatformFission,
+		InvokeProtocol:             "http1",
+		GRPCFunctionTimeoutSeconds: 5,
+	}}
+	function := &common.Function{Name: "test-function", Endpoint: server.URL + "/test-function"}
+
+	success, record := CreateInvoker(cfg).Invoke(function, &testRuntimeSpecs, "min0.inv1")
+	if !success ||
+		record.ActualDuration != 10_000 ||
+		record.StartType != mc.Hot ||
+		record.CallID != "" ||
+		record.InvocationID != "min0.inv1" {
+
+		t.Errorf("Unexpected record: %+v", record)
+	}
+}
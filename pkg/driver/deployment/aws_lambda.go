@@ -1,10 +1,12 @@
 package deployment
 
 import (
+	"errors"
 	"fmt"
 	log "github.com/sirupsen/logrus"
 	"github.com/vhive-serverless/loader/pkg/common"
 	"github.com/vhive-serverless/loader/pkg/config"
+	"gopkg.in/yaml.v3"
 	"os/exec"
 	"strings"
 	"sync"
@@ -19,17 +21,42 @@ func newAWSLambdaDeployer() *awsLambdaDeployer {
 	return &awsLambdaDeployer{}
 }
 
-func (ld *awsLambdaDeployer) Deploy(cfg *config.Configuration) {
+func (ld *awsLambdaDeployer) Deploy(cfg *config.Configuration) error {
 	ld.functions = cfg.Functions
 
-	internalAWSDeployment(cfg.Functions)
+	return internalAWSDeployment(cfg.Functions)
 }
 
 func (ld *awsLambdaDeployer) Clean() {
 	CleanAWSLambda(ld.functions)
 }
 
-func internalAWSDeployment(functions []*common.Function) {
+// Render writes the serverless.yml files of the deployment, leaving the AWS account ID to be resolved by the
+// Serverless Framework.
+func (ld *awsLambdaDeployer) Render(cfg *config.Configuration, outputDir string) error {
+	const provider = "aws"
+
+	for i, functionGroup := range separateFunctions(cfg.Functions) {
+		serverless := Serverless{}
+		serverless.CreateHeader(i, provider)
+
+		for _, function := range functionGroup {
+			serverless.AddFunctionConfig(function, provider, "${aws:accountId}")
+		}
+
+		data, err := yaml.Marshal(&serverless)
+		if err != nil {
+			return err
+		}
+		if err = writeArtifact(outputDir, fmt.Sprintf("serverless-%d.yml", i), data); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func internalAWSDeployment(functions []*common.Function) error {
 	const provider = "aws"
 
 	// Check if all required dependencies are installed, verify that AWS account is clean and ready for deployment
@@ -41,8 +68,8 @@ func internalAWSDeployment(functions []*common.Function) {
 	// Use goroutines to deploy functions in parallel, and ensure all finishes
 	// Due to CPU and memory constraints, by default, we will deploy 2 serverless.yml files in parallel and wait for them to finish before deploying the next 2
 	var wg sync.WaitGroup
-	var counter uint64 = 0
 	parallelDeployment := 2
+	errs := make([]error, len(functionGroups))
 
 	for i := 0; i < len(functionGroups); {
 		for range parallelDeployment {
@@ -55,10 +82,8 @@ func internalAWSDeployment(functions []*common.Function) {
 					functionToURLMapping := DeployServerless(index)
 
 					if functionToURLMapping == nil {
-						CleanAWSLambda(functions)                               // Clean up all deployed functions before exiting
-						log.Fatalf("Failed to deploy serverless-%d.yml", index) // Immediately terminate deployment for fast feedback
+						errs[index] = fmt.Errorf("failed to deploy serverless-%d.yml", index)
 					} else {
-						atomic.AddUint64(&counter, 1)
 						for i := range functionGroup {
 							functionGroup[i].Endpoint = functionToURLMapping[i]
 							log.Debugf("Function %s set to %s", functionGroup[i].Name, functionGroup[i].Endpoint)
@@ -69,9 +94,17 @@ func internalAWSDeployment(functions []*common.Function) {
 			}
 		}
 		wg.Wait()
+
+		// Immediately terminate deployment for fast feedback
+		if err := errors.Join(errs...); err != nil {
+			CleanAWSLambda(functions) // Clean up all deployed functions before returning
+			return err
+		}
 	}
 
 	log.Debugf("Deployed all %d serverless.yml files", len(functionGroups))
+
+	return nil
 }
 
 // CleanAWSLambda cleans up the AWS Lambda deployment environment by deleting all serverless.yml files and the ECR private repository
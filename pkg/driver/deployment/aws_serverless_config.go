@@ -68,8 +68,7 @@ func (s *Serverless) AddPackagePattern(pattern string) {
 
 // AddFunctionConfig adds the function configuration for serverless.com deployment
 func (s *Serverless) AddFunctionConfig(function *common.Function, provider string, awsAccountId string) {
-	// Extract trace-func-0 from trace-func-0-2642643831809466437 by splitting on "-"
-	shortName := fmt.Sprintf("%s-%s", common.FunctionNamePrefix, strings.Split(function.Name, "-")[2])
+	shortName := common.AWSLambdaFunctionName(function)
 
 	var image string
 	var timeout string
@@ -33,16 +33,47 @@ func newAzureFunctionsDeployer() *azureFunctionsDeployer {
 	return &azureFunctionsDeployer{}
 }
 
-func (afd *azureFunctionsDeployer) Deploy(cfg *config.Configuration) {
+func (afd *azureFunctionsDeployer) Deploy(cfg *config.Configuration) error {
 	afd.functions = cfg.Functions
-	afd.config = DeployAzureFunctions(afd.functions)
+
+	var err error
+	afd.config, err = DeployAzureFunctions(afd.functions)
+	return err
 }
 
 func (afd *azureFunctionsDeployer) Clean() {
+	// no Azure resources were created
+	if afd.config == nil {
+		return
+	}
+
 	CleanAzureFunctions(afd.config, afd.functions)
 }
 
-func DeployAzureFunctions(functions []*common.Function) *Config {
+// Render writes the function app folder of each function along with the host.json and requirements.txt files that
+// are zipped with them on deployment.
+func (afd *azureFunctionsDeployer) Render(cfg *config.Configuration, outputDir string) error {
+	sharedWorkloadDir := filepath.Join("azurefunctions_setup", "shared_azure_workload")
+
+	if err := CopyPythonWorkload("server/trace-func-py/exec_func.py", filepath.Join(sharedWorkloadDir, "exec_func.py")); err != nil {
+		return err
+	}
+	if err := CreateFunctionFolders(outputDir, sharedWorkloadDir, cfg.Functions); err != nil {
+		return err
+	}
+
+	for _, file := range []string{"host.json", "requirements.txt"} {
+		if err := common.CopyFile(filepath.Join("azurefunctions_setup", file), filepath.Join(outputDir, file)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// DeployAzureFunctions deploys the functions in a new resource group. The returned configuration is nil unless the
+// resource group exists, even if the deployment failed.
+func DeployAzureFunctions(functions []*common.Function) (*Config, error) {
 	// 1. Copy exec_func.py to azurefunctions_setup
 	// 2. Initialize resources required for Azure Functions deployment
 	// 3. Create function folders
@@ -52,7 +83,7 @@ func DeployAzureFunctions(functions []*common.Function) *Config {
 	// Load azurefunctionsconfig yaml file
 	config, err := LoadConfig("azurefunctions_setup/azurefunctionsconfig.yaml")
 	if err != nil {
-		log.Fatalf("Error loading azure functions config yaml: %s", err)
+		return nil, fmt.Errorf("error loading azure functions config yaml: %w", err)
 	}
 
 	// Set unique names for Azure Resources
@@ -68,28 +99,30 @@ func DeployAzureFunctions(functions []*common.Function) *Config {
 
 	// 1. Run script to copy workload
 	if err := CopyPythonWorkload("server/trace-func-py/exec_func.py", "azurefunctions_setup/shared_azure_workload/exec_func.py"); err != nil {
-		log.Fatalf("Error copying Python workload: %s", err)
+		return nil, fmt.Errorf("error copying Python workload: %w", err)
 	}
 
 	// 2. Initialize resources required for Azure Functions deployment
-	InitAzureFunctions(config, functions)
+	if err := InitAzureFunctions(config, functions); err != nil {
+		return nil, err
+	}
 
 	// 3. Create function folders
 	if err := CreateFunctionFolders(baseDir, sharedWorkloadDir, functions); err != nil {
-		log.Fatalf("Error setting up function folders required for zipping: %s", err)
+		return config, fmt.Errorf("error setting up function folders required for zipping: %w", err)
 	}
 
 	// 4. Zip function folders
 	if err := ZipFunctionAppFiles(baseDir, functions); err != nil {
-		log.Fatalf("Error zipping function app files for deployment: %s", err)
+		return config, fmt.Errorf("error zipping function app files for deployment: %w", err)
 	}
 
 	// 5. Deploy the function to Azure Functions
 	if err := DeployFunctions(config, zipBaseDir, functions); err != nil {
-		log.Fatalf("Error deploying function: %s", err)
+		return config, fmt.Errorf("error deploying function: %w", err)
 	}
 
-	return config
+	return config, nil
 }
 
 func CleanAzureFunctions(config *Config, functions []*common.Function) {
@@ -124,14 +157,16 @@ func CopyPythonWorkload(srcPath, dstPath string) error {
 
 /* Functions for initializing resources required for Azure Functions deployment */
 
-func InitAzureFunctions(config *Config, functions []*common.Function) {
+// InitAzureFunctions creates the resources of the deployment, deleting the resource group if any of them could not be
+// created.
+func InitAzureFunctions(config *Config, functions []*common.Function) error {
 	// 1. Create Resource Group
 	// 2. Create Storage Account
 	// 3. Create Function Apps + Set Settings For Each App
 
 	// 1. Create Resource Group
 	if err := CreateResourceGroup(config); err != nil {
-		log.Fatalf("Error during Resource Group creation: %s", err)
+		return fmt.Errorf("error during Resource Group creation: %w", err)
 	}
 
 	// 2. Create Storage Account
@@ -142,7 +177,7 @@ func InitAzureFunctions(config *Config, functions []*common.Function) {
 			log.Errorf("Failed to delete resource group during cleanup: %v", cleanupErr)
 		}
 
-		log.Fatalf("Error during Storage Account creation: %s", err)
+		return fmt.Errorf("error during Storage Account creation: %w", err)
 	}
 
 	// 3. Create Function Apps + Set Settings For Each App
@@ -156,7 +191,7 @@ func InitAzureFunctions(config *Config, functions []*common.Function) {
 				log.Errorf("Failed to delete resource group during cleanup: %v", cleanupErr)
 			}
 
-			log.Fatalf("Error during Function App creation: %s", err)
+			return fmt.Errorf("error during Function App creation: %w", err)
 		}
 
 		// Set SCM_DO_BUILD_DURING_DEPLOYMENT
@@ -167,7 +202,7 @@ func InitAzureFunctions(config *Config, functions []*common.Function) {
 				log.Errorf("Failed to delete resource group during cleanup: %v", cleanupErr)
 			}
 
-			log.Fatalf("failed to set SCM settings: %s", err)
+			return fmt.Errorf("failed to set SCM settings: %w", err)
 		}
 
 		// Set ENABLE_ORYX_BUILD
@@ -178,11 +213,13 @@ func InitAzureFunctions(config *Config, functions []*common.Function) {
 				log.Errorf("Failed to delete resource group during cleanup: %v", cleanupErr)
 			}
 
-			log.Fatalf("failed to set Oryx settings: %s", err)
+			return fmt.Errorf("failed to set Oryx settings: %w", err)
 		}
 	}
 
 	log.Info("Azure Functions environment for deployment initialized successfully.")
+
+	return nil
 }
 
 // LoadConfig reads the YAML configuration file
@@ -1,16 +1,26 @@
 package deployment
 
 import (
+	"os"
+	"path/filepath"
+
 	"github.com/sirupsen/logrus"
 	"github.com/vhive-serverless/loader/pkg/common"
 	"github.com/vhive-serverless/loader/pkg/config"
 )
 
 type FunctionDeployer interface {
-	Deploy(cfg *config.Configuration)
+	// Deploy deploys the functions and sets their endpoints.
+	Deploy(cfg *config.Configuration) error
 	Clean()
 }
 
+// ArtifactRenderer is implemented by deployers that can write the artifacts they would deploy (e.g., manifests or
+// registration payloads) to a directory instead of applying them.
+type ArtifactRenderer interface {
+	Render(cfg *config.Configuration, outputDir string) error
+}
+
 func CreateDeployer(cfg *config.Configuration) FunctionDeployer {
 	switch cfg.LoaderConfiguration.Platform {
 	case common.PlatformAWSLambda:
@@ -23,9 +33,29 @@ func CreateDeployer(cfg *config.Configuration) FunctionDeployer {
 		return newKnativeDeployer()
 	case common.PlatformOpenWhisk:
 		return newOpenWhiskDeployer()
+	case common.PlatformGenericHTTP:
+		return newGenericHTTPDeployer()
+	case common.PlatformOpenFaaS:
+		return newOpenFaaSDeployer()
+	case common.PlatformFission:
+		return newFissionDeployer()
+	case common.PlatformSimulated:
+		return newSimulatedDeployer()
+	case common.PlatformLocal:
+		return newLocalDeployer()
 	default:
 		logrus.Fatal("Unsupported platform.")
 	}
 
 	return nil
 }
+
+// writeArtifact writes the artifact to the path relative to the output directory, creating its parent directories.
+func writeArtifact(outputDir string, path string, data []byte) error {
+	path = filepath.Join(outputDir, path)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, data, 0644)
+}
@@ -1,6 +1,8 @@
 package deployment
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"math/rand"
@@ -37,7 +39,9 @@ func newDirigentDeployerConfiguration(cfg *config.Configuration) dirigentDeploym
 	}
 }
 
-func (d *dirigentDeployer) Deploy(cfg *config.Configuration) {
+// Deploy registers the functions, or the functions and compositions of the workflow, with the control plane. The
+// returned error joins the errors of the functions that could not be registered.
+func (d *dirigentDeployer) Deploy(cfg *config.Configuration) error {
 	dirigentDeployerConfig := newDirigentDeployerConfiguration(cfg)
 
 	endpoint := ""
@@ -45,13 +49,13 @@ func (d *dirigentDeployer) Deploy(cfg *config.Configuration) {
 	if dirigentDeployerConfig.deployWorkflow {
 		wfConfigPath := cfg.DirigentConfiguration.WorkflowConfigPath
 		if wfConfigPath == "" {
-			log.Fatalf("Failed to deploy workflow: no workflow config path specified in config file.")
+			return errors.New("failed to deploy workflow: no workflow config path specified in config file")
 		}
 		wfConfig := config.ReadWorkflowConfig(wfConfigPath)
 
 		dMetadata := cfg.Functions[0].DirigentMetadata
 		if dMetadata == nil {
-			log.Fatalf("No Dirigent metadata for workflow %s", cfg.Functions[0].Name)
+			return fmt.Errorf("no Dirigent metadata for workflow %s", cfg.Functions[0].Name)
 		}
 		tmpNumArgs := dMetadata.NumArgs
 		tmpNumRets := dMetadata.NumRets
@@ -67,7 +71,7 @@ func (d *dirigentDeployer) Deploy(cfg *config.Configuration) {
 				ColdStartBusyLoopMs: cfg.Functions[0].ColdStartBusyLoopMs,
 				DirigentMetadata:    dMetadata,
 			}
-			deployDirigentFunction(
+			err := deployDirigentFunction(
 				tmpFunction,
 				wfFunc.FunctionPath,
 				dirigentDeployerConfig.RegistrationServer,
@@ -75,16 +79,22 @@ func (d *dirigentDeployer) Deploy(cfg *config.Configuration) {
 				cfg.DirigentConfiguration.PrepullMode,
 				cfg.DirigentConfiguration.RpsRequestedGpu,
 			)
+			if err != nil {
+				return err
+			}
 			endpoint = tmpFunction.Endpoint
 		}
 		dMetadata.NumArgs = tmpNumArgs
 		dMetadata.NumRets = tmpNumRets
 
 		// deploy workflow (stored as configuration functions)
-		compositionNames := deployDirigentWorkflow(
+		compositionNames, err := deployDirigentWorkflow(
 			cfg.Functions[0],
 			dirigentDeployerConfig.RegistrationServer,
 		)
+		if err != nil {
+			return err
+		}
 		// create a function for each registered composition
 		newFunctions := make([]*common.Function, len(compositionNames))
 		for i, compositionName := range compositionNames {
@@ -101,6 +111,13 @@ func (d *dirigentDeployer) Deploy(cfg *config.Configuration) {
 		cfg.Functions = newFunctions
 
 	} else {
+		for _, function := range cfg.Functions {
+			if function.DirigentMetadata == nil {
+				return fmt.Errorf("no Dirigent metadata for function %s", function.Name)
+			}
+		}
+
+		errs := make([]error, len(cfg.Functions))
 		wg := &sync.WaitGroup{}
 		wg.Add(len(cfg.Functions))
 
@@ -108,10 +125,7 @@ func (d *dirigentDeployer) Deploy(cfg *config.Configuration) {
 			go func(idx int) {
 				defer wg.Done()
 
-				if cfg.Functions[i].DirigentMetadata == nil {
-					log.Fatalf("No Dirigent metadata for function %s", cfg.Functions[i].Name)
-				}
-				deployDirigentFunction(
+				errs[idx] = deployDirigentFunction(
 					cfg.Functions[idx],
 					cfg.Functions[idx].DirigentMetadata.Image,
 					dirigentDeployerConfig.RegistrationServer,
@@ -123,11 +137,83 @@ func (d *dirigentDeployer) Deploy(cfg *config.Configuration) {
 		}
 
 		wg.Wait()
+
+		return errors.Join(errs...)
 	}
+
+	return nil
 }
 
 func (*dirigentDeployer) Clean() {}
 
+// Render writes the registration payload of each function as submitted to the control plane, as well as the payload
+// of the workflow in workflow mode.
+func (d *dirigentDeployer) Render(cfg *config.Configuration, outputDir string) error {
+	dcfg := cfg.DirigentConfiguration
+
+	functions := cfg.Functions
+	images := make([]string, len(functions))
+	for i, function := range functions {
+		if function.DirigentMetadata == nil {
+			return fmt.Errorf("no Dirigent metadata for function %s", function.Name)
+		}
+		images[i] = function.DirigentMetadata.Image
+	}
+
+	if dcfg.Workflow {
+		if dcfg.WorkflowConfigPath == "" {
+			return fmt.Errorf("no workflow config path specified in config file")
+		}
+		wfConfig := config.ReadWorkflowConfig(dcfg.WorkflowConfigPath)
+
+		wfDescription, err := os.ReadFile(functions[0].DirigentMetadata.Image)
+		if err != nil {
+			return err
+		}
+		if err = writeDirigentPayload(outputDir, "workflow-"+functions[0].Name, url.Values{
+			"name":     {functions[0].Name},
+			"workflow": {string(wfDescription)},
+		}); err != nil {
+			return err
+		}
+
+		functions = make([]*common.Function, len(wfConfig.Functions))
+		images = make([]string, len(wfConfig.Functions))
+		for i, wfFunc := range wfConfig.Functions {
+			metadata := *cfg.Functions[0].DirigentMetadata
+			metadata.NumArgs = wfFunc.NumArgs
+			metadata.NumRets = wfFunc.NumRets
+
+			functions[i] = &common.Function{
+				Name:                wfFunc.FunctionName,
+				CPURequestsMilli:    cfg.Functions[0].CPURequestsMilli,
+				MemoryRequestsMiB:   cfg.Functions[0].MemoryRequestsMiB,
+				ColdStartBusyLoopMs: cfg.Functions[0].ColdStartBusyLoopMs,
+				DirigentMetadata:    &metadata,
+			}
+			images[i] = wfFunc.FunctionPath
+		}
+	}
+
+	for i, function := range functions {
+		payload := dirigentRegistrationPayload(function, images[i], dcfg.BusyLoopOnSandboxStartup, dcfg.PrepullMode, dcfg.RpsRequestedGpu)
+		if err := writeDirigentPayload(outputDir, function.Name, payload); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func writeDirigentPayload(outputDir string, name string, payload url.Values) error {
+	data, err := json.MarshalIndent(payload, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return writeArtifact(outputDir, name+".json", data)
+}
+
 var registrationClient = &http.Client{
 	Timeout: 300 * time.Second, // time for a request to timeout
 	Transport: &http.Transport{
@@ -152,7 +238,39 @@ var checkClient = &http.Client{
 	},
 }
 
-func deployDirigentFunction(function *common.Function, imagePath string, controlPlaneAddress string, busyLoopOnColdStart bool, prepullMode string, requestedGpu int) {
+func deployDirigentFunction(function *common.Function, imagePath string, controlPlaneAddress string, busyLoopOnColdStart bool, prepullMode string, requestedGpu int) error {
+	payload := dirigentRegistrationPayload(function, imagePath, busyLoopOnColdStart, prepullMode, requestedGpu)
+
+	log.Debug(payload)
+
+	resp, err := registrationClient.PostForm(fmt.Sprintf("http://%s/", controlPlaneAddress), payload)
+	if err != nil {
+		return fmt.Errorf("failed to register function %s with the control plane - %w", function.Name, err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read the registration response of function %s - %w", function.Name, err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("got status code %d while registering %s. Body: %s", resp.StatusCode, function.Name, body)
+	}
+
+	endpoints := strings.Split(string(body), ";")
+	if len(endpoints) == 0 {
+		return fmt.Errorf("registration of function %s returned no data plane(s)", function.Name)
+	}
+	function.Endpoint = endpoints[rand.Intn(len(endpoints))]
+
+	checkForRegistration(controlPlaneAddress, function.Name, prepullMode)
+
+	return nil
+}
+
+// dirigentRegistrationPayload returns the form submitted to the control plane to register the function.
+func dirigentRegistrationPayload(function *common.Function, imagePath string, busyLoopOnColdStart bool, prepullMode string, requestedGpu int) url.Values {
 	metadata := function.DirigentMetadata
 
 	if metadata == nil {
@@ -177,39 +295,21 @@ func deployDirigentFunction(function *common.Function, imagePath string, control
 		"node_port":           {strconv.Itoa(metadata.NodePort)},
 	}
 
+	if settings := function.Autoscaling; settings != nil {
+		if settings.MinScaleSet {
+			payload["scaling_lower_bound"] = []string{strconv.Itoa(settings.MinScale)}
+		}
+		if settings.MaxScale > 0 {
+			payload["scaling_upper_bound"] = []string{strconv.Itoa(settings.MaxScale)}
+		}
+	}
+
 	if busyLoopOnColdStart {
 		payload["iteration_multiplier"] = []string{strconv.Itoa(function.DirigentMetadata.IterationMultiplier)}
 		payload["cold_start_busy_loop_ms"] = []string{strconv.Itoa(function.ColdStartBusyLoopMs)}
 	}
 
-	log.Debug(payload)
-
-	resp, err := registrationClient.PostForm(fmt.Sprintf("http://%s/", controlPlaneAddress), payload)
-	if err != nil {
-		log.Error("Failed to register a service with the control plane - ", err.Error())
-		return
-	}
-	defer resp.Body.Close()
-
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		log.Error("Failed to read response body.")
-		return
-	}
-
-	if resp.StatusCode != http.StatusOK {
-		log.Errorf("Got status code %d while registering %s. Body: %s", resp.StatusCode, function.Name, body)
-		return
-	}
-
-	endpoints := strings.Split(string(body), ";")
-	if len(endpoints) == 0 {
-		log.Error("Function registration returned no data plane(s).")
-		return
-	}
-	function.Endpoint = endpoints[rand.Intn(len(endpoints))]
-
-	checkForRegistration(controlPlaneAddress, function.Name, prepullMode)
+	return payload
 }
 
 func checkForRegistration(controlPlaneAddress, functionName, prepullMode string) {
@@ -234,15 +334,15 @@ func checkForRegistration(controlPlaneAddress, functionName, prepullMode string)
 	}
 }
 
-func deployDirigentWorkflow(wf *common.Function, controlPlaneAddress string) []string {
+func deployDirigentWorkflow(wf *common.Function, controlPlaneAddress string) ([]string, error) {
 	metadata := wf.DirigentMetadata
 	if metadata == nil {
-		log.Fatalf("No Dirigent metadata for workflow %s", wf.Name)
+		return nil, fmt.Errorf("no Dirigent metadata for workflow %s", wf.Name)
 	}
 
 	wfDescription, err := os.ReadFile(metadata.Image)
 	if err != nil {
-		log.Fatalf("Failed to read workflow description file '%s' : %v", metadata.Image, err)
+		return nil, fmt.Errorf("failed to read workflow description file '%s' - %w", metadata.Image, err)
 	}
 	payload := url.Values{
 		"name":     {wf.Name},
@@ -251,19 +351,22 @@ func deployDirigentWorkflow(wf *common.Function, controlPlaneAddress string) []s
 
 	resp, err := registrationClient.PostForm(fmt.Sprintf("http://%s/workflow", controlPlaneAddress), payload)
 	if err != nil {
-		log.Fatalf("Failed to register a workflow with the control plane - %v", err)
+		return nil, fmt.Errorf("failed to register a workflow with the control plane - %w", err)
 	}
 	defer resp.Body.Close()
 
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		log.Fatalf("Failed to read response body.")
+		return nil, fmt.Errorf("failed to read the workflow registration response - %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("got status code %d while registering workflow %s. Body: %s", resp.StatusCode, wf.Name, body)
 	}
 
 	registeredCompositions := strings.Split(string(body), ";")
 	if len(registeredCompositions) == 0 {
-		log.Fatalf("Workflow registration returned zero registered workflows.")
+		return nil, errors.New("workflow registration returned zero registered workflows")
 	}
 
-	return registeredCompositions
+	return registeredCompositions, nil
 }
@@ -0,0 +1,57 @@
+package deployment_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vhive-serverless/loader/pkg/common"
+	"github.com/vhive-serverless/loader/pkg/config"
+	"github.com/vhive-serverless/loader/pkg/driver/deployment"
+)
+
+func TestDirigentDeployerRegistrationFailure(t *testing.T) {
+	// the control plane rejects the registration of broken functions
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.FormValue("name") == "broken-function" {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+
+		_, _ = w.Write([]byte("10.0.0.1:8080"))
+	}))
+	defer server.Close()
+
+	functions := []*common.Function{
+		{Name: "function", DirigentMetadata: &common.DirigentMetadata{Image: "trace-func"}},
+		{Name: "broken-function", DirigentMetadata: &common.DirigentMetadata{Image: "trace-func"}},
+	}
+	cfg := &config.Configuration{
+		LoaderConfiguration: &config.LoaderConfiguration{Platform: common.PlatformDirigent},
+		DirigentConfiguration: &config.DirigentConfig{
+			DirigentControlPlaneIP: strings.TrimPrefix(server.URL, "http://"),
+			PrepullMode:            "none",
+		},
+		Functions: functions,
+	}
+
+	err := deployment.CreateDeployer(cfg).Deploy(cfg)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "broken-function")
+
+	assert.Equal(t, "10.0.0.1:8080", functions[0].Endpoint)
+	assert.Empty(t, functions[1].Endpoint)
+}
+
+func TestDirigentDeployerWithoutMetadata(t *testing.T) {
+	cfg := &config.Configuration{
+		LoaderConfiguration:   &config.LoaderConfiguration{Platform: common.PlatformDirigent},
+		DirigentConfiguration: &config.DirigentConfig{},
+		Functions:             []*common.Function{{Name: "function"}},
+	}
+
+	assert.ErrorContains(t, deployment.CreateDeployer(cfg).Deploy(cfg), "no Dirigent metadata")
+}
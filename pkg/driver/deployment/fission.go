@@ -0,0 +1,283 @@
+package deployment
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"os/exec"
+	"strconv"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/vhive-serverless/loader/pkg/common"
+	"github.com/vhive-serverless/loader/pkg/config"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	fissionAPIVersion      = "fission.io/v1"
+	fissionEnvironmentName = "invitro-trace-func"
+	fissionManagedByLabel  = "app.kubernetes.io/managed-by"
+	fissionManagedByValue  = "invitro-loader"
+	fissionResourceKinds   = "httptriggers.fission.io,functions.fission.io,packages.fission.io,environments.fission.io"
+
+	// Fission routes requests and specialization calls to port 8888 of the environment container
+	fissionFunctionPort = 8888
+)
+
+type fissionDeployer struct {
+	cfg *config.FissionConfig
+}
+
+type fissionObject struct {
+	APIVersion string          `yaml:"apiVersion"`
+	Kind       string          `yaml:"kind"`
+	Metadata   fissionMetadata `yaml:"metadata"`
+	Spec       any             `yaml:"spec"`
+	Status     any             `yaml:"status,omitempty"`
+}
+
+type fissionMetadata struct {
+	Name      string            `yaml:"name"`
+	Namespace string            `yaml:"namespace"`
+	Labels    map[string]string `yaml:"labels"`
+}
+
+type fissionReference struct {
+	Name      string `yaml:"name"`
+	Namespace string `yaml:"namespace"`
+}
+
+type fissionResources struct {
+	Requests map[string]string `yaml:"requests,omitempty"`
+	Limits   map[string]string `yaml:"limits,omitempty"`
+}
+
+type fissionEnvVar struct {
+	Name  string `yaml:"name"`
+	Value string `yaml:"value"`
+}
+
+type fissionEnvironmentSpec struct {
+	Version int `yaml:"version"`
+	Runtime struct {
+		Image     string `yaml:"image"`
+		Container struct {
+			Name string          `yaml:"name"`
+			Env  []fissionEnvVar `yaml:"env"`
+		} `yaml:"container"`
+	} `yaml:"runtime"`
+	Resources   fissionResources `yaml:"resources"`
+	PoolSize    int              `yaml:"poolsize"`
+	KeepArchive bool             `yaml:"keeparchive"`
+}
+
+type fissionPackageSpec struct {
+	Environment fissionReference `yaml:"environment"`
+	Deployment  struct {
+		Type    string `yaml:"type"`
+		Literal string `yaml:"literal"`
+	} `yaml:"deployment"`
+}
+
+type fissionFunctionSpec struct {
+	Environment fissionReference `yaml:"environment"`
+	Package     struct {
+		PackageRef   fissionReference `yaml:"packageref"`
+		FunctionName string           `yaml:"functionName"`
+	} `yaml:"package"`
+	Resources      fissionResources `yaml:"resources"`
+	InvokeStrategy struct {
+		ExecutionStrategy struct {
+			ExecutorType          string `yaml:"ExecutorType"`
+			MinScale              int    `yaml:"MinScale"`
+			MaxScale              int    `yaml:"MaxScale"`
+			SpecializationTimeout int    `yaml:"SpecializationTimeout"`
+		} `yaml:"ExecutionStrategy"`
+		StrategyType string `yaml:"StrategyType"`
+	} `yaml:"InvokeStrategy"`
+	FunctionTimeout int `yaml:"functionTimeout"`
+	// mimics the container concurrency of 1 used for Knative
+	RequestsPerPod int `yaml:"requestsPerPod"`
+}
+
+type fissionHTTPTriggerSpec struct {
+	RelativeURL string   `yaml:"relativeurl"`
+	Methods     []string `yaml:"methods"`
+	FunctionRef struct {
+		Type string `yaml:"type"`
+		Name string `yaml:"name"`
+	} `yaml:"functionref"`
+}
+
+func newFissionDeployer() *fissionDeployer {
+	return &fissionDeployer{}
+}
+
+// Deploy applies the Fission manifests of all functions with kubectl.
+func (d *fissionDeployer) Deploy(cfg *config.Configuration) error {
+	d.cfg = cfg.FissionConfiguration
+
+	manifests, err := RenderFissionManifests(d.cfg, cfg.Functions, cfg.LoaderConfiguration.GRPCFunctionTimeoutSeconds)
+	if err != nil {
+		return fmt.Errorf("failed to render Fission manifests - %w", err)
+	}
+
+	cmd := exec.Command("kubectl", "apply", "-f", "-")
+	cmd.Stdin = bytes.NewReader(manifests)
+
+	stdoutStderr, err := cmd.CombinedOutput()
+	log.Debug("CMD response: ", string(stdoutStderr))
+	if err != nil {
+		return fmt.Errorf("failed to apply Fission manifests - %w\n%s", err, stdoutStderr)
+	}
+
+	for _, function := range cfg.Functions {
+		function.Endpoint = fmt.Sprintf("%s/%s", d.cfg.RouterURL, function.Name)
+	}
+
+	log.Infof("Deployed %d functions on Fission with the %s executor", len(cfg.Functions), d.cfg.ExecutorType)
+
+	return nil
+}
+
+func (d *fissionDeployer) Clean() {
+	cmd := exec.Command("kubectl", "delete", fissionResourceKinds,
+		"-n", d.cfg.Namespace, "-l", fissionManagedByLabel+"="+fissionManagedByValue)
+
+	stdoutStderr, err := cmd.CombinedOutput()
+	log.Debug("CMD response: ", string(stdoutStderr))
+	if err != nil {
+		log.Warnf("Failed to clean Fission functions: %v\n%s", err, stdoutStderr)
+	}
+}
+
+// Render writes the manifests that would be applied on deployment.
+func (d *fissionDeployer) Render(cfg *config.Configuration, outputDir string) error {
+	manifests, err := RenderFissionManifests(cfg.FissionConfiguration, cfg.Functions, cfg.LoaderConfiguration.GRPCFunctionTimeoutSeconds)
+	if err != nil {
+		return err
+	}
+
+	return writeArtifact(outputDir, "fission.yaml", manifests)
+}
+
+// RenderFissionManifests returns the environment shared by all functions, followed by the package, the function
+// and the HTTP trigger of each function, as a multi-document YAML.
+func RenderFissionManifests(cfg *config.FissionConfig, functions []*common.Function, functionTimeout int) ([]byte, error) {
+	var buffer bytes.Buffer
+	encoder := yaml.NewEncoder(&buffer)
+	encoder.SetIndent(2)
+
+	objects := []fissionObject{fissionEnvironment(cfg, functions)}
+	for _, function := range functions {
+		objects = append(objects,
+			fissionPackage(cfg, function),
+			fissionFunction(cfg, function, functionTimeout),
+			fissionHTTPTrigger(cfg, function),
+		)
+	}
+
+	for _, object := range objects {
+		if err := encoder.Encode(object); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := encoder.Close(); err != nil {
+		return nil, err
+	}
+
+	return buffer.Bytes(), nil
+}
+
+func newFissionObject(cfg *config.FissionConfig, kind string, name string, spec any) fissionObject {
+	return fissionObject{
+		APIVersion: fissionAPIVersion,
+		Kind:       kind,
+		Metadata: fissionMetadata{
+			Name:      name,
+			Namespace: cfg.Namespace,
+			Labels:    map[string]string{fissionManagedByLabel: fissionManagedByValue},
+		},
+		Spec: spec,
+	}
+}
+
+func fissionFunctionResources(function *common.Function) fissionResources {
+	return fissionResources{
+		Requests: map[string]string{
+			"cpu":    strconv.Itoa(function.CPURequestsMilli) + "m",
+			"memory": strconv.Itoa(function.MemoryRequestsMiB) + "Mi",
+		},
+		Limits: map[string]string{
+			"cpu": strconv.Itoa(function.CPULimitsMilli) + "m",
+		},
+	}
+}
+
+func fissionEnvironment(cfg *config.FissionConfig, functions []*common.Function) fissionObject {
+	spec := fissionEnvironmentSpec{
+		Version:  3,
+		PoolSize: cfg.PoolSize,
+	}
+	spec.Runtime.Image = cfg.Image
+	spec.Runtime.Container.Name = fissionEnvironmentName
+	spec.Runtime.Container.Env = []fissionEnvVar{
+		{Name: "FUNC_PROTOCOL_ENV", Value: "http"},
+		{Name: "FUNC_PORT_ENV", Value: strconv.Itoa(fissionFunctionPort)},
+		{Name: "ITERATIONS_MULTIPLIER", Value: "102"},
+	}
+
+	// NOTE: pool manager pods are shared by all functions, so the resources of the first function are used,
+	// which are the same for all functions with the 1vCPU limit
+	if len(functions) > 0 {
+		spec.Resources = fissionFunctionResources(functions[0])
+	}
+
+	return newFissionObject(cfg, "Environment", fissionEnvironmentName, spec)
+}
+
+func fissionPackage(cfg *config.FissionConfig, function *common.Function) fissionObject {
+	spec := fissionPackageSpec{
+		Environment: fissionReference{Name: fissionEnvironmentName, Namespace: cfg.Namespace},
+	}
+	// the trace function is baked into the environment image, so the package only holds a placeholder
+	spec.Deployment.Type = "literal"
+	spec.Deployment.Literal = base64.StdEncoding.EncodeToString([]byte(function.Name))
+
+	object := newFissionObject(cfg, "Package", function.Name, spec)
+	object.Status = map[string]string{"buildstatus": "none"}
+
+	return object
+}
+
+func fissionFunction(cfg *config.FissionConfig, function *common.Function, functionTimeout int) fissionObject {
+	spec := fissionFunctionSpec{
+		Environment:     fissionReference{Name: fissionEnvironmentName, Namespace: cfg.Namespace},
+		Resources:       fissionFunctionResources(function),
+		FunctionTimeout: functionTimeout,
+		RequestsPerPod:  1,
+	}
+	spec.Package.PackageRef = fissionReference{Name: function.Name, Namespace: cfg.Namespace}
+	spec.Package.FunctionName = function.Name
+
+	strategy := &spec.InvokeStrategy.ExecutionStrategy
+	strategy.ExecutorType = cfg.ExecutorType
+	strategy.MinScale = function.InitialScale
+	strategy.MaxScale = common.MaxOf(cfg.MaxScale, function.InitialScale)
+	strategy.SpecializationTimeout = 120
+	spec.InvokeStrategy.StrategyType = "execution"
+
+	return newFissionObject(cfg, "Function", function.Name, spec)
+}
+
+func fissionHTTPTrigger(cfg *config.FissionConfig, function *common.Function) fissionObject {
+	spec := fissionHTTPTriggerSpec{
+		RelativeURL: "/" + function.Name,
+		Methods:     []string{"POST"},
+	}
+	spec.FunctionRef.Type = "name"
+	spec.FunctionRef.Name = function.Name
+
+	return newFissionObject(cfg, "HTTPTrigger", function.Name, spec)
+}
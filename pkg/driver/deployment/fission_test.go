@@ -0,0 +1,80 @@
+package deployment_test
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vhive-serverless/loader/pkg/common"
+	"github.com/vhive-serverless/loader/pkg/config"
+	"github.com/vhive-serverless/loader/pkg/driver/deployment"
+	"gopkg.in/yaml.v3"
+)
+
+func TestRenderFissionManifests(t *testing.T) {
+	cfg := &config.FissionConfig{
+		RouterURL:    "http://router.fission",
+		Namespace:    "fission-function",
+		Image:        "ghcr.io/vhive-serverless/invitro_trace_function:latest",
+		ExecutorType: common.FissionExecutorNewDeploy,
+		PoolSize:     3,
+		MaxScale:     10,
+	}
+	functions := []*common.Function{
+		{Name: "trace-func-0", CPURequestsMilli: 100, CPULimitsMilli: 1000, MemoryRequestsMiB: 128, InitialScale: 2},
+		{Name: "trace-func-1", CPURequestsMilli: 100, CPULimitsMilli: 1000, MemoryRequestsMiB: 256, InitialScale: 0},
+	}
+
+	manifests, err := deployment.RenderFissionManifests(cfg, functions, 900)
+	require.NoError(t, err)
+
+	var objects []map[string]any
+	decoder := yaml.NewDecoder(bytes.NewReader(manifests))
+	for {
+		var object map[string]any
+		if err := decoder.Decode(&object); errors.Is(err, io.EOF) {
+			break
+		} else {
+			require.NoError(t, err)
+		}
+		objects = append(objects, object)
+	}
+
+	require.Len(t, objects, 1+3*len(functions))
+	for _, object := range objects {
+		metadata := object["metadata"].(map[string]any)
+		assert.Equal(t, "fission.io/v1", object["apiVersion"])
+		assert.Equal(t, "fission-function", metadata["namespace"])
+		assert.Equal(t, "invitro-loader", metadata["labels"].(map[string]any)["app.kubernetes.io/managed-by"])
+	}
+
+	environment := objects[0]["spec"].(map[string]any)
+	assert.Equal(t, "Environment", objects[0]["kind"])
+	assert.Equal(t, 3, environment["poolsize"])
+	assert.Equal(t, cfg.Image, environment["runtime"].(map[string]any)["image"])
+
+	kinds := []string{"Package", "Function", "HTTPTrigger"}
+	for i, object := range objects[1:] {
+		assert.Equal(t, kinds[i%3], object["kind"])
+		assert.Equal(t, functions[i/3].Name, object["metadata"].(map[string]any)["name"])
+	}
+
+	function := objects[2]["spec"].(map[string]any)
+	resources := function["resources"].(map[string]any)
+	strategy := function["InvokeStrategy"].(map[string]any)["ExecutionStrategy"].(map[string]any)
+	assert.Equal(t, "100m", resources["requests"].(map[string]any)["cpu"])
+	assert.Equal(t, "128Mi", resources["requests"].(map[string]any)["memory"])
+	assert.Equal(t, "1000m", resources["limits"].(map[string]any)["cpu"])
+	assert.Equal(t, "newdeploy", strategy["ExecutorType"])
+	assert.Equal(t, 2, strategy["MinScale"])
+	assert.Equal(t, 10, strategy["MaxScale"])
+	assert.Equal(t, 900, function["functionTimeout"])
+	assert.Equal(t, 1, function["requestsPerPod"])
+
+	trigger := objects[3]["spec"].(map[string]any)
+	assert.Equal(t, "/trace-func-0", trigger["relativeurl"])
+	assert.Equal(t, "trace-func-0", trigger["functionref"].(map[string]any)["name"])
+}
@@ -0,0 +1,22 @@
+package deployment
+
+import (
+	log "github.com/sirupsen/logrus"
+	"github.com/vhive-serverless/loader/pkg/config"
+)
+
+// genericHTTPDeployer does not deploy anything, as functions behind a generic HTTP gateway are expected to be
+// deployed before the experiment starts.
+type genericHTTPDeployer struct{}
+
+func newGenericHTTPDeployer() *genericHTTPDeployer {
+	return &genericHTTPDeployer{}
+}
+
+func (g *genericHTTPDeployer) Deploy(cfg *config.Configuration) error {
+	log.Infof("Assuming %d functions are already deployed behind %s", len(cfg.Functions), cfg.GenericHTTPConfiguration.URLTemplate)
+
+	return nil
+}
+
+func (g *genericHTTPDeployer) Clean() {}
@@ -1,57 +1,179 @@
 package deployment
 
 import (
-	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
 	"fmt"
+	"io"
 	"math"
-	"os/exec"
-	"regexp"
+	"os"
+	"path/filepath"
 	"runtime"
 	"strconv"
 	"sync"
+	"time"
 
-	"github.com/go-cmd/cmd"
 	log "github.com/sirupsen/logrus"
 	"github.com/vhive-serverless/loader/pkg/common"
 	"github.com/vhive-serverless/loader/pkg/config"
+	"gopkg.in/yaml.v3"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/wait"
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/discovery/cached/memory"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/restmapper"
+	"k8s.io/client-go/tools/clientcmd"
+	servingv1 "knative.dev/serving/pkg/apis/serving/v1"
+	servingclientset "knative.dev/serving/pkg/client/clientset/versioned"
 )
 
 const (
 	bareMetalLbGateway = "10.200.3.4.sslip.io" // Address of the bare-metal load balancer.
 	namespace          = "default"
+
+	knativeManagedByLabel = "app.kubernetes.io/managed-by"
+	knativeManagedByValue = "invitro-loader"
+	knativeRunLabel       = "loader.vhive-serverless/run"
+
+	knativeInitialScaleAnnotation = "autoscaling.knative.dev/initial-scale"
+
+	defaultKnativeReadyTimeout = 10 * time.Minute
+	knativeReadyPollInterval   = time.Second
+	kubernetesRequestTimeout   = 30 * time.Second
 )
 
-var (
-	urlRegex = regexp.MustCompile("at URL:\nhttp://([^\n]+)")
+// stages at which the deployment of a Knative service may fail
+const (
+	KnativeStageConnect   = "connect to the cluster of"
+	KnativeStagePredeploy = "predeploy"
+	KnativeStageRender    = "render"
+	KnativeStageApply     = "apply"
+	KnativeStageReady     = "wait for readiness of"
 )
 
-type knativeDeployer struct{}
+var ErrKnativeServiceNotReady = errors.New("service did not become ready in time")
+
+// KnativeDeploymentError reports the function and the stage at which its deployment failed. The function is empty if
+// the deployment of all functions failed.
+type KnativeDeploymentError struct {
+	Function string
+	Stage    string
+	Err      error
+}
+
+func (e *KnativeDeploymentError) Error() string {
+	if e.Function == "" {
+		return fmt.Sprintf("failed to %s Knative services - %v", e.Stage, e.Err)
+	}
+
+	return fmt.Sprintf("failed to %s Knative service %s - %v", e.Stage, e.Function, e.Err)
+}
+
+func (e *KnativeDeploymentError) Unwrap() error {
+	return e.Err
+}
+
+// knativeDeployer deploys Knative services through the Kubernetes API. Services are labeled with the ID of the
+// loader run, so that only the services of this run are deleted on cleanup.
+type knativeDeployer struct {
+	runID string
+
+	// created on the first deployment, as rendering does not require a cluster
+	serving servingclientset.Interface
+	dynamic dynamic.Interface
+	mapper  meta.RESTMapper
+
+	mutex sync.Mutex
+	// objects applied from each predeployment file
+	predeployed map[string][]*unstructured.Unstructured
+}
 
 type knativeDeploymentConfiguration struct {
 	IsPartiallyPanic  bool
 	EndpointPort      int
 	AutoscalingMetric string
+	ReadyTimeout      time.Duration
 }
 
 func newKnativeDeployer() *knativeDeployer {
-	return &knativeDeployer{}
+	id := make([]byte, 4)
+	_, _ = rand.Read(id)
+
+	return &knativeDeployer{
+		runID:       hex.EncodeToString(id),
+		predeployed: make(map[string][]*unstructured.Unstructured),
+	}
+}
+
+// connect creates the clients of the cluster configured in the kubeconfig file, as kubectl does, or of the cluster
+// the loader runs in.
+func (d *knativeDeployer) connect() error {
+	if d.serving != nil {
+		return nil
+	}
+
+	restConfig, err := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(
+		clientcmd.NewDefaultClientConfigLoadingRules(), &clientcmd.ConfigOverrides{}).ClientConfig()
+	if err != nil {
+		return err
+	}
+	restConfig.Timeout = kubernetesRequestTimeout
+
+	serving, err := servingclientset.NewForConfig(restConfig)
+	if err != nil {
+		return err
+	}
+	dynamicClient, err := dynamic.NewForConfig(restConfig)
+	if err != nil {
+		return err
+	}
+	discoveryClient, err := discovery.NewDiscoveryClientForConfig(restConfig)
+	if err != nil {
+		return err
+	}
+
+	d.serving = serving
+	d.dynamic = dynamicClient
+	d.mapper = restmapper.NewDeferredDiscoveryRESTMapper(memory.NewMemCacheClient(discoveryClient))
+
+	return nil
 }
 
 func newKnativeDeployerConfiguration(cfg *config.Configuration) knativeDeploymentConfiguration {
+	readyTimeout := time.Duration(cfg.LoaderConfiguration.KnativeReadyTimeoutSeconds) * time.Second
+	if readyTimeout <= 0 {
+		readyTimeout = defaultKnativeReadyTimeout
+	}
+
 	return knativeDeploymentConfiguration{
 		IsPartiallyPanic:  cfg.LoaderConfiguration.IsPartiallyPanic,
 		EndpointPort:      cfg.LoaderConfiguration.EndpointPort,
 		AutoscalingMetric: cfg.LoaderConfiguration.AutoscalingMetric,
+		ReadyTimeout:      readyTimeout,
 	}
 }
 
-func (*knativeDeployer) Deploy(cfg *config.Configuration) {
+// Deploy deploys the Knative services of all functions and waits for them to become ready. The returned error joins
+// the KnativeDeploymentError of each function that could not be deployed.
+func (d *knativeDeployer) Deploy(cfg *config.Configuration) error {
+	if err := d.connect(); err != nil {
+		return &KnativeDeploymentError{Stage: KnativeStageConnect, Err: err}
+	}
+
 	knativeConfig := newKnativeDeployerConfiguration(cfg)
 
 	queue := make(chan struct{}, runtime.NumCPU()) // message queue as a sync method
 	deployed := sync.WaitGroup{}
 	deployed.Add(len(cfg.Functions))
 
+	errs := make([]error, len(cfg.Functions))
 	for i := 0; i < len(cfg.Functions); i++ {
 		go func() {
 			queue <- struct{}{}
@@ -59,99 +181,345 @@ func (*knativeDeployer) Deploy(cfg *config.Configuration) {
 			defer deployed.Done()
 			defer func() { <-queue }()
 
-			knativeDeploySingleFunction(
-				cfg.Functions[i],
-				cfg.Functions[i].YAMLPath,
-				knativeConfig.IsPartiallyPanic,
-				knativeConfig.EndpointPort,
-				knativeConfig.AutoscalingMetric,
-			)
+			errs[i] = d.deploySingleFunction(context.Background(), cfg.Functions[i], knativeConfig)
 		}()
 	}
 
 	deployed.Wait()
+
+	if err := errors.Join(errs...); err != nil {
+		return err
+	}
+
+	log.Infof("Deployed %d Knative services with run label %s=%s", len(cfg.Functions), knativeRunLabel, d.runID)
+
+	return nil
 }
 
-func (*knativeDeployer) Clean() {
-	cmd := exec.Command("kn", "service", "delete", "--all")
+func (d *knativeDeployer) Clean() {
+	if d.serving == nil {
+		return
+	}
+	ctx := context.Background()
 
-	var out bytes.Buffer
-	cmd.Stdout = &out
+	services, err := d.serving.ServingV1().Services(metav1.NamespaceAll).List(ctx, metav1.ListOptions{
+		LabelSelector: knativeRunLabel + "=" + d.runID,
+	})
+	if err != nil {
+		log.Errorf("Unable to list Knative services - %v", err)
+	} else {
+		for _, service := range services.Items {
+			err = d.serving.ServingV1().Services(service.Namespace).Delete(ctx, service.Name, metav1.DeleteOptions{})
+			if err != nil && !apierrors.IsNotFound(err) {
+				log.Errorf("Unable to delete Knative service %s - %v", service.Name, err)
+			}
+		}
+	}
 
-	if err := cmd.Run(); err != nil {
-		log.Errorf("Unable to delete Knative services - %s", err)
+	for path, objects := range d.predeployed {
+		for _, object := range objects {
+			resource, err := d.resourceFor(object)
+			if err == nil {
+				err = resource.Delete(ctx, object.GetName(), metav1.DeleteOptions{})
+			}
+			if err != nil && !apierrors.IsNotFound(err) {
+				log.Errorf("Unable to clean up %s %s of predeployment %s - %v", object.GetKind(), object.GetName(), path, err)
+			}
+		}
 	}
-	preDepCmd := exec.Command("kubectl", "delete", "pods", "--all")
-	preDepCmd.Stdout = &out
-	if err := preDepCmd.Run(); err != nil {
-		log.Error("Unable to clean up predeployment files")
+}
+
+// predeploy applies the objects of the predeployment file, e.g., the databases accessed by the function, with
+// server-side apply.
+func (d *knativeDeployer) predeploy(ctx context.Context, path string) error {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	// predeployments may be shared by several functions
+	if _, ok := d.predeployed[path]; ok {
+		return nil
+	}
+
+	objects, err := readManifests(path)
+	if err != nil {
+		return err
 	}
-	preDepCmd = exec.Command("kubectl", "delete", "services", "--all")
-	preDepCmd.Stdout = &out
-	if err := preDepCmd.Run(); err != nil {
-		log.Error("Unable to clean up predeployment files")
+
+	for _, object := range objects {
+		resource, err := d.resourceFor(object)
+		if err != nil {
+			return err
+		}
+
+		_, err = resource.Apply(ctx, object.GetName(), object, metav1.ApplyOptions{FieldManager: knativeManagedByValue, Force: true})
+		if err != nil {
+			return fmt.Errorf("failed to apply %s %s - %w", object.GetKind(), object.GetName(), err)
+		}
+	}
+
+	d.predeployed[path] = objects
+	return nil
+}
+
+// resourceFor returns the client of the resource of the object, in the default namespace unless set.
+func (d *knativeDeployer) resourceFor(object *unstructured.Unstructured) (dynamic.ResourceInterface, error) {
+	gvk := object.GroupVersionKind()
+	mapping, err := d.mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+	if err != nil {
+		return nil, err
+	}
+
+	if mapping.Scope.Name() == meta.RESTScopeNameRoot {
+		return d.dynamic.Resource(mapping.Resource), nil
+	}
+	if object.GetNamespace() == "" {
+		object.SetNamespace(namespace)
+	}
+
+	return d.dynamic.Resource(mapping.Resource).Namespace(object.GetNamespace()), nil
+}
+
+// readManifests reads the objects of a YAML or JSON file, which may contain several documents.
+func readManifests(path string) ([]*unstructured.Unstructured, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var objects []*unstructured.Unstructured
+	decoder := utilyaml.NewYAMLOrJSONDecoder(file, 4096)
+	for {
+		var object map[string]any
+		if err = decoder.Decode(&object); errors.Is(err, io.EOF) {
+			return objects, nil
+		} else if err != nil {
+			return nil, err
+		}
+
+		// empty documents
+		if len(object) > 0 {
+			objects = append(objects, &unstructured.Unstructured{Object: object})
+		}
 	}
 }
 
-func knativeDeploySingleFunction(function *common.Function, yamlPath string, isPartiallyPanic bool, endpointPort int, autoscalingMetric string) bool {
+func (d *knativeDeployer) deploySingleFunction(ctx context.Context, function *common.Function, knativeConfig knativeDeploymentConfiguration) error {
+	for _, path := range function.PredeploymentPath {
+		if err := d.predeploy(ctx, path); err != nil {
+			return &KnativeDeploymentError{Function: function.Name, Stage: KnativeStagePredeploy, Err: err}
+		}
+	}
+
+	rendered, err := renderKnativeService(function, knativeConfig, d.runID)
+	if err != nil {
+		return &KnativeDeploymentError{Function: function.Name, Stage: KnativeStageRender, Err: err}
+	}
+	service := &servingv1.Service{}
+	if err = utilyaml.Unmarshal(rendered, service); err != nil {
+		return &KnativeDeploymentError{Function: function.Name, Stage: KnativeStageRender, Err: err}
+	}
+
+	if err = d.applyService(ctx, service); err != nil {
+		return &KnativeDeploymentError{Function: function.Name, Stage: KnativeStageApply, Err: err}
+	}
+
+	ready, err := d.awaitService(ctx, service.Namespace, service.Name, knativeConfig.ReadyTimeout)
+	if err != nil {
+		log.Debugf("Service %s not ready - %v", function.Name, err)
+		return &KnativeDeploymentError{Function: function.Name, Stage: KnativeStageReady, Err: ErrKnativeServiceNotReady}
+	}
+
+	endpoint := fmt.Sprintf("%s.%s.%s", function.Name, service.Namespace, bareMetalLbGateway)
+	if ready.Status.URL != nil && ready.Status.URL.Host != "" {
+		endpoint = ready.Status.URL.Host
+	} else {
+		log.Debugf("Service %s reports no URL, using %s", function.Name, endpoint)
+	}
+
+	// adding port to the endpoint
+	function.Endpoint = fmt.Sprintf("%s:%d", endpoint, knativeConfig.EndpointPort)
+	log.Debugf("Deployed function on %s\n", function.Endpoint)
+
+	return nil
+}
+
+// applyService creates the service, or updates it if it already exists, e.g., left over from a previous run.
+func (d *knativeDeployer) applyService(ctx context.Context, service *servingv1.Service) error {
+	services := d.serving.ServingV1().Services(service.Namespace)
+
+	_, err := services.Create(ctx, service, metav1.CreateOptions{FieldManager: knativeManagedByValue})
+	if !apierrors.IsAlreadyExists(err) {
+		return err
+	}
+
+	existing, err := services.Get(ctx, service.Name, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	service.ResourceVersion = existing.ResourceVersion
+
+	_, err = services.Update(ctx, service, metav1.UpdateOptions{FieldManager: knativeManagedByValue})
+	return err
+}
+
+// awaitService polls the service until its latest revision is ready, failing early if the service failed.
+func (d *knativeDeployer) awaitService(ctx context.Context, namespace string, name string, timeout time.Duration) (*servingv1.Service, error) {
+	var ready *servingv1.Service
+
+	err := wait.PollUntilContextTimeout(ctx, knativeReadyPollInterval, timeout, true, func(ctx context.Context) (bool, error) {
+		service, err := d.serving.ServingV1().Services(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			log.Debugf("Failed to get service %s - %v", name, err)
+			return false, nil
+		}
+
+		if service.IsFailed() {
+			return false, fmt.Errorf("service failed - %s", service.Status.GetCondition(servingv1.ServiceConditionReady).GetMessage())
+		}
+		if service.IsReady() {
+			ready = service
+			return true, nil
+		}
+
+		return false, nil
+	})
+
+	return ready, err
+}
+
+// Render writes the Knative service of each function and the predeployments they require. Rendered services are not
+// labeled with a run ID.
+func (d *knativeDeployer) Render(cfg *config.Configuration, outputDir string) error {
+	knativeConfig := newKnativeDeployerConfiguration(cfg)
+
+	for _, function := range cfg.Functions {
+		for _, path := range function.PredeploymentPath {
+			predeployment, err := os.ReadFile(path)
+			if err != nil {
+				return &KnativeDeploymentError{Function: function.Name, Stage: KnativeStagePredeploy, Err: err}
+			}
+			if err = writeArtifact(outputDir, filepath.Join("predeployment", filepath.Base(path)), predeployment); err != nil {
+				return err
+			}
+		}
+
+		service, err := renderKnativeService(function, knativeConfig, "")
+		if err != nil {
+			return &KnativeDeploymentError{Function: function.Name, Stage: KnativeStageRender, Err: err}
+		}
+		if err = writeArtifact(outputDir, function.Name+".yaml", service); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// RenderKnativeService returns the Knative service of the function, rendered from its YAML template, as it would be
+// applied by the Knative deployer.
+func RenderKnativeService(cfg *config.Configuration, function *common.Function, runID string) ([]byte, error) {
+	return renderKnativeService(function, newKnativeDeployerConfiguration(cfg), runID)
+}
+
+func renderKnativeService(function *common.Function, knativeConfig knativeDeploymentConfiguration, runID string) ([]byte, error) {
+	template, err := os.ReadFile(function.YAMLPath)
+	if err != nil {
+		return nil, err
+	}
+
 	panicWindow := "\"10.0\""
 	panicThreshold := "\"200.0\""
-	if isPartiallyPanic {
+	if knativeConfig.IsPartiallyPanic {
 		panicWindow = "\"100.0\""
 		panicThreshold = "\"1000.0\""
 	}
 	autoscalingTarget := 100 // default for concurrency
-	if autoscalingMetric == "rps" {
+	if knativeConfig.AutoscalingMetric == "rps" {
 		autoscalingTarget = int(math.Round(1000.0 / function.RuntimeStats.Average))
 		// for rps mode use the average runtime in milliseconds to determine how many requests a pod can process per
 		// second, then round to an integer as that is what the knative config expects
 	}
-	for _, path := range function.PredeploymentPath {
-		envCmd := cmd.NewCmd("kubectl", "apply", "-f", path)
-		status := <-envCmd.Start()
 
-		for _, line := range status.Stdout {
-			fmt.Println("Predeployment command response is" + line)
+	variables := map[string]string{
+		"FUNC_NAME":               function.Name,
+		"CPU_REQUEST":             strconv.Itoa(function.CPURequestsMilli) + "m",
+		"CPU_LIMITS":              strconv.Itoa(function.CPULimitsMilli) + "m",
+		"MEMORY_REQUESTS":         strconv.Itoa(function.MemoryRequestsMiB) + "Mi",
+		"PANIC_WINDOW":            panicWindow,
+		"PANIC_THRESHOLD":         panicThreshold,
+		"AUTOSCALING_METRIC":      wrapString(knativeConfig.AutoscalingMetric),
+		"AUTOSCALING_TARGET":      wrapString(strconv.Itoa(autoscalingTarget)),
+		"COLD_START_BUSY_LOOP_MS": wrapString(strconv.Itoa(function.ColdStartBusyLoopMs)),
+	}
+	// same semantics as envsubst, which templates were written for
+	rendered := os.Expand(string(template), func(name string) string {
+		if value, ok := variables[name]; ok {
+			return value
 		}
+		return os.Getenv(name)
+	})
+
+	var service map[string]any
+	if err = yaml.Unmarshal([]byte(rendered), &service); err != nil {
+		return nil, err
 	}
-	cmd := exec.Command(
-		"bash",
-		"./pkg/driver/deployment/knative.sh",
-		yamlPath,
-		function.Name,
 
-		strconv.Itoa(function.CPURequestsMilli)+"m",
-		strconv.Itoa(function.CPULimitsMilli)+"m",
-		strconv.Itoa(function.MemoryRequestsMiB)+"Mi",
-		strconv.Itoa(function.InitialScale),
-		panicWindow,
-		panicThreshold,
+	metadata := yamlMapping(service, "metadata")
+	metadata["name"] = function.Name
+	if metadata["namespace"] == nil {
+		metadata["namespace"] = namespace
+	}
+	setRunLabels(yamlMapping(metadata, "labels"), runID)
 
-		wrapString(autoscalingMetric),
-		wrapString(strconv.Itoa(autoscalingTarget)),
+	revisionMetadata := yamlMapping(yamlMapping(yamlMapping(service, "spec"), "template"), "metadata")
+	setRunLabels(yamlMapping(revisionMetadata, "labels"), runID)
+	annotations := yamlMapping(revisionMetadata, "annotations")
+	annotations[knativeInitialScaleAnnotation] = strconv.Itoa(function.InitialScale)
+	if function.Autoscaling != nil {
+		setAutoscalingAnnotations(annotations, function.Autoscaling)
+	}
 
-		wrapString(strconv.Itoa(function.ColdStartBusyLoopMs)),
-	)
+	return yaml.Marshal(service)
+}
 
-	stdoutStderr, err := cmd.CombinedOutput()
-	log.Debug("CMD response: ", string(stdoutStderr))
-	if err != nil {
-		// TODO: there should be a toggle to turn off deployment because if this is fatal then we cannot test the thing locally
-		log.Warnf("Failed to deploy function %s: %v\n%s\n", function.Name, err, stdoutStderr)
-		return false
-	}
-	if endpoint := urlRegex.FindStringSubmatch(string(stdoutStderr))[1]; endpoint != function.Endpoint {
-		// TODO: check when this situation happens
-		log.Debugf("Update function endpoint to %s\n", endpoint)
-		function.Endpoint = endpoint
-	} else {
-		function.Endpoint = fmt.Sprintf("%s.%s.%s", function.Name, namespace, bareMetalLbGateway)
+// setAutoscalingAnnotations overrides the autoscaling annotations of the template with the settings of the function.
+func setAutoscalingAnnotations(annotations map[string]any, settings *common.AutoscalingSettings) {
+	annotations["autoscaling.knative.dev/min-scale"] = strconv.Itoa(settings.MinScale)
+	if settings.MaxScale > 0 {
+		annotations["autoscaling.knative.dev/max-scale"] = strconv.Itoa(settings.MaxScale)
+	}
+	if settings.Metric != "" {
+		annotations["autoscaling.knative.dev/metric"] = settings.Metric
+	}
+	if settings.Target > 0 {
+		annotations["autoscaling.knative.dev/target"] = strconv.FormatFloat(settings.Target, 'f', -1, 64)
+	}
+	if settings.StableWindowSeconds > 0 {
+		annotations["autoscaling.knative.dev/window"] = fmt.Sprintf("%ds", settings.StableWindowSeconds)
+	}
+	if settings.ScaleToZeroRetentionSeconds > 0 {
+		annotations["autoscaling.knative.dev/scale-to-zero-pod-retention-period"] = fmt.Sprintf("%ds", settings.ScaleToZeroRetentionSeconds)
+	}
+}
+
+// yamlMapping returns the mapping under the key, creating it if missing.
+func yamlMapping(parent map[string]any, key string) map[string]any {
+	if child, ok := parent[key].(map[string]any); ok {
+		return child
 	}
-	// adding port to the endpoint
-	function.Endpoint = fmt.Sprintf("%s:%d", function.Endpoint, endpointPort)
-	log.Debugf("Deployed function on %s\n", function.Endpoint)
 
-	return true
+	child := make(map[string]any)
+	parent[key] = child
+
+	return child
+}
+
+func setRunLabels(labels map[string]any, runID string) {
+	labels[knativeManagedByLabel] = knativeManagedByValue
+	if runID != "" {
+		labels[knativeRunLabel] = runID
+	}
 }
 
 func wrapString(value string) string {
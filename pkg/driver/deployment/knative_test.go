@@ -0,0 +1,175 @@
+package deployment_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vhive-serverless/loader/pkg/common"
+	"github.com/vhive-serverless/loader/pkg/config"
+	"github.com/vhive-serverless/loader/pkg/driver/deployment"
+	"gopkg.in/yaml.v3"
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
+	servingv1 "knative.dev/serving/pkg/apis/serving/v1"
+)
+
+func TestRenderKnativeService(t *testing.T) {
+	cfg := &config.Configuration{LoaderConfiguration: &config.LoaderConfiguration{
+		IsPartiallyPanic:  true,
+		AutoscalingMetric: "rps",
+	}}
+	function := &common.Function{
+		Name:                "trace-func-0",
+		YAMLPath:            "../../../workloads/container/trace_func_go.yaml",
+		CPURequestsMilli:    100,
+		CPULimitsMilli:      1000,
+		MemoryRequestsMiB:   128,
+		InitialScale:        2,
+		ColdStartBusyLoopMs: 50,
+		RuntimeStats:        &common.FunctionRuntimeStats{Average: 250},
+	}
+
+	rendered, err := deployment.RenderKnativeService(cfg, function, "run-0")
+	require.NoError(t, err)
+
+	var service struct {
+		Metadata struct {
+			Name      string            `yaml:"name"`
+			Namespace string            `yaml:"namespace"`
+			Labels    map[string]string `yaml:"labels"`
+		} `yaml:"metadata"`
+		Spec struct {
+			Template struct {
+				Metadata struct {
+					Labels      map[string]string `yaml:"labels"`
+					Annotations map[string]string `yaml:"annotations"`
+				} `yaml:"metadata"`
+				Spec struct {
+					Containers []struct {
+						Env []struct {
+							Name  string `yaml:"name"`
+							Value string `yaml:"value"`
+						} `yaml:"env"`
+						Resources struct {
+							Limits   map[string]string `yaml:"limits"`
+							Requests map[string]string `yaml:"requests"`
+						} `yaml:"resources"`
+					} `yaml:"containers"`
+				} `yaml:"spec"`
+			} `yaml:"template"`
+		} `yaml:"spec"`
+	}
+	require.NoError(t, yaml.Unmarshal(rendered, &service))
+
+	assert.Equal(t, "trace-func-0", service.Metadata.Name)
+	assert.Equal(t, "default", service.Metadata.Namespace)
+	assert.Equal(t, "run-0", service.Metadata.Labels["loader.vhive-serverless/run"])
+	assert.Equal(t, "invitro-loader", service.Spec.Template.Metadata.Labels["app.kubernetes.io/managed-by"])
+
+	annotations := service.Spec.Template.Metadata.Annotations
+	assert.Equal(t, "2", annotations["autoscaling.knative.dev/initial-scale"])
+	assert.Equal(t, "100.0", annotations["autoscaling.knative.dev/panic-window-percentage"])
+	assert.Equal(t, "rps", annotations["autoscaling.knative.dev/metric"])
+	assert.Equal(t, "4", annotations["autoscaling.knative.dev/target"])
+
+	require.Len(t, service.Spec.Template.Spec.Containers, 1)
+	container := service.Spec.Template.Spec.Containers[0]
+	assert.Equal(t, "1000m", container.Resources.Limits["cpu"])
+	assert.Equal(t, "100m", container.Resources.Requests["cpu"])
+	assert.Equal(t, "128Mi", container.Resources.Requests["memory"])
+	for _, env := range container.Env {
+		if env.Name == "COLD_START_BUSY_LOOP_MS" {
+			assert.Equal(t, "50", env.Value)
+		}
+	}
+}
+
+func TestRenderKnativeServiceAutoscaling(t *testing.T) {
+	cfg := &config.Configuration{LoaderConfiguration: &config.LoaderConfiguration{AutoscalingMetric: "concurrency"}}
+	function := &common.Function{
+		Name:         "trace-func-0",
+		YAMLPath:     "../../../workloads/container/trace_func_go.yaml",
+		RuntimeStats: &common.FunctionRuntimeStats{Average: 250},
+		Autoscaling: &common.AutoscalingSettings{
+			MinScale:                    1,
+			MaxScale:                    12,
+			Metric:                      "concurrency",
+			Target:                      0.7,
+			StableWindowSeconds:         30,
+			ScaleToZeroRetentionSeconds: 120,
+		},
+	}
+
+	rendered, err := deployment.RenderKnativeService(cfg, function, "run-0")
+	require.NoError(t, err)
+
+	var service struct {
+		Spec struct {
+			Template struct {
+				Metadata struct {
+					Annotations map[string]string `yaml:"annotations"`
+				} `yaml:"metadata"`
+			} `yaml:"template"`
+		} `yaml:"spec"`
+	}
+	require.NoError(t, yaml.Unmarshal(rendered, &service))
+
+	annotations := service.Spec.Template.Metadata.Annotations
+	assert.Equal(t, "1", annotations["autoscaling.knative.dev/min-scale"])
+	assert.Equal(t, "12", annotations["autoscaling.knative.dev/max-scale"])
+	assert.Equal(t, "concurrency", annotations["autoscaling.knative.dev/metric"])
+	assert.Equal(t, "0.7", annotations["autoscaling.knative.dev/target"])
+	assert.Equal(t, "30s", annotations["autoscaling.knative.dev/window"])
+	assert.Equal(t, "120s", annotations["autoscaling.knative.dev/scale-to-zero-pod-retention-period"])
+}
+
+func TestKnativeDeploymentError(t *testing.T) {
+	var err error = &deployment.KnativeDeploymentError{
+		Function: "trace-func-0",
+		Stage:    deployment.KnativeStageReady,
+		Err:      deployment.ErrKnativeServiceNotReady,
+	}
+
+	var deploymentErr *deployment.KnativeDeploymentError
+	require.True(t, errors.As(err, &deploymentErr))
+	assert.Equal(t, "trace-func-0", deploymentErr.Function)
+	assert.True(t, errors.Is(err, deployment.ErrKnativeServiceNotReady))
+}
+
+func TestRenderKnativeServiceDecodes(t *testing.T) {
+	cfg := &config.Configuration{LoaderConfiguration: &config.LoaderConfiguration{AutoscalingMetric: "concurrency"}}
+	function := &common.Function{
+		Name:         "trace-func-0",
+		YAMLPath:     "../../../workloads/container/trace_func_go.yaml",
+		RuntimeStats: &common.FunctionRuntimeStats{Average: 250},
+	}
+
+	rendered, err := deployment.RenderKnativeService(cfg, function, "run-0")
+	require.NoError(t, err)
+
+	service := &servingv1.Service{}
+	require.NoError(t, utilyaml.Unmarshal(rendered, service))
+
+	assert.Equal(t, "trace-func-0", service.Name)
+	assert.Equal(t, "default", service.Namespace)
+	assert.Equal(t, "run-0", service.Labels["loader.vhive-serverless/run"])
+	assert.Len(t, service.Spec.Template.Spec.Containers, 1)
+}
+
+func TestKnativeDeployWithoutCluster(t *testing.T) {
+	t.Setenv("KUBECONFIG", t.TempDir()+"/missing")
+	t.Setenv("KUBERNETES_SERVICE_HOST", "")
+
+	cfg := &config.Configuration{
+		LoaderConfiguration: &config.LoaderConfiguration{Platform: common.PlatformKnative},
+		Functions:           []*common.Function{{Name: "trace-func-0"}},
+	}
+
+	err := deployment.CreateDeployer(cfg).Deploy(cfg)
+
+	var deploymentErr *deployment.KnativeDeploymentError
+	require.True(t, errors.As(err, &deploymentErr))
+	assert.Equal(t, deployment.KnativeStageConnect, deploymentErr.Stage)
+	assert.Empty(t, deploymentErr.Function)
+}
@@ -0,0 +1,377 @@
+package deployment
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/vhive-serverless/loader/pkg/config"
+)
+
+// time a terminated process is given to finish its in-flight invocations before being killed
+const localShutdownGracePeriod = 5 * time.Second
+
+// localDeployer runs the trace function as one local process per function. Each function is served on a stable
+// ephemeral port by a TCP proxy, which starts the process on demand and lets the reaper kill it once idle for longer
+// than the keep-alive, so that functions scale to zero and back like on a FaaS platform.
+type localDeployer struct {
+	cfg *config.LocalConfig
+	// holds the built binary, removed on clean
+	workDir   string
+	binary    string
+	functions []*localFunction
+
+	stopReaper chan struct{}
+	reaperDone chan struct{}
+}
+
+type localFunction struct {
+	name     string
+	binary   string
+	env      []string
+	cfg      *config.LocalConfig
+	listener net.Listener
+
+	mutex   sync.Mutex
+	process *localProcess
+	// µs since epoch of the last bytes forwarded in each direction, a request is in flight while the last request
+	// bytes are more recent than the last response bytes
+	lastRequest  atomic.Int64
+	lastResponse atomic.Int64
+}
+
+type localProcess struct {
+	cmd     *exec.Cmd
+	address string
+	exited  chan struct{}
+}
+
+func newLocalDeployer() *localDeployer {
+	return &localDeployer{}
+}
+
+func (d *localDeployer) Deploy(cfg *config.Configuration) error {
+	if cfg.LocalConfiguration == nil {
+		return errors.New("local configuration is required for platform 'local'")
+	}
+	d.cfg = cfg.LocalConfiguration
+
+	var err error
+	d.workDir, err = os.MkdirTemp("", "loader-local-")
+	if err != nil {
+		return fmt.Errorf("failed to create the working directory of the local platform - %w", err)
+	}
+
+	d.binary = d.cfg.BinaryPath
+	if d.binary == "" {
+		d.binary = filepath.Join(d.workDir, "trace-func")
+		if err = buildLocalFunction(d.cfg.SourcePath, d.binary); err != nil {
+			d.Clean()
+			return fmt.Errorf("failed to build the trace function - %w", err)
+		}
+	}
+
+	env := []string{"FUNC_TYPE_ENV=TRACE"}
+	if cfg.LoaderConfiguration.InvokeProtocol != "grpc" {
+		env = append(env, "FUNC_PROTOCOL_ENV=http")
+	}
+	if d.cfg.IterationsMultiplier > 0 {
+		env = append(env, "ITERATIONS_MULTIPLIER="+strconv.Itoa(d.cfg.IterationsMultiplier))
+	}
+
+	for _, function := range cfg.Functions {
+		f, err := d.deployFunction(function.Name, env)
+		if err != nil {
+			d.Clean()
+			return fmt.Errorf("failed to deploy function %s locally - %w", function.Name, err)
+		}
+
+		if function.InitialScale > 0 {
+			f.mutex.Lock()
+			_, err = f.ensureRunning()
+			f.mutex.Unlock()
+			if err != nil {
+				// stops the processes of the functions started so far, which would otherwise outlive the loader
+				d.Clean()
+				return fmt.Errorf("failed to start function %s - %w", function.Name, err)
+			}
+		}
+
+		function.Endpoint = f.listener.Addr().String()
+		if cfg.LoaderConfiguration.InvokeProtocol != "grpc" {
+			function.Endpoint = "http://" + function.Endpoint
+		}
+		log.Debugf("Deployed function %s on %s", function.Name, function.Endpoint)
+	}
+
+	d.stopReaper = make(chan struct{})
+	d.reaperDone = make(chan struct{})
+	go d.reap()
+
+	log.Infof("Deployed %d functions as local processes", len(cfg.Functions))
+
+	return nil
+}
+
+func (d *localDeployer) Clean() {
+	if d.stopReaper != nil {
+		close(d.stopReaper)
+		<-d.reaperDone
+		d.stopReaper = nil
+	}
+
+	for _, f := range d.functions {
+		_ = f.listener.Close()
+
+		f.mutex.Lock()
+		f.stop()
+		f.mutex.Unlock()
+	}
+	d.functions = nil
+
+	if d.workDir != "" {
+		if err := os.RemoveAll(d.workDir); err != nil {
+			log.Warnf("Failed to remove the working directory of the local platform: %v", err)
+		}
+		d.workDir = ""
+	}
+}
+
+func buildLocalFunction(sourcePath string, binary string) error {
+	log.Infof("Building the trace function from %s", sourcePath)
+
+	cmd := exec.Command("go", "build", "-o", binary, sourcePath)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("%w\n%s", err, output)
+	}
+
+	return nil
+}
+
+func (d *localDeployer) deployFunction(name string, env []string) (*localFunction, error) {
+	listener, err := net.Listen("tcp", net.JoinHostPort(d.cfg.Address, "0"))
+	if err != nil {
+		return nil, err
+	}
+
+	f := &localFunction{
+		name:     name,
+		binary:   d.binary,
+		env:      env,
+		cfg:      d.cfg,
+		listener: listener,
+	}
+	d.functions = append(d.functions, f)
+	go f.serve()
+
+	return f, nil
+}
+
+// reap periodically kills the processes of functions idle for longer than the keep-alive.
+func (d *localDeployer) reap() {
+	defer close(d.reaperDone)
+
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+
+	keepAlive := time.Duration(d.cfg.KeepAliveSeconds) * time.Second
+	for {
+		select {
+		case <-d.stopReaper:
+			return
+		case now := <-ticker.C:
+			for _, f := range d.functions {
+				f.mutex.Lock()
+				if f.process != nil && f.idleSince(now) > keepAlive {
+					log.Debugf("Scaling function %s to zero", f.name)
+					f.stop()
+				}
+				f.mutex.Unlock()
+			}
+		}
+	}
+}
+
+func (f *localFunction) idleSince(now time.Time) time.Duration {
+	lastRequest, lastResponse := f.lastRequest.Load(), f.lastResponse.Load()
+	if lastRequest > lastResponse {
+		return 0
+	}
+
+	return now.Sub(time.UnixMicro(lastResponse))
+}
+
+// serve accepts connections to the function and forwards them to its process, starting it if needed.
+func (f *localFunction) serve() {
+	for {
+		conn, err := f.listener.Accept()
+		if err != nil {
+			if !errors.Is(err, net.ErrClosed) {
+				log.Warnf("Failed to accept connection to function %s: %v", f.name, err)
+			}
+			return
+		}
+
+		go f.forward(conn)
+	}
+}
+
+func (f *localFunction) forward(conn net.Conn) {
+	defer conn.Close()
+
+	// the connection counts as activity, so that the process is not reaped while starting
+	f.touch(&f.lastResponse)
+	f.mutex.Lock()
+	process, err := f.ensureRunning()
+	f.mutex.Unlock()
+	if err != nil {
+		log.Warnf("Failed to start function %s: %v", f.name, err)
+		return
+	}
+
+	backend, err := net.Dial("tcp", process.address)
+	if err != nil {
+		log.Debugf("Failed to connect to function %s: %v", f.name, err)
+		return
+	}
+	defer backend.Close()
+
+	done := make(chan struct{}, 2)
+	go f.copy(backend, conn, &f.lastRequest, done)
+	go f.copy(conn, backend, &f.lastResponse, done)
+
+	// either side closing, e.g., the process being reaped, closes the connection
+	select {
+	case <-done:
+	case <-process.exited:
+	}
+}
+
+func (f *localFunction) copy(dst net.Conn, src net.Conn, activity *atomic.Int64, done chan<- struct{}) {
+	buffer := make([]byte, 32*1024)
+	for {
+		n, err := src.Read(buffer)
+		if n > 0 {
+			f.touch(activity)
+			if _, writeErr := dst.Write(buffer[:n]); writeErr != nil {
+				break
+			}
+		}
+		if err != nil {
+			if !errors.Is(err, io.EOF) && !errors.Is(err, net.ErrClosed) {
+				log.Tracef("Connection to function %s closed: %v", f.name, err)
+			}
+			break
+		}
+	}
+
+	done <- struct{}{}
+}
+
+func (f *localFunction) touch(activity *atomic.Int64) {
+	activity.Store(time.Now().UnixMicro())
+}
+
+// ensureRunning starts the process of the function if it is not running and waits until it accepts connections.
+// Must be called with the mutex held.
+func (f *localFunction) ensureRunning() (*localProcess, error) {
+	if f.process != nil {
+		select {
+		case <-f.process.exited:
+			log.Warnf("Process of function %s exited unexpectedly", f.name)
+			f.process = nil
+		default:
+			return f.process, nil
+		}
+	}
+
+	port, err := freePort(f.cfg.Address)
+	if err != nil {
+		return nil, err
+	}
+
+	cmd := exec.Command(f.binary)
+	cmd.Env = append(os.Environ(), f.env...)
+	cmd.Env = append(cmd.Env, "FUNC_PORT_ENV="+strconv.Itoa(port))
+	if log.IsLevelEnabled(log.DebugLevel) {
+		cmd.Stdout = os.Stderr
+		cmd.Stderr = os.Stderr
+	}
+	if err = cmd.Start(); err != nil {
+		return nil, err
+	}
+
+	process := &localProcess{
+		cmd:     cmd,
+		address: net.JoinHostPort(f.cfg.Address, strconv.Itoa(port)),
+		exited:  make(chan struct{}),
+	}
+	go func() {
+		_ = cmd.Wait()
+		close(process.exited)
+	}()
+	f.process = process
+
+	deadline := time.Now().Add(time.Duration(f.cfg.StartupTimeoutSeconds) * time.Second)
+	for {
+		conn, err := net.DialTimeout("tcp", process.address, time.Second)
+		if err == nil {
+			_ = conn.Close()
+			f.touch(&f.lastResponse)
+			log.Debugf("Started function %s on %s", f.name, process.address)
+
+			return process, nil
+		}
+
+		select {
+		case <-process.exited:
+			f.process = nil
+			return nil, fmt.Errorf("process exited during startup")
+		case <-time.After(10 * time.Millisecond):
+		}
+
+		if time.Now().After(deadline) {
+			f.stop()
+			return nil, fmt.Errorf("process did not accept connections within %d seconds", f.cfg.StartupTimeoutSeconds)
+		}
+	}
+}
+
+// stop terminates the process of the function, killing it if it does not exit within the grace period. Must be
+// called with the mutex held.
+func (f *localFunction) stop() {
+	if f.process == nil {
+		return
+	}
+
+	_ = f.process.cmd.Process.Signal(syscall.SIGTERM)
+	select {
+	case <-f.process.exited:
+	case <-time.After(localShutdownGracePeriod):
+		_ = f.process.cmd.Process.Kill()
+		<-f.process.exited
+	}
+
+	f.process = nil
+}
+
+// freePort returns a port the operating system considers free on the address.
+func freePort(address string) (int, error) {
+	listener, err := net.Listen("tcp", net.JoinHostPort(address, "0"))
+	if err != nil {
+		return 0, err
+	}
+	defer listener.Close()
+
+	return listener.Addr().(*net.TCPAddr).Port, nil
+}
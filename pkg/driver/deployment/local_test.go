@@ -0,0 +1,123 @@
+package deployment_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vhive-serverless/loader/pkg/common"
+	"github.com/vhive-serverless/loader/pkg/config"
+	"github.com/vhive-serverless/loader/pkg/driver/deployment"
+	"github.com/vhive-serverless/loader/pkg/workload/standard"
+)
+
+// TestLocalFunctionProcess is not a test, but the trace function launched by the local deployer in TestLocalDeployer.
+func TestLocalFunctionProcess(t *testing.T) {
+	if os.Getenv("LOADER_LOCAL_FUNCTION_PROCESS") != "1" {
+		t.Skip("Only run as a function process")
+	}
+
+	port, _ := strconv.Atoi(os.Getenv("FUNC_PORT_ENV"))
+	standard.StartHTTPServer("127.0.0.1", port, standard.TraceFunction)
+	os.Exit(0)
+}
+
+func invokeLocalFunction(t *testing.T, endpoint string) bool {
+	response, err := http.Post(endpoint, "application/json", bytes.NewBufferString(`{"RuntimeInMilliSec": 10, "MemoryInMebiBytes": 16}`))
+	require.NoError(t, err)
+	defer response.Body.Close()
+	require.Equal(t, http.StatusOK, response.StatusCode)
+
+	var reply struct{ ColdStart bool }
+	require.NoError(t, json.NewDecoder(response.Body).Decode(&reply))
+
+	return reply.ColdStart
+}
+
+func TestLocalDeployer(t *testing.T) {
+	// runs this test binary as the function process
+	binary := filepath.Join(t.TempDir(), "trace-func")
+	script := fmt.Sprintf("#!/bin/sh\nLOADER_LOCAL_FUNCTION_PROCESS=1 exec %s -test.run=^TestLocalFunctionProcess$\n", os.Args[0])
+	require.NoError(t, os.WriteFile(binary, []byte(script), 0755))
+
+	cfg := &config.Configuration{
+		LoaderConfiguration: &config.LoaderConfiguration{Platform: common.PlatformLocal, InvokeProtocol: "http1"},
+		LocalConfiguration: &config.LocalConfig{
+			BinaryPath:            binary,
+			Address:               "127.0.0.1",
+			KeepAliveSeconds:      1,
+			StartupTimeoutSeconds: 30,
+			IterationsMultiplier:  1,
+		},
+		Functions: []*common.Function{
+			{Name: "warm", InitialScale: 1},
+			{Name: "cold", InitialScale: 0},
+		},
+	}
+
+	deployer := deployment.CreateDeployer(cfg)
+	require.NoError(t, deployer.Deploy(cfg))
+	defer deployer.Clean()
+
+	warm, cold := cfg.Functions[0], cfg.Functions[1]
+	assert.Regexp(t, `^http://127\.0\.0\.1:\d+$`, warm.Endpoint)
+	assert.NotEqual(t, warm.Endpoint, cold.Endpoint)
+
+	// the first request is the first one served by either process, but only the cold function waits for its start
+	assert.True(t, invokeLocalFunction(t, cold.Endpoint))
+	assert.False(t, invokeLocalFunction(t, cold.Endpoint))
+
+	// idle processes are killed after the keep-alive and restarted on the next request
+	time.Sleep(3 * time.Second)
+	assert.True(t, invokeLocalFunction(t, cold.Endpoint), "Process should have been scaled to zero")
+
+	deployer.Clean()
+	_, err := http.Post(warm.Endpoint, "application/json", bytes.NewBufferString(`{}`))
+	assert.Error(t, err, "Functions should not be served after clean")
+}
+
+func TestLocalDeployerStartFailure(t *testing.T) {
+	// the first process records its PID and serves the function, the following ones exit during their startup
+	dir := t.TempDir()
+	binary, pidFile := filepath.Join(dir, "trace-func"), filepath.Join(dir, "pid")
+	script := fmt.Sprintf("#!/bin/sh\n[ -e %[1]s ] && exit 1\necho $$ > %[1]s\nLOADER_LOCAL_FUNCTION_PROCESS=1 exec %[2]s -test.run=^TestLocalFunctionProcess$\n", pidFile, os.Args[0])
+	require.NoError(t, os.WriteFile(binary, []byte(script), 0755))
+
+	cfg := &config.Configuration{
+		LoaderConfiguration: &config.LoaderConfiguration{Platform: common.PlatformLocal, InvokeProtocol: "http1"},
+		LocalConfiguration: &config.LocalConfig{
+			BinaryPath:            binary,
+			Address:               "127.0.0.1",
+			KeepAliveSeconds:      60,
+			StartupTimeoutSeconds: 30,
+		},
+		Functions: []*common.Function{
+			{Name: "started", InitialScale: 1},
+			{Name: "failing", InitialScale: 1},
+		},
+	}
+
+	deployer := deployment.CreateDeployer(cfg)
+	err := deployer.Deploy(cfg)
+	require.ErrorContains(t, err, "failed to start function failing")
+	defer deployer.Clean()
+
+	// the process of the function started before the failure does not outlive the deployment
+	content, err := os.ReadFile(pidFile)
+	require.NoError(t, err)
+	pid, err := strconv.Atoi(string(bytes.TrimSpace(content)))
+	require.NoError(t, err)
+	assert.ErrorIs(t, syscall.Kill(pid, 0), syscall.ESRCH)
+
+	_, err = http.Post(cfg.Functions[0].Endpoint, "application/json", bytes.NewBufferString(`{}`))
+	assert.Error(t, err, "Functions should not be served after a failed deployment")
+}
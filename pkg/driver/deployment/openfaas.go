@@ -0,0 +1,182 @@
+package deployment
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/vhive-serverless/loader/pkg/common"
+	"github.com/vhive-serverless/loader/pkg/config"
+)
+
+const (
+	openFaaSFunctionsPath = "/system/functions"
+	openFaaSMinScaleLabel = "com.openfaas.scale.min"
+
+	// of-watchdog and faas-netes route requests to port 8080 of the function container
+	openFaaSFunctionPort = 8080
+)
+
+type openFaaSDeployer struct {
+	cfg       *config.OpenFaaSConfig
+	client    *http.Client
+	functions []*common.Function
+}
+
+// openFaaSFunctionDeployment is the request body of the OpenFaaS gateway function deployment API.
+type openFaaSFunctionDeployment struct {
+	Service   string             `json:"service"`
+	Image     string             `json:"image"`
+	Namespace string             `json:"namespace,omitempty"`
+	EnvVars   map[string]string  `json:"envVars,omitempty"`
+	Labels    map[string]string  `json:"labels,omitempty"`
+	Limits    *openFaaSResources `json:"limits,omitempty"`
+	Requests  *openFaaSResources `json:"requests,omitempty"`
+}
+
+type openFaaSResources struct {
+	Memory string `json:"memory,omitempty"`
+	CPU    string `json:"cpu,omitempty"`
+}
+
+type openFaaSDeleteFunctionRequest struct {
+	FunctionName string `json:"functionName"`
+	Namespace    string `json:"namespace,omitempty"`
+}
+
+func newOpenFaaSDeployer() *openFaaSDeployer {
+	return &openFaaSDeployer{
+		client: &http.Client{Timeout: 60 * time.Second},
+	}
+}
+
+// Deploy deploys each function through the gateway. The returned error joins the errors of the functions that could
+// not be deployed.
+func (d *openFaaSDeployer) Deploy(cfg *config.Configuration) error {
+	d.cfg = cfg.OpenFaaSConfiguration
+	d.functions = cfg.Functions
+
+	var errs []error
+	for _, function := range d.functions {
+		if err := d.deployFunction(function); err != nil {
+			errs = append(errs, fmt.Errorf("failed to deploy function %s on OpenFaaS - %w", function.Name, err))
+			continue
+		}
+
+		function.Endpoint = d.functionURL(function)
+		log.Debugf("Deployed function %s on %s", function.Name, function.Endpoint)
+	}
+
+	return errors.Join(errs...)
+}
+
+// Render writes the request body of the deployment of each function to the gateway.
+func (d *openFaaSDeployer) Render(cfg *config.Configuration, outputDir string) error {
+	d.cfg = cfg.OpenFaaSConfiguration
+
+	for _, function := range cfg.Functions {
+		data, err := json.MarshalIndent(d.functionDeployment(function), "", "  ")
+		if err != nil {
+			return err
+		}
+		if err = writeArtifact(outputDir, function.Name+".json", data); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (d *openFaaSDeployer) deployFunction(function *common.Function) error {
+	deployment := d.functionDeployment(function)
+
+	status, err := d.request(http.MethodPost, deployment)
+	if status == http.StatusConflict {
+		// the function exists from a previous run, in which case it gets updated
+		log.Debugf("Function %s already exists, updating it - %v", function.Name, err)
+		_, err = d.request(http.MethodPut, deployment)
+	}
+
+	return err
+}
+
+func (d *openFaaSDeployer) functionDeployment(function *common.Function) openFaaSFunctionDeployment {
+	return openFaaSFunctionDeployment{
+		Service:   function.Name,
+		Image:     d.cfg.Image,
+		Namespace: d.cfg.Namespace,
+		EnvVars: map[string]string{
+			"FUNC_PROTOCOL_ENV":       "http",
+			"FUNC_PORT_ENV":           strconv.Itoa(openFaaSFunctionPort),
+			"ITERATIONS_MULTIPLIER":   "102",
+			"COLD_START_BUSY_LOOP_MS": strconv.Itoa(function.ColdStartBusyLoopMs),
+		},
+		Labels: map[string]string{
+			openFaaSMinScaleLabel: strconv.Itoa(common.MaxOf(1, function.InitialScale)),
+		},
+		// the trace only sets a memory request, which also limits the memory of the function
+		Limits: &openFaaSResources{
+			CPU:    strconv.Itoa(function.CPULimitsMilli) + "m",
+			Memory: strconv.Itoa(function.MemoryRequestsMiB) + "Mi",
+		},
+		Requests: &openFaaSResources{
+			CPU:    strconv.Itoa(function.CPURequestsMilli) + "m",
+			Memory: strconv.Itoa(function.MemoryRequestsMiB) + "Mi",
+		},
+	}
+}
+
+func (d *openFaaSDeployer) functionURL(function *common.Function) string {
+	if d.cfg.Namespace == "" {
+		return fmt.Sprintf("%s/function/%s", d.cfg.GatewayURL, function.Name)
+	}
+
+	return fmt.Sprintf("%s/function/%s.%s", d.cfg.GatewayURL, function.Name, d.cfg.Namespace)
+}
+
+// request sends the body to the function management API and returns the status code of the response, which is 0 if
+// none was received.
+func (d *openFaaSDeployer) request(method string, body any) (int, error) {
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return 0, err
+	}
+
+	req, err := http.NewRequest(method, d.cfg.GatewayURL+openFaaSFunctionsPath, bytes.NewReader(payload))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.SetBasicAuth(d.cfg.Username, d.cfg.Password)
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		message, _ := io.ReadAll(resp.Body)
+		return resp.StatusCode, fmt.Errorf("%s %s returned %s - %s", method, openFaaSFunctionsPath, resp.Status, string(message))
+	}
+
+	return resp.StatusCode, nil
+}
+
+func (d *openFaaSDeployer) Clean() {
+	for _, function := range d.functions {
+		_, err := d.request(http.MethodDelete, openFaaSDeleteFunctionRequest{
+			FunctionName: function.Name,
+			Namespace:    d.cfg.Namespace,
+		})
+		if err != nil {
+			log.Warnf("Failed to delete function %s: %v", function.Name, err)
+		}
+	}
+}
@@ -0,0 +1,140 @@
+package deployment_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vhive-serverless/loader/pkg/common"
+	"github.com/vhive-serverless/loader/pkg/config"
+	"github.com/vhive-serverless/loader/pkg/driver/deployment"
+)
+
+// fakeOpenFaaSGateway stands in for the function management API of the OpenFaaS gateway.
+type fakeOpenFaaSGateway struct {
+	mutex     sync.Mutex
+	functions map[string]map[string]any
+	deleted   []string
+}
+
+func (g *fakeOpenFaaSGateway) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if user, password, ok := r.BasicAuth(); !ok || user != "admin" || password != "secret" {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	if r.URL.Path != "/system/functions" {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	var body map[string]any
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+
+	switch r.Method {
+	case http.MethodPost:
+		name := body["service"].(string)
+		if name == "broken-function" {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		if _, ok := g.functions[name]; ok {
+			w.WriteHeader(http.StatusConflict)
+			return
+		}
+		g.functions[name] = body
+	case http.MethodPut:
+		g.functions[body["service"].(string)] = body
+	case http.MethodDelete:
+		g.deleted = append(g.deleted, body["functionName"].(string))
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	w.WriteHeader(http.StatusAccepted)
+}
+
+func TestOpenFaaSDeployer(t *testing.T) {
+	gateway := &fakeOpenFaaSGateway{
+		functions: map[string]map[string]any{"existing-function": {}},
+	}
+	server := httptest.NewServer(gateway)
+	defer server.Close()
+
+	functions := []*common.Function{
+		{Name: "new-function", InitialScale: 3, CPURequestsMilli: 100, CPULimitsMilli: 1000, MemoryRequestsMiB: 12},
+		{Name: "existing-function", InitialScale: 0, CPURequestsMilli: 100, CPULimitsMilli: 1000, MemoryRequestsMiB: 12},
+		{Name: "broken-function"},
+	}
+	cfg := &config.Configuration{
+		LoaderConfiguration: &config.LoaderConfiguration{Platform: common.PlatformOpenFaaS},
+		OpenFaaSConfiguration: &config.OpenFaaSConfig{
+			GatewayURL: server.URL,
+			Username:   "admin",
+			Password:   "secret",
+			Image:      "trace-func:test",
+		},
+		Functions: functions,
+	}
+
+	deployer := deployment.CreateDeployer(cfg)
+	err := deployer.Deploy(cfg)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "broken-function")
+	assert.NotContains(t, err.Error(), "existing-function")
+
+	require.Len(t, gateway.functions, 2)
+
+	created := gateway.functions["new-function"]
+	assert.Equal(t, "trace-func:test", created["image"])
+	assert.Equal(t, map[string]any{"com.openfaas.scale.min": "3"}, created["labels"])
+	assert.Equal(t, map[string]any{"cpu": "1000m", "memory": "12Mi"}, created["limits"])
+	assert.Equal(t, map[string]any{"cpu": "100m", "memory": "12Mi"}, created["requests"])
+	assert.Equal(t, "http", created["envVars"].(map[string]any)["FUNC_PROTOCOL_ENV"])
+
+	// existing functions are updated, and scaled to at least one instance
+	updated := gateway.functions["existing-function"]
+	assert.Equal(t, map[string]any{"com.openfaas.scale.min": "1"}, updated["labels"])
+
+	assert.Equal(t, server.URL+"/function/new-function", functions[0].Endpoint)
+	assert.Equal(t, server.URL+"/function/existing-function", functions[1].Endpoint)
+
+	// functions failing to be created for another reason than existing already are not updated instead
+	assert.Empty(t, functions[2].Endpoint)
+
+	deployer.Clean()
+	assert.ElementsMatch(t, []string{"new-function", "existing-function", "broken-function"}, gateway.deleted)
+}
+
+func TestOpenFaaSDeployerUnauthorized(t *testing.T) {
+	gateway := &fakeOpenFaaSGateway{functions: map[string]map[string]any{}}
+	server := httptest.NewServer(gateway)
+	defer server.Close()
+
+	functions := []*common.Function{{Name: "function"}}
+	cfg := &config.Configuration{
+		LoaderConfiguration: &config.LoaderConfiguration{Platform: common.PlatformOpenFaaS},
+		OpenFaaSConfiguration: &config.OpenFaaSConfig{
+			GatewayURL: server.URL,
+			Username:   "admin",
+			Password:   "wrong",
+		},
+		Functions: functions,
+	}
+
+	err := deployment.CreateDeployer(cfg).Deploy(cfg)
+	assert.ErrorContains(t, err, "401 Unauthorized")
+
+	assert.Empty(t, gateway.functions)
+	assert.Empty(t, functions[0].Endpoint)
+}
@@ -19,7 +19,7 @@ func newOpenWhiskDeployer() *openWhiskDeployer {
 	return &openWhiskDeployer{}
 }
 
-func (owd *openWhiskDeployer) Deploy(cfg *config.Configuration) {
+func (owd *openWhiskDeployer) Deploy(cfg *config.Configuration) error {
 	owd.functions = cfg.Functions
 
 	cmd := exec.Command("wsk", "-i", "property", "get", "--apihost")
@@ -29,7 +29,7 @@ func (owd *openWhiskDeployer) Deploy(cfg *config.Configuration) {
 
 	err := cmd.Run()
 	if err != nil {
-		log.Fatalf("Unable to read OpenWhisk API host data - %s", err)
+		return fmt.Errorf("unable to read OpenWhisk API host data - %w", err)
 	}
 	result := strings.Split(out.String(), "\t")
 	endpoint := strings.TrimSpace(result[len(result)-1])
@@ -41,11 +41,13 @@ func (owd *openWhiskDeployer) Deploy(cfg *config.Configuration) {
 
 		err = cmd.Run()
 		if err != nil {
-			log.Fatalf("Unable to create OpenWhisk action for function %s - %s", owd.functions[i].Name, err)
+			return fmt.Errorf("unable to create OpenWhisk action for function %s - %w", owd.functions[i].Name, err)
 		}
 
 		owd.functions[i].Endpoint = fmt.Sprintf("https://%s/api/v1/web/guest/default/%s", endpoint, owd.functions[i].Name)
 	}
+
+	return nil
 }
 
 func (owd *openWhiskDeployer) Clean() {
@@ -0,0 +1,119 @@
+package deployment_test
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vhive-serverless/loader/pkg/common"
+	"github.com/vhive-serverless/loader/pkg/config"
+	"github.com/vhive-serverless/loader/pkg/driver/deployment"
+	"gopkg.in/yaml.v3"
+)
+
+func renderDeployment(t *testing.T, cfg *config.Configuration) string {
+	renderer, ok := deployment.CreateDeployer(cfg).(deployment.ArtifactRenderer)
+	require.True(t, ok, "Deployer of %s should render its artifacts", cfg.LoaderConfiguration.Platform)
+
+	outputDir := t.TempDir()
+	require.NoError(t, renderer.Render(cfg, outputDir))
+
+	return outputDir
+}
+
+func renderTestFunctions() []*common.Function {
+	return []*common.Function{
+		{
+			Name:              "trace-func-0-1234",
+			YAMLPath:          "../../../workloads/container/trace_func_go.yaml",
+			CPURequestsMilli:  100,
+			CPULimitsMilli:    1000,
+			MemoryRequestsMiB: 128,
+			InitialScale:      1,
+			RuntimeStats:      &common.FunctionRuntimeStats{Average: 100},
+			DirigentMetadata: &common.DirigentMetadata{
+				Image:             "docker.io/cvetkovic/dirigent_trace_function:latest",
+				Port:              80,
+				Protocol:          "tcp",
+				ScalingUpperBound: 10,
+			},
+		},
+	}
+}
+
+func TestRenderKnativeDeployment(t *testing.T) {
+	outputDir := renderDeployment(t, &config.Configuration{
+		LoaderConfiguration: &config.LoaderConfiguration{Platform: common.PlatformKnative},
+		Functions:           renderTestFunctions(),
+	})
+
+	data, err := os.ReadFile(filepath.Join(outputDir, "trace-func-0-1234.yaml"))
+	require.NoError(t, err)
+
+	var service map[string]any
+	require.NoError(t, yaml.Unmarshal(data, &service))
+	labels := service["metadata"].(map[string]any)["labels"].(map[string]any)
+	assert.Equal(t, "invitro-loader", labels["app.kubernetes.io/managed-by"])
+	assert.NotContains(t, labels, "loader.vhive-serverless/run")
+}
+
+func TestRenderAWSLambdaDeployment(t *testing.T) {
+	outputDir := renderDeployment(t, &config.Configuration{
+		LoaderConfiguration: &config.LoaderConfiguration{Platform: common.PlatformAWSLambda},
+		Functions:           renderTestFunctions(),
+	})
+
+	data, err := os.ReadFile(filepath.Join(outputDir, "serverless-0.yml"))
+	require.NoError(t, err)
+
+	var serverless deployment.Serverless
+	require.NoError(t, yaml.Unmarshal(data, &serverless))
+	assert.Equal(t, "loader-0", serverless.Service)
+	assert.Contains(t, serverless.Functions, "trace-func-0-1234")
+}
+
+func TestRenderDirigentDeployment(t *testing.T) {
+	functions := renderTestFunctions()
+	functions[0].Autoscaling = &common.AutoscalingSettings{MinScale: 2, MinScaleSet: true, MaxScale: 8}
+
+	outputDir := renderDeployment(t, &config.Configuration{
+		LoaderConfiguration:   &config.LoaderConfiguration{Platform: common.PlatformDirigent},
+		DirigentConfiguration: &config.DirigentConfig{PrepullMode: "none"},
+		Functions:             functions,
+	})
+
+	data, err := os.ReadFile(filepath.Join(outputDir, "trace-func-0-1234.json"))
+	require.NoError(t, err)
+
+	var payload map[string][]string
+	require.NoError(t, json.Unmarshal(data, &payload))
+	assert.Equal(t, []string{"docker.io/cvetkovic/dirigent_trace_function:latest"}, payload["image"])
+	assert.Equal(t, []string{"100"}, payload["requested_cpu"])
+	assert.Equal(t, []string{"128"}, payload["requested_memory"])
+	assert.Equal(t, []string{"2"}, payload["scaling_lower_bound"])
+	assert.Equal(t, []string{"8"}, payload["scaling_upper_bound"])
+}
+
+func TestRenderDirigentDeploymentWithoutMinScale(t *testing.T) {
+	// the trace policy leaves the minimum scale unset, which keeps the scaling lower bound of the function
+	functions := renderTestFunctions()
+	functions[0].DirigentMetadata.ScalingLowerBound = 1
+	functions[0].Autoscaling = &common.AutoscalingSettings{Policy: common.AutoscalingPolicyTrace, MaxScale: 8}
+
+	outputDir := renderDeployment(t, &config.Configuration{
+		LoaderConfiguration:   &config.LoaderConfiguration{Platform: common.PlatformDirigent},
+		DirigentConfiguration: &config.DirigentConfig{PrepullMode: "none"},
+		Functions:             functions,
+	})
+
+	data, err := os.ReadFile(filepath.Join(outputDir, "trace-func-0-1234.json"))
+	require.NoError(t, err)
+
+	var payload map[string][]string
+	require.NoError(t, json.Unmarshal(data, &payload))
+	assert.Equal(t, []string{"1"}, payload["scaling_lower_bound"])
+	assert.Equal(t, []string{"8"}, payload["scaling_upper_bound"])
+}
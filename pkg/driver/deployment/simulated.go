@@ -0,0 +1,56 @@
+package deployment
+
+import (
+	"errors"
+	"fmt"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/vhive-serverless/loader/pkg/config"
+	"github.com/vhive-serverless/loader/pkg/simulator"
+)
+
+// memory of instances of functions without memory requests
+const defaultSimulatedMemoryMiB = 128
+
+// simulatedDeployer registers the functions with the in-process simulated platform.
+type simulatedDeployer struct {
+	platform *simulator.Platform
+}
+
+func newSimulatedDeployer() *simulatedDeployer {
+	return &simulatedDeployer{}
+}
+
+func (s *simulatedDeployer) Deploy(cfg *config.Configuration) error {
+	if cfg.SimulatedConfiguration == nil {
+		return errors.New("simulated configuration is required for platform 'simulated'")
+	}
+	s.platform = simulator.ForConfig(cfg.SimulatedConfiguration)
+
+	for _, function := range cfg.Functions {
+		memoryMiB := function.MemoryRequestsMiB
+		if memoryMiB <= 0 {
+			memoryMiB = defaultSimulatedMemoryMiB
+		}
+
+		minScale := 0
+		if function.Autoscaling != nil {
+			minScale = function.Autoscaling.MinScale
+		}
+
+		if err := s.platform.Deploy(function.Name, memoryMiB, function.InitialScale, minScale); err != nil {
+			return fmt.Errorf("failed to deploy function %s on the simulated platform - %w", function.Name, err)
+		}
+		function.Endpoint = function.Name
+	}
+
+	log.Infof("Deployed %d functions on the simulated platform", len(cfg.Functions))
+
+	return nil
+}
+
+func (s *simulatedDeployer) Clean() {
+	if s.platform != nil {
+		s.platform.Clean()
+	}
+}
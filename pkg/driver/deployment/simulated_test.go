@@ -0,0 +1,19 @@
+package deployment_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vhive-serverless/loader/pkg/common"
+	"github.com/vhive-serverless/loader/pkg/config"
+	"github.com/vhive-serverless/loader/pkg/driver/deployment"
+)
+
+func TestSimulatedDeployerWithoutConfiguration(t *testing.T) {
+	cfg := &config.Configuration{
+		LoaderConfiguration: &config.LoaderConfiguration{Platform: common.PlatformSimulated},
+		Functions:           []*common.Function{{Name: "function"}},
+	}
+
+	assert.Error(t, deployment.CreateDeployer(cfg).Deploy(cfg))
+}
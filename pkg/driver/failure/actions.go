@@ -0,0 +1,200 @@
+package failure
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+
+	"github.com/sirupsen/logrus"
+	"github.com/vhive-serverless/loader/pkg/config"
+)
+
+const (
+	// FunctionPodsFailure deletes a percentage of the pods of a Knative function
+	FunctionPodsFailure = "function_pods"
+	// ActivatorFailure and AutoscalerFailure delete the pods of the Knative component
+	ActivatorFailure  = "activator"
+	AutoscalerFailure = "autoscaler"
+	// NodeDrainFailure cordons and drains the nodes, which are uncordoned on recovery
+	NodeDrainFailure = "node_drain"
+	// SystemDeploymentFailure scales a deployment to zero, which is scaled back on recovery
+	SystemDeploymentFailure = "system_deployment"
+
+	knativeServingNamespace = "knative-serving"
+	knativeServiceLabel     = "serving.knative.dev/service"
+)
+
+// FailureAction injects a failure into the cluster and recovers from it.
+type FailureAction interface {
+	Inject() error
+	// Recover ends the failure once its duration elapsed, if the recovery of the failure event is not 'none'.
+	Recover() error
+}
+
+// newFailureAction returns the action failing the component of the event, executing commands with run and calling
+// the Kubernetes API with client. Actions drawing random numbers get their own source seeded from rng, as the
+// occurrences of the events are injected concurrently.
+func newFailureAction(platform string, event config.FailureEvent, run func([]string) error, client ClusterClient, rng *rand.Rand) (FailureAction, error) {
+	switch event.Component {
+	case FunctionPodsFailure:
+		percentage := event.Percentage
+		if percentage == 0 {
+			percentage = 100
+		}
+		if percentage < 0 || percentage > 100 {
+			return nil, fmt.Errorf("invalid percentage of pods to kill: %.2f", event.Percentage)
+		}
+
+		selector := knativeServiceLabel
+		if event.Function != "" {
+			selector += "=" + event.Function
+		}
+
+		return &killPodsAction{client: client, namespace: namespaceOrDefault(event.Namespace, "default"), selector: selector, percentage: percentage,
+			rng: rand.New(rand.NewSource(rng.Int63()))}, nil
+	case ActivatorFailure, AutoscalerFailure:
+		return &killPodsAction{client: client, namespace: namespaceOrDefault(event.Namespace, knativeServingNamespace), selector: "app=" + event.Component, percentage: 100,
+			rng: rand.New(rand.NewSource(rng.Int63()))}, nil
+	case NodeDrainFailure:
+		if len(event.Nodes) == 0 {
+			return nil, fmt.Errorf("no nodes to drain")
+		}
+
+		return &drainNodesAction{client: client, nodes: event.Nodes}, nil
+	case SystemDeploymentFailure:
+		if event.Deployment == "" {
+			return nil, fmt.Errorf("no deployment to scale to zero")
+		}
+
+		return &scaleDeploymentAction{client: client, namespace: namespaceOrDefault(event.Namespace, knativeServingNamespace), name: event.Deployment}, nil
+	default:
+		commands, err := commandsFor(platform, event.Component, event.DurationSeconds > 0)
+		if err != nil {
+			return nil, err
+		}
+
+		return &commandAction{commands: commands, nodes: event.Nodes, run: run}, nil
+	}
+}
+
+func namespaceOrDefault(namespace string, defaultNamespace string) string {
+	if namespace == "" {
+		return defaultNamespace
+	}
+
+	return namespace
+}
+
+// commandAction fails the component by executing commands locally or over SSH.
+type commandAction struct {
+	commands failureCommands
+	nodes    []string
+	run      func([]string) error
+}
+
+func (a *commandAction) Inject() error {
+	return execute(a.run, a.commands.inject, a.commands.remote, a.nodes)
+}
+
+func (a *commandAction) Recover() error {
+	return execute(a.run, a.commands.recover, a.commands.remote, a.nodes)
+}
+
+// killPodsAction deletes a random percentage of the pods matching the selector, which their controllers replace.
+type killPodsAction struct {
+	client     ClusterClient
+	namespace  string
+	selector   string
+	percentage float64
+	rng        *rand.Rand
+}
+
+func (a *killPodsAction) Inject() error {
+	pods, err := a.client.ListPods(a.namespace, a.selector, "")
+	if err != nil {
+		return err
+	}
+	if len(pods) == 0 {
+		return fmt.Errorf("no pods matching %s in namespace %s", a.selector, a.namespace)
+	}
+
+	count := int(math.Ceil(float64(len(pods)) * a.percentage / 100))
+	a.rng.Shuffle(len(pods), func(i, j int) { pods[i], pods[j] = pods[j], pods[i] })
+
+	var errs []error
+	for _, pod := range pods[:count] {
+		logrus.Debugf("Deleting pod %s/%s", pod.Namespace, pod.Name)
+		errs = append(errs, a.client.DeletePod(pod.Namespace, pod.Name))
+	}
+
+	return errors.Join(errs...)
+}
+
+func (a *killPodsAction) Recover() error {
+	return nil
+}
+
+// drainNodesAction cordons the nodes and evicts their pods, except those of daemon sets and static pods.
+type drainNodesAction struct {
+	client ClusterClient
+	nodes  []string
+}
+
+func (a *drainNodesAction) Inject() error {
+	var errs []error
+	for _, node := range a.nodes {
+		if err := a.client.SetNodeUnschedulable(node, true); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+
+		pods, err := a.client.ListPods("", "", node)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+
+		for _, pod := range pods {
+			if pod.OwnerKind == "DaemonSet" || pod.OwnerKind == "Node" {
+				continue
+			}
+
+			logrus.Debugf("Evicting pod %s/%s from node %s", pod.Namespace, pod.Name, node)
+			errs = append(errs, a.client.EvictPod(pod.Namespace, pod.Name))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func (a *drainNodesAction) Recover() error {
+	var errs []error
+	for _, node := range a.nodes {
+		errs = append(errs, a.client.SetNodeUnschedulable(node, false))
+	}
+
+	return errors.Join(errs...)
+}
+
+// scaleDeploymentAction scales the deployment to zero and restores its replicas on recovery.
+type scaleDeploymentAction struct {
+	client    ClusterClient
+	namespace string
+	name      string
+	replicas  int
+}
+
+func (a *scaleDeploymentAction) Inject() error {
+	replicas, err := a.client.GetDeploymentReplicas(a.namespace, a.name)
+	if err != nil {
+		return err
+	}
+	a.replicas = replicas
+
+	return a.client.ScaleDeployment(a.namespace, a.name, 0)
+}
+
+func (a *scaleDeploymentAction) Recover() error {
+	return a.client.ScaleDeployment(a.namespace, a.name, a.replicas)
+}
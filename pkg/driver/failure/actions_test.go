@@ -0,0 +1,261 @@
+package failure
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/vhive-serverless/loader/pkg/common"
+	"github.com/vhive-serverless/loader/pkg/config"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/utils/ptr"
+)
+
+// newFakeCluster returns a clientset of a cluster with the pods of two functions, the activator, a daemon set and the
+// Knative controller.
+func newFakeCluster() *fake.Clientset {
+	var objects []runtime.Object
+	for i := 0; i < 4; i++ {
+		objects = append(objects, newFakePod("default", fmt.Sprintf("func-a-%d", i), "worker-1", "ReplicaSet", knativeServiceLabel, "func-a"))
+	}
+	objects = append(objects,
+		newFakePod("default", "func-b-0", "worker-2", "ReplicaSet", knativeServiceLabel, "func-b"),
+		newFakePod("knative-serving", "activator-0", "master", "ReplicaSet", "app", "activator"),
+		newFakePod("kube-system", "kube-proxy-0", "worker-1", "DaemonSet", "k8s-app", "kube-proxy"),
+		&corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "worker-1"}},
+		&appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "knative-serving", Name: "controller"},
+			Spec:       appsv1.DeploymentSpec{Replicas: ptr.To[int32](2)},
+		},
+	)
+
+	return fake.NewSimpleClientset(objects...)
+}
+
+func newFakePod(namespace string, name string, node string, ownerKind string, label string, value string) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:       namespace,
+			Name:            name,
+			Labels:          map[string]string{label: value},
+			OwnerReferences: []metav1.OwnerReference{{Kind: ownerKind, Name: name}},
+		},
+		Spec: corev1.PodSpec{NodeName: node},
+	}
+}
+
+// changes returns the calls of the clientset changing the cluster.
+func changes(clientset *fake.Clientset) []string {
+	var calls []string
+	for _, action := range clientset.Actions() {
+		if action.GetVerb() == "get" || action.GetVerb() == "list" {
+			continue
+		}
+
+		call := action.GetVerb() + " " + action.GetResource().Resource
+		if action.GetSubresource() != "" {
+			call += "/" + action.GetSubresource()
+		}
+		if named, ok := action.(interface{ GetName() string }); ok {
+			call += " " + action.GetNamespace() + "/" + named.GetName()
+		}
+		calls = append(calls, call)
+	}
+
+	return calls
+}
+
+func countPods(t *testing.T, clientset *fake.Clientset, namespace string, selector string) int {
+	pods, err := clientset.CoreV1().Pods(namespace).List(context.Background(), metav1.ListOptions{LabelSelector: selector})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return len(pods.Items)
+}
+
+func replicas(t *testing.T, clientset *fake.Clientset) int32 {
+	deployment, err := clientset.AppsV1().Deployments("knative-serving").Get(context.Background(), "controller", metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return *deployment.Spec.Replicas
+}
+
+func unschedulable(t *testing.T, clientset *fake.Clientset, name string) bool {
+	node, err := clientset.CoreV1().Nodes().Get(context.Background(), name, metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return node.Spec.Unschedulable
+}
+
+func newTestAction(t *testing.T, client ClusterClient, event config.FailureEvent) FailureAction {
+	action, err := newFailureAction(common.PlatformKnative, event, nil, client, rand.New(rand.NewSource(42)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return action
+}
+
+func TestKillFunctionPods(t *testing.T) {
+	clientset := newFakeCluster()
+	client := &kubernetesClient{clientset: clientset}
+	action := newTestAction(t, client, config.FailureEvent{Component: FunctionPodsFailure, Function: "func-a", Percentage: 50})
+
+	if err := action.Inject(); err != nil {
+		t.Fatal(err)
+	}
+
+	if calls := changes(clientset); len(calls) != 2 || countPods(t, clientset, "default", knativeServiceLabel+"=func-a") != 2 {
+		t.Errorf("Expected half of the pods of func-a to be deleted, got %v", calls)
+	}
+	if countPods(t, clientset, "default", knativeServiceLabel+"=func-b") != 1 {
+		t.Errorf("Pods of other functions should not be deleted")
+	}
+
+	missing := newTestAction(t, client, config.FailureEvent{Component: FunctionPodsFailure, Function: "func-c"})
+	if err := missing.Inject(); err == nil {
+		t.Error("Expected an error for a function without pods")
+	}
+
+	if _, err := newFailureAction(common.PlatformKnative, config.FailureEvent{Component: FunctionPodsFailure, Percentage: 150}, nil, client, nil); err == nil {
+		t.Error("Expected an error for an invalid percentage")
+	}
+}
+
+func TestKillPodsSources(t *testing.T) {
+	client := &kubernetesClient{clientset: newFakeCluster()}
+
+	// actions built from the same source are injected concurrently by the scheduler
+	rng := rand.New(rand.NewSource(42))
+	var sources []*rand.Rand
+	for _, event := range []config.FailureEvent{
+		{Component: FunctionPodsFailure, Function: "func-a"},
+		{Component: FunctionPodsFailure, Function: "func-b"},
+		{Component: ActivatorFailure},
+	} {
+		action, err := newFailureAction(common.PlatformKnative, event, nil, client, rng)
+		if err != nil {
+			t.Fatal(err)
+		}
+		sources = append(sources, action.(*killPodsAction).rng)
+	}
+
+	if sources[0] == rng || sources[0] == sources[1] || sources[1] == sources[2] || sources[0] == sources[2] {
+		t.Error("Expected each action to have its own random source")
+	}
+}
+
+func TestDeleteActivator(t *testing.T) {
+	clientset := newFakeCluster()
+	action := newTestAction(t, &kubernetesClient{clientset: clientset}, config.FailureEvent{Component: ActivatorFailure})
+
+	if err := action.Inject(); err != nil {
+		t.Fatal(err)
+	}
+	if calls := changes(clientset); !slices.Equal(calls, []string{"delete pods knative-serving/activator-0"}) {
+		t.Errorf("Expected the activator pod to be deleted, got %v", calls)
+	}
+}
+
+func TestDrainNode(t *testing.T) {
+	clientset := newFakeCluster()
+	action := newTestAction(t, &kubernetesClient{clientset: clientset}, config.FailureEvent{Component: NodeDrainFailure, Nodes: []string{"worker-1"}})
+
+	if err := action.Inject(); err != nil {
+		t.Fatal(err)
+	}
+	if !unschedulable(t, clientset, "worker-1") {
+		t.Error("Node should be cordoned")
+	}
+
+	var evicted []string
+	for _, call := range changes(clientset) {
+		if strings.HasPrefix(call, "create pods/eviction") {
+			evicted = append(evicted, call)
+		}
+	}
+	if len(evicted) != 4 || slices.Contains(evicted, "create pods/eviction kube-system/kube-proxy-0") {
+		t.Errorf("Only the pods of func-a should be evicted from the drained node, got %v", evicted)
+	}
+
+	if err := action.Recover(); err != nil {
+		t.Fatal(err)
+	}
+	if unschedulable(t, clientset, "worker-1") {
+		t.Error("Node should be uncordoned on recovery")
+	}
+}
+
+func TestScaleSystemDeployment(t *testing.T) {
+	clientset := newFakeCluster()
+	action := newTestAction(t, &kubernetesClient{clientset: clientset}, config.FailureEvent{Component: SystemDeploymentFailure, Deployment: "controller"})
+
+	if err := action.Inject(); err != nil {
+		t.Fatal(err)
+	}
+	if replicas(t, clientset) != 0 {
+		t.Error("Deployment should be scaled to zero")
+	}
+
+	if err := action.Recover(); err != nil {
+		t.Fatal(err)
+	}
+	if replicas(t, clientset) != 2 {
+		t.Errorf("Deployment should be scaled back to its replicas, got %d", replicas(t, clientset))
+	}
+}
+
+func TestDryRun(t *testing.T) {
+	clientset := newFakeCluster()
+	client := newDryRunClient(&kubernetesClient{clientset: clientset})
+
+	for _, event := range []config.FailureEvent{
+		{Component: FunctionPodsFailure, Function: "func-a"},
+		{Component: AutoscalerFailure},
+		{Component: NodeDrainFailure, Nodes: []string{"worker-1"}},
+		{Component: SystemDeploymentFailure, Deployment: "controller"},
+	} {
+		action := newTestAction(t, client, event)
+		_ = action.Inject()
+		_ = action.Recover()
+	}
+
+	if calls := changes(clientset); len(calls) != 0 || countPods(t, clientset, "", "") != 7 || replicas(t, clientset) != 2 {
+		t.Errorf("Dry runs should not change the cluster, got %v", calls)
+	}
+}
+
+func TestSchedulerActions(t *testing.T) {
+	clientset := newFakeCluster()
+	s := newScheduler(common.PlatformKnative, &config.FailureConfiguration{
+		FailureEnabled: true,
+		Events: []config.FailureEvent{
+			{At: 0, Component: SystemDeploymentFailure, Deployment: "controller", DurationSeconds: 1},
+		},
+	}, time.Minute, nil, &kubernetesClient{clientset: clientset}, rand.New(rand.NewSource(42)))
+
+	s.Start()
+	time.Sleep(1500 * time.Millisecond)
+	records := s.Stop()
+
+	if len(records) != 1 || records[0].Target != "controller" || records[0].Recovery != RecoveryStart || records[0].Error != "" {
+		t.Fatalf("Unexpected failure records %+v", records)
+	}
+	calls := changes(clientset)
+	if !slices.Equal(calls, []string{"patch deployments knative-serving/controller", "patch deployments knative-serving/controller"}) || replicas(t, clientset) != 2 {
+		t.Errorf("Deployment should be scaled to zero and back, got %v", calls)
+	}
+}
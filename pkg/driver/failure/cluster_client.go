@@ -0,0 +1,195 @@
+package failure
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	policyv1 "k8s.io/api/policy/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+const kubernetesRequestTimeout = 30 * time.Second
+
+// Pod is a pod of the cluster targeted by a failure action.
+type Pod struct {
+	Namespace string
+	Name      string
+	Node      string
+	// kind of the owner of the pod, e.g., ReplicaSet or DaemonSet
+	OwnerKind string
+}
+
+// ClusterClient is the subset of the Kubernetes API used by the failure actions.
+type ClusterClient interface {
+	// ListPods returns the pods matching the label selector, in all namespaces if the namespace is empty, and on the
+	// given node if not empty.
+	ListPods(namespace string, labelSelector string, node string) ([]Pod, error)
+	DeletePod(namespace string, name string) error
+	// EvictPod evicts the pod through the eviction API, which honors pod disruption budgets.
+	EvictPod(namespace string, name string) error
+
+	GetDeploymentReplicas(namespace string, name string) (int, error)
+	ScaleDeployment(namespace string, name string, replicas int) error
+
+	SetNodeUnschedulable(node string, unschedulable bool) error
+}
+
+// kubernetesClient calls the Kubernetes API of the cluster in the kubeconfig file, as for the deployment of the
+// functions. It connects on its first call, so that schedules without Kubernetes actions do not require a cluster.
+type kubernetesClient struct {
+	once      sync.Once
+	clientset kubernetes.Interface
+	err       error
+}
+
+func newKubernetesClient() *kubernetesClient {
+	return &kubernetesClient{}
+}
+
+func (c *kubernetesClient) connect() (kubernetes.Interface, error) {
+	c.once.Do(func() {
+		if c.clientset != nil {
+			return
+		}
+
+		restConfig, err := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(
+			clientcmd.NewDefaultClientConfigLoadingRules(), &clientcmd.ConfigOverrides{}).ClientConfig()
+		if err != nil {
+			c.err = fmt.Errorf("failed to connect to the cluster - %w", err)
+			return
+		}
+		restConfig.Timeout = kubernetesRequestTimeout
+
+		c.clientset, c.err = kubernetes.NewForConfig(restConfig)
+	})
+
+	return c.clientset, c.err
+}
+
+func (c *kubernetesClient) ListPods(namespace string, labelSelector string, node string) ([]Pod, error) {
+	clientset, err := c.connect()
+	if err != nil {
+		return nil, err
+	}
+
+	options := metav1.ListOptions{LabelSelector: labelSelector}
+	if node != "" {
+		options.FieldSelector = "spec.nodeName=" + node
+	}
+
+	list, err := clientset.CoreV1().Pods(namespace).List(context.Background(), options)
+	if err != nil {
+		return nil, err
+	}
+
+	pods := make([]Pod, 0, len(list.Items))
+	for _, item := range list.Items {
+		if node != "" && item.Spec.NodeName != node {
+			continue
+		}
+
+		pod := Pod{Namespace: item.Namespace, Name: item.Name, Node: item.Spec.NodeName}
+		if len(item.OwnerReferences) > 0 {
+			pod.OwnerKind = item.OwnerReferences[0].Kind
+		}
+		pods = append(pods, pod)
+	}
+
+	return pods, nil
+}
+
+func (c *kubernetesClient) DeletePod(namespace string, name string) error {
+	clientset, err := c.connect()
+	if err != nil {
+		return err
+	}
+
+	return clientset.CoreV1().Pods(namespace).Delete(context.Background(), name, metav1.DeleteOptions{})
+}
+
+func (c *kubernetesClient) EvictPod(namespace string, name string) error {
+	clientset, err := c.connect()
+	if err != nil {
+		return err
+	}
+
+	return clientset.CoreV1().Pods(namespace).EvictV1(context.Background(), &policyv1.Eviction{
+		ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name},
+	})
+}
+
+func (c *kubernetesClient) GetDeploymentReplicas(namespace string, name string) (int, error) {
+	clientset, err := c.connect()
+	if err != nil {
+		return 0, err
+	}
+
+	deployment, err := clientset.AppsV1().Deployments(namespace).Get(context.Background(), name, metav1.GetOptions{})
+	if err != nil {
+		return 0, err
+	}
+	// defaulted by the API server
+	if deployment.Spec.Replicas == nil {
+		return 1, nil
+	}
+
+	return int(*deployment.Spec.Replicas), nil
+}
+
+func (c *kubernetesClient) ScaleDeployment(namespace string, name string, replicas int) error {
+	clientset, err := c.connect()
+	if err != nil {
+		return err
+	}
+
+	patch := fmt.Sprintf(`{"spec":{"replicas":%d}}`, replicas)
+	_, err = clientset.AppsV1().Deployments(namespace).Patch(context.Background(), name, types.MergePatchType, []byte(patch), metav1.PatchOptions{})
+	return err
+}
+
+func (c *kubernetesClient) SetNodeUnschedulable(node string, unschedulable bool) error {
+	clientset, err := c.connect()
+	if err != nil {
+		return err
+	}
+
+	// as kubectl cordon and uncordon
+	patch := fmt.Sprintf(`{"spec":{"unschedulable":%t}}`, unschedulable)
+	_, err = clientset.CoreV1().Nodes().Patch(context.Background(), node, types.MergePatchType, []byte(patch), metav1.PatchOptions{})
+	return err
+}
+
+// dryRunClient reads the state of the cluster, but only logs the changes the failure actions would make.
+type dryRunClient struct {
+	ClusterClient
+}
+
+func newDryRunClient(client ClusterClient) *dryRunClient {
+	return &dryRunClient{ClusterClient: client}
+}
+
+func (c *dryRunClient) DeletePod(namespace string, name string) error {
+	logrus.Infof("[dry run] Deleting pod %s/%s", namespace, name)
+	return nil
+}
+
+func (c *dryRunClient) EvictPod(namespace string, name string) error {
+	logrus.Infof("[dry run] Evicting pod %s/%s", namespace, name)
+	return nil
+}
+
+func (c *dryRunClient) ScaleDeployment(namespace string, name string, replicas int) error {
+	logrus.Infof("[dry run] Scaling deployment %s/%s to %d replicas", namespace, name, replicas)
+	return nil
+}
+
+func (c *dryRunClient) SetNodeUnschedulable(node string, unschedulable bool) error {
+	logrus.Infof("[dry run] Setting node %s unschedulable: %t", node, unschedulable)
+	return nil
+}
@@ -0,0 +1,226 @@
+package failure
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/vhive-serverless/loader/pkg/config"
+	"github.com/vhive-serverless/loader/pkg/metric"
+)
+
+// scheduledFailure is an occurrence of a failure event of the schedule.
+type scheduledFailure struct {
+	event      int
+	occurrence int
+	// time since the beginning of the experiment
+	at     time.Duration
+	spec   config.FailureEvent
+	action FailureAction
+}
+
+// Scheduler injects the failures of the schedule during the experiment and records when each of them started and
+// ended.
+type Scheduler struct {
+	failures []scheduledFailure
+
+	stop    chan struct{}
+	running sync.WaitGroup
+
+	mutex   sync.Mutex
+	records []*metric.FailureRecord
+}
+
+func NewScheduler(platform string, cfg *config.FailureConfiguration, experimentDuration time.Duration, seed int64) *Scheduler {
+	var client ClusterClient = newKubernetesClient()
+	run := invokeLocally
+	if cfg != nil && cfg.DryRun {
+		client = newDryRunClient(client)
+		run = logCommand
+	}
+
+	return newScheduler(platform, cfg, experimentDuration, run, client, rand.New(rand.NewSource(seed)))
+}
+
+func newScheduler(platform string, cfg *config.FailureConfiguration, experimentDuration time.Duration,
+	run func([]string) error, client ClusterClient, rng *rand.Rand) *Scheduler {
+
+	s := &Scheduler{stop: make(chan struct{})}
+
+	events := scheduleEvents(cfg)
+	if len(events) == 0 {
+		return s
+	}
+
+	for i, event := range events {
+		if event.DurationSeconds > 0 && event.Recovery == "" {
+			events[i].Recovery = RecoveryStart
+		} else if event.DurationSeconds == 0 {
+			events[i].Recovery = RecoveryNone
+		}
+		if events[i].Recovery != RecoveryStart && events[i].Recovery != RecoveryNone {
+			logrus.Fatalf("Invalid recovery '%s' of failure event %d.", event.Recovery, i)
+		}
+	}
+
+	var err error
+	s.failures, err = expandSchedule(events, experimentDuration, func(event config.FailureEvent) (FailureAction, error) {
+		return newFailureAction(platform, event, run, client, rng)
+	})
+	if errors.Is(err, errUnsupportedPlatform) {
+		logrus.Errorf("No specified failure handler for given type of system.")
+		s.failures = nil
+	} else if err != nil {
+		logrus.Fatalf("Invalid failure schedule - %v", err)
+	}
+
+	return s
+}
+
+// scheduleEvents returns the events of the schedule along with the single failure of the configuration, if any.
+func scheduleEvents(cfg *config.FailureConfiguration) []config.FailureEvent {
+	if cfg == nil || !cfg.FailureEnabled {
+		return nil
+	}
+
+	events := slices.Clone(cfg.Events)
+	if cfg.FailAt != 0 && cfg.FailComponent != "" {
+		events = append(events, config.FailureEvent{
+			At:        cfg.FailAt,
+			Component: cfg.FailComponent,
+			Nodes:     strings.Fields(cfg.FailNode),
+		})
+	}
+
+	return events
+}
+
+// expandSchedule returns the occurrences of the events during the experiment, ordered by time. Each occurrence has
+// its own action, so that actions can keep the state needed to recover.
+func expandSchedule(events []config.FailureEvent, experimentDuration time.Duration,
+	newAction func(config.FailureEvent) (FailureAction, error)) ([]scheduledFailure, error) {
+
+	var failures []scheduledFailure
+
+	for i, event := range events {
+		period := time.Duration(event.RepeatEverySeconds) * time.Second
+		for occurrence := 0; ; occurrence++ {
+			at := time.Duration(event.At)*time.Second + time.Duration(occurrence)*period
+			if at >= experimentDuration {
+				if occurrence == 0 {
+					logrus.Warnf("Failure event %d at %ds is scheduled after the end of the experiment.", i, event.At)
+				}
+				break
+			}
+
+			action, err := newAction(event)
+			if err != nil {
+				return nil, fmt.Errorf("failure event %d - %w", i, err)
+			}
+			failures = append(failures, scheduledFailure{event: i, occurrence: occurrence, at: at, spec: event, action: action})
+
+			if period <= 0 || (event.RepeatCount > 0 && occurrence >= event.RepeatCount) {
+				break
+			}
+		}
+	}
+
+	slices.SortStableFunc(failures, func(a, b scheduledFailure) int {
+		return int(a.at - b.at)
+	})
+
+	return failures, nil
+}
+
+// Start schedules the failures relative to the current time, taken as the beginning of the experiment.
+func (s *Scheduler) Start() {
+	start := time.Now()
+
+	for _, failure := range s.failures {
+		s.running.Add(1)
+		go s.inject(start, failure)
+	}
+}
+
+// Stop cancels the failures that did not start yet, recovers those that are ongoing and returns the records of the
+// injected failures, ordered by start time.
+func (s *Scheduler) Stop() []*metric.FailureRecord {
+	close(s.stop)
+	s.running.Wait()
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	slices.SortStableFunc(s.records, func(a, b *metric.FailureRecord) int {
+		return int(a.StartTime - b.StartTime)
+	})
+
+	return s.records
+}
+
+func (s *Scheduler) inject(start time.Time, failure scheduledFailure) {
+	defer s.running.Done()
+
+	if !s.wait(time.Until(start.Add(failure.at))) {
+		return
+	}
+
+	record := &metric.FailureRecord{
+		Event:      failure.event,
+		Occurrence: failure.occurrence,
+		Component:  failure.spec.Component,
+		Target:     failureTarget(failure.spec),
+		Nodes:      strings.Join(failure.spec.Nodes, NodeSeparator),
+		Recovery:   failure.spec.Recovery,
+		StartTime:  time.Now().UnixMicro(),
+	}
+	logrus.Infof("Injecting failure of %s (event %d, occurrence %d)", record.Component, record.Event, record.Occurrence)
+
+	err := failure.action.Inject()
+	if err == nil && failure.spec.DurationSeconds > 0 {
+		if !s.wait(time.Duration(failure.spec.DurationSeconds) * time.Second) {
+			logrus.Warnf("Experiment ended during the failure of %s, ending it early", record.Component)
+		}
+
+		if failure.spec.Recovery == RecoveryStart {
+			err = failure.action.Recover()
+		}
+	}
+
+	record.EndTime = time.Now().UnixMicro()
+	if err != nil {
+		record.Error = err.Error()
+	}
+	logrus.Infof("Failure of %s ended after %.3fs", record.Component, float64(record.EndTime-record.StartTime)/1e6)
+
+	s.mutex.Lock()
+	s.records = append(s.records, record)
+	s.mutex.Unlock()
+}
+
+// failureTarget returns the function or the deployment failed by the event, if any.
+func failureTarget(event config.FailureEvent) string {
+	if event.Function != "" {
+		return event.Function
+	}
+
+	return event.Deployment
+}
+
+// wait returns true once the duration elapsed, or false if the scheduler was stopped before.
+func (s *Scheduler) wait(duration time.Duration) bool {
+	timer := time.NewTimer(duration)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return true
+	case <-s.stop:
+		return false
+	}
+}
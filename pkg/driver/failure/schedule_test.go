@@ -0,0 +1,129 @@
+package failure
+
+import (
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/vhive-serverless/loader/pkg/common"
+	"github.com/vhive-serverless/loader/pkg/config"
+)
+
+func TestExpandSchedule(t *testing.T) {
+	events := scheduleEvents(&config.FailureConfiguration{
+		FailureEnabled: true,
+		FailAt:         30,
+		FailComponent:  ControlPlaneFailure,
+		Events: []config.FailureEvent{
+			{At: 0, Component: DataPlaneFailure, RepeatEverySeconds: 20},
+			{At: 10, Component: WorkerNodeFailure, Nodes: []string{"node-1"}, DurationSeconds: 5, RepeatEverySeconds: 25, RepeatCount: 1},
+			{At: 120, Component: ControlPlaneFailure},
+		},
+	})
+
+	newAction := func(event config.FailureEvent) (FailureAction, error) {
+		return newFailureAction(common.PlatformKnative, event, nil, nil, nil)
+	}
+
+	failures, err := expandSchedule(events, time.Minute, newAction)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []struct {
+		event int
+		at    int
+	}{{0, 0}, {1, 10}, {0, 20}, {3, 30}, {1, 35}, {0, 40}}
+	if len(failures) != len(expected) {
+		t.Fatalf("Expected %d failures, got %d", len(expected), len(failures))
+	}
+	for i, failure := range failures {
+		if failure.event != expected[i].event || failure.at != time.Duration(expected[i].at)*time.Second {
+			t.Errorf("Unexpected failure %d: event %d at %v", i, failure.event, failure.at)
+		}
+	}
+
+	if _, err = expandSchedule([]config.FailureEvent{{Component: "etcd"}}, time.Minute, newAction); err == nil {
+		t.Error("Expected an error for an invalid component")
+	}
+}
+
+func TestScheduler(t *testing.T) {
+	var mutex sync.Mutex
+	var commands []string
+	run := func(command []string) error {
+		mutex.Lock()
+		defer mutex.Unlock()
+
+		commands = append(commands, strings.Join(command, " "))
+		return nil
+	}
+
+	s := newScheduler(common.PlatformDirigent, &config.FailureConfiguration{
+		FailureEnabled: true,
+		Events: []config.FailureEvent{
+			{At: 0, Component: DataPlaneFailure},
+			{At: 0, Component: WorkerNodeFailure, Nodes: []string{"node-1", "node-2"}, DurationSeconds: 1},
+			// ongoing when the experiment ends
+			{At: 1, Component: ControlPlaneFailure, DurationSeconds: 60},
+		},
+	}, time.Minute, run, nil, nil)
+
+	s.Start()
+	time.Sleep(1500 * time.Millisecond)
+	records := s.Stop()
+
+	if len(records) != 3 {
+		t.Fatalf("Expected 3 failure records, got %d", len(records))
+	}
+	for _, record := range records {
+		if record.StartTime == 0 || record.EndTime < record.StartTime || record.Error != "" {
+			t.Errorf("Unexpected failure record %+v", record)
+		}
+
+		switch record.Component {
+		case DataPlaneFailure:
+			if record.Recovery != RecoveryNone {
+				t.Errorf("Instantaneous failures should not be recovered, got %s", record.Recovery)
+			}
+		case WorkerNodeFailure:
+			if duration := record.EndTime - record.StartTime; duration < 1e6 || record.Nodes != "node-1 node-2" {
+				t.Errorf("Failure should last for its duration on both nodes, got %dus on %s", duration, record.Nodes)
+			}
+		case ControlPlaneFailure:
+			if duration := record.EndTime - record.StartTime; duration > 10e6 {
+				t.Errorf("Ongoing failure should be recovered once the experiment ends, got %dus", duration)
+			}
+		}
+	}
+
+	expected := []string{
+		"sudo systemctl restart data_plane",
+		"ssh -o StrictHostKeyChecking=no node-1 sudo systemctl stop worker_node",
+		"ssh -o StrictHostKeyChecking=no node-2 sudo systemctl start worker_node",
+		"sudo systemctl stop control_plane",
+		"sudo systemctl start control_plane",
+	}
+	for _, command := range expected {
+		found := false
+		for _, executed := range commands {
+			found = found || executed == command
+		}
+		if !found {
+			t.Errorf("Command '%s' was not executed, got %v", command, commands)
+		}
+	}
+}
+
+func TestSchedulerDisabled(t *testing.T) {
+	s := NewScheduler(common.PlatformKnative, &config.FailureConfiguration{
+		FailAt:        1,
+		FailComponent: ControlPlaneFailure,
+	}, time.Minute, 42)
+	s.Start()
+
+	if records := s.Stop(); len(records) != 0 {
+		t.Errorf("No failure should be injected if disabled, got %d", len(records))
+	}
+}
@@ -1,14 +1,15 @@
 package failure
 
 import (
-	"github.com/vhive-serverless/loader/pkg/common"
+	"errors"
+	"fmt"
 	"os/exec"
 	"strings"
 	"sync"
-	"time"
+
+	"github.com/vhive-serverless/loader/pkg/common"
 
 	"github.com/sirupsen/logrus"
-	"github.com/vhive-serverless/loader/pkg/config"
 )
 
 const (
@@ -17,88 +18,168 @@ const (
 	ControlPlaneFailure = "control_plane"
 	DataPlaneFailure    = "data_plane"
 	WorkerNodeFailure   = "worker_node"
-)
-
-func ScheduleFailure(platform string, config *config.FailureConfiguration) {
-	if config != nil && config.FailureEnabled && config.FailAt != 0 && config.FailComponent != "" {
-		time.Sleep(time.Duration(config.FailAt) * time.Second)
-
-		switch platform {
-		case common.PlatformKnative:
-			triggerKnativeFailure(config.FailNode, config.FailComponent)
-		case common.PlatformDirigent:
-			triggerDirigentFailure(config.FailNode, config.FailComponent)
-		default:
-			logrus.Errorf("No specified failure handler for given type of system.")
-		}
-	}
-}
 
-func invokeRemotely(command []string, nodes string) {
-	splitNodes := strings.Split(nodes, NodeSeparator)
-	wg := &sync.WaitGroup{}
+	// RecoveryStart starts the stopped component once the duration of the failure elapsed
+	RecoveryStart = "start"
+	// RecoveryNone leaves the recovery of the component to the platform
+	RecoveryNone = "none"
+)
 
-	for _, node := range splitNodes {
-		wg.Add(1)
+var errUnsupportedPlatform = errors.New("no specified failure handler for given type of system")
 
-		go func(command []string, node string) {
-			defer wg.Done()
+// failureCommands injects the failure of a component and, for failures with a duration, recovers it.
+type failureCommands struct {
+	inject  [][]string
+	recover [][]string
+	// whether the commands are executed on the nodes of the failure over SSH rather than locally
+	remote bool
+}
 
-			finalCommand := append([]string{"ssh", "-o", "StrictHostKeyChecking=no", node}, command...)
-			invokeLocally(finalCommand)
-		}(command, node)
+// commandsFor returns the commands failing the component of the platform. Components kept down are stopped instead
+// of being restarted, and started again on recovery.
+func commandsFor(platform string, component string, keepDown bool) (failureCommands, error) {
+	switch platform {
+	case common.PlatformKnative:
+		return knativeFailureCommands(component, keepDown)
+	case common.PlatformDirigent:
+		return dirigentFailureCommands(component, keepDown)
+	default:
+		return failureCommands{}, errUnsupportedPlatform
 	}
-
-	wg.Wait()
 }
 
-func triggerKnativeFailure(nodes string, component string) {
-	var command []string
+func knativeFailureCommands(component string, keepDown bool) (failureCommands, error) {
+	var deployments map[string][]string
 	switch component {
 	case ControlPlaneFailure:
-		command = []string{"bash", "./pkg/driver/failure/knative_delete_control_plane.sh"}
+		if !keepDown {
+			return failureCommands{inject: [][]string{{"bash", "./pkg/driver/failure/knative_delete_control_plane.sh"}}}, nil
+		}
+		deployments = map[string][]string{"knative-serving": {"autoscaler", "controller", "webhook"}}
 	case DataPlaneFailure:
-		command = []string{"bash", "./pkg/driver/failure/knative_delete_data_plane.sh"}
+		if !keepDown {
+			return failureCommands{inject: [][]string{{"bash", "./pkg/driver/failure/knative_delete_data_plane.sh"}}}, nil
+		}
+		deployments = map[string][]string{
+			"knative-serving": {"activator"},
+			"istio-system":    {"cluster-local-gateway", "istio-ingressgateway"},
+		}
 	case WorkerNodeFailure:
-		command = []string{"sudo", "systemctl", "restart", "kubelet"}
+		if !keepDown {
+			return failureCommands{inject: [][]string{{"sudo", "systemctl", "restart", "kubelet"}}, remote: true}, nil
+		}
+		return failureCommands{
+			inject:  [][]string{{"sudo", "systemctl", "stop", "kubelet"}},
+			recover: [][]string{{"sudo", "systemctl", "start", "kubelet"}},
+			remote:  true,
+		}, nil
 	default:
-		logrus.Fatal("Invalid component to fail.")
+		return failureCommands{}, fmt.Errorf("invalid component to fail: %s", component)
 	}
 
-	if component != "worker_node" {
-		invokeLocally(command)
-	} else {
-		invokeRemotely(command, nodes)
+	// components are kept down by scaling their deployments to zero, and recovered with a single replica
+	var commands failureCommands
+	for _, namespace := range []string{"knative-serving", "istio-system"} {
+		if names, ok := deployments[namespace]; ok {
+			commands.inject = append(commands.inject, scaleDeployments(namespace, names, 0))
+			commands.recover = append(commands.recover, scaleDeployments(namespace, names, 1))
+		}
+	}
+
+	return commands, nil
+}
+
+func scaleDeployments(namespace string, names []string, replicas int) []string {
+	command := []string{"kubectl", "-n", namespace, "scale", fmt.Sprintf("--replicas=%d", replicas)}
+	for _, name := range names {
+		command = append(command, "deployment/"+name)
 	}
+
+	return command
 }
 
-func triggerDirigentFailure(nodes string, component string) {
-	var command []string
+func dirigentFailureCommands(component string, keepDown bool) (failureCommands, error) {
+	var service string
 	switch component {
 	case ControlPlaneFailure:
-		command = []string{"sudo", "systemctl", "restart", "control_plane"}
+		service = "control_plane"
 	case DataPlaneFailure:
-		command = []string{"sudo", "systemctl", "restart", "data_plane"}
+		service = "data_plane"
 	case WorkerNodeFailure:
-		command = []string{"sudo", "systemctl", "restart", "worker_node"}
+		service = "worker_node"
 	default:
-		logrus.Fatal("Invalid component to fail.")
+		return failureCommands{}, fmt.Errorf("invalid component to fail: %s", component)
 	}
 
-	if nodes == "" {
-		invokeLocally(command)
-	} else {
-		invokeRemotely(command, nodes)
+	if !keepDown {
+		return failureCommands{inject: [][]string{{"sudo", "systemctl", "restart", service}}, remote: true}, nil
 	}
+
+	return failureCommands{
+		inject:  [][]string{{"sudo", "systemctl", "stop", service}},
+		recover: [][]string{{"sudo", "systemctl", "start", service}},
+		remote:  true,
+	}, nil
+}
+
+// execute runs the commands locally, or on each of the nodes if remote and nodes are given.
+func execute(run func(command []string) error, commands [][]string, remote bool, nodes []string) error {
+	for _, command := range commands {
+		var err error
+		if remote && len(nodes) > 0 {
+			err = invokeRemotely(run, command, nodes)
+		} else {
+			err = run(command)
+		}
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func invokeRemotely(run func(command []string) error, command []string, nodes []string) error {
+	wg := &sync.WaitGroup{}
+	errs := make([]error, len(nodes))
+
+	for i, node := range nodes {
+		wg.Add(1)
+
+		go func(i int, node string) {
+			defer wg.Done()
+
+			finalCommand := append([]string{"ssh", "-o", "StrictHostKeyChecking=no", node}, command...)
+			errs[i] = run(finalCommand)
+		}(i, node)
+	}
+
+	wg.Wait()
+
+	for i, err := range errs {
+		if err != nil {
+			return fmt.Errorf("node %s: %w", nodes[i], err)
+		}
+	}
+
+	return nil
 }
 
-func invokeLocally(command []string) {
+func invokeLocally(command []string) error {
 	cmd := exec.Command(command[0], command[1:]...)
 	output, err := cmd.CombinedOutput()
 	if err != nil {
-		logrus.Errorf("Error triggering %s failure - %v", command, err)
-		return
+		logrus.Errorf("Error executing %s - %v", command, err)
+		return fmt.Errorf("%s: %w", strings.Join(command, " "), err)
 	}
 
-	logrus.Infof("Failure triggered - %s", string(output))
+	logrus.Infof("Executed %s - %s", command, string(output))
+	return nil
+}
+
+// logCommand only logs the command, in dry runs.
+func logCommand(command []string) error {
+	logrus.Infof("[dry run] Executing %s", strings.Join(command, " "))
+	return nil
 }
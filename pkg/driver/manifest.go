@@ -0,0 +1,341 @@
+package driver
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"maps"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"runtime/debug"
+	"slices"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/vhive-serverless/loader/pkg/common"
+	"github.com/vhive-serverless/loader/pkg/config"
+)
+
+const redacted = "<redacted>"
+
+// RunManifest records the provenance of an experiment. It is written before the functions are deployed and again,
+// with the functions as deployed, the phases of the experiment and its output files, once they are cleaned up.
+type RunManifest struct {
+	Build BuildInfo `json:"build"`
+	Host  HostInfo  `json:"host"`
+	Seed  int64     `json:"seed"`
+
+	StartTime time.Time       `json:"startTime"`
+	EndTime   time.Time       `json:"endTime"`
+	Phases    []PhaseBoundary `json:"phases"`
+
+	Configuration EffectiveConfiguration `json:"configuration"`
+	// configuration and trace files read by the loader
+	InputFiles  []ManifestFile     `json:"inputFiles"`
+	Functions   []ManifestFunction `json:"functions"`
+	OutputFiles []ManifestFile     `json:"outputFiles"`
+}
+
+type BuildInfo struct {
+	Module    string `json:"module"`
+	Version   string `json:"version"`
+	GoVersion string `json:"goVersion"`
+	Commit    string `json:"commit"`
+	// commit time, RFC 3339
+	CommitTime string `json:"commitTime,omitempty"`
+	// uncommitted changes in the working tree
+	Modified bool `json:"modified"`
+}
+
+type HostInfo struct {
+	Hostname string `json:"hostname"`
+	OS       string `json:"os"`
+	Arch     string `json:"arch"`
+	CPUs     int    `json:"cpus"`
+}
+
+type PhaseBoundary struct {
+	Phase string    `json:"phase"`
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
+}
+
+// EffectiveConfiguration is the configuration of the experiment once all the configuration files are read and merged.
+// Passwords and credentials in headers are redacted.
+type EffectiveConfiguration struct {
+	Loader      *config.LoaderConfiguration  `json:"loader"`
+	Failure     *config.FailureConfiguration `json:"failure"`
+	Dirigent    *config.DirigentConfig       `json:"dirigent,omitempty"`
+	GenericHTTP *config.GenericHTTPConfig    `json:"genericHTTP,omitempty"`
+	OpenFaaS    *config.OpenFaaSConfig       `json:"openFaaS,omitempty"`
+	Fission     *config.FissionConfig        `json:"fission,omitempty"`
+	DynamicGRPC *config.DynamicGRPCConfig    `json:"dynamicGRPC,omitempty"`
+	Autoscaling *config.AutoscalingConfig    `json:"autoscaling,omitempty"`
+	Simulated   *config.SimulatedConfig      `json:"simulated,omitempty"`
+	Local       *config.LocalConfig          `json:"local,omitempty"`
+
+	TraceGranularity string `json:"traceGranularity"`
+	// in minutes, including the warmup
+	TraceDuration int `json:"traceDuration"`
+}
+
+type ManifestFile struct {
+	Path   string `json:"path"`
+	Size   int64  `json:"size"`
+	SHA256 string `json:"sha256,omitempty"`
+}
+
+// ManifestFunction is a function of the experiment with the resources and scaling settings it is deployed with.
+type ManifestFunction struct {
+	Name        string `json:"name"`
+	Endpoint    string `json:"endpoint,omitempty"`
+	Invocations int    `json:"invocations"`
+
+	CPURequestsMilli    int    `json:"cpuRequestsMilli"`
+	CPULimitsMilli      int    `json:"cpuLimitsMilli"`
+	MemoryRequestsMiB   int    `json:"memoryRequestsMiB"`
+	ColdStartBusyLoopMs int    `json:"coldStartBusyLoopMs"`
+	YAMLPath            string `json:"yamlPath,omitempty"`
+
+	InitialScale int                         `json:"initialScale"`
+	Autoscaling  *common.AutoscalingSettings `json:"autoscaling,omitempty"`
+}
+
+// newRunManifest describes the build, the host, the configuration, the input files and the functions of the
+// experiment, which starts at the given time.
+func (d *Driver) newRunManifest(start time.Time) *RunManifest {
+	cfg := d.Configuration
+
+	manifest := &RunManifest{
+		Build:         readBuildInfo(),
+		Host:          readHostInfo(),
+		Seed:          cfg.LoaderConfiguration.Seed,
+		StartTime:     start,
+		Configuration: effectiveConfiguration(cfg),
+	}
+
+	loaderCfg := cfg.LoaderConfiguration
+	inputs := []string{cfg.ConfigPath, cfg.FailureConfigPath, loaderCfg.DirigentConfigPath, loaderCfg.GenericHTTPConfigPath,
+		loaderCfg.OpenFaaSConfigPath, loaderCfg.FissionConfigPath, loaderCfg.SimulatedConfigPath, loaderCfg.LocalConfigPath,
+		loaderCfg.DynamicGRPCConfigPath, loaderCfg.AutoscalingConfigPath}
+	if cfg.DirigentConfiguration != nil {
+		inputs = append(inputs, cfg.DirigentConfiguration.WorkflowConfigPath)
+	}
+	inputs = append(inputs, traceFiles(loaderCfg.TracePath)...)
+
+	for _, path := range inputs {
+		if path == "" {
+			continue
+		}
+
+		if file, err := hashFile(path); err == nil {
+			manifest.InputFiles = append(manifest.InputFiles, file)
+		} else if !os.IsNotExist(err) {
+			log.Warnf("Failed to hash %s for the run manifest - %v", path, err)
+		}
+	}
+
+	manifest.setFunctions(cfg.Functions)
+
+	return manifest
+}
+
+// setFunctions replaces the functions of the manifest, e.g., once deployed, with their endpoints and without the
+// functions dropped for not becoming ready.
+func (m *RunManifest) setFunctions(functions []*common.Function) {
+	m.Functions = nil
+
+	for _, function := range functions {
+		manifestFunction := ManifestFunction{
+			Name:                function.Name,
+			Endpoint:            function.Endpoint,
+			CPURequestsMilli:    function.CPURequestsMilli,
+			CPULimitsMilli:      function.CPULimitsMilli,
+			MemoryRequestsMiB:   function.MemoryRequestsMiB,
+			ColdStartBusyLoopMs: function.ColdStartBusyLoopMs,
+			YAMLPath:            function.YAMLPath,
+			InitialScale:        function.InitialScale,
+			Autoscaling:         function.Autoscaling,
+		}
+		if function.Specification != nil {
+			manifestFunction.Invocations = len(function.Specification.IAT)
+		}
+
+		m.Functions = append(m.Functions, manifestFunction)
+	}
+}
+
+func (m *RunManifest) addPhase(phase string, start time.Time, end time.Time) {
+	m.Phases = append(m.Phases, PhaseBoundary{Phase: phase, Start: start, End: end})
+}
+
+// finish sets the end of the experiment and lists the files written with the output path prefix since its start.
+func (m *RunManifest) finish(outputPathPrefix string, manifestPath string) {
+	m.EndTime = time.Now()
+
+	paths, err := filepath.Glob(outputPathPrefix + "_*")
+	if err != nil {
+		log.Warnf("Failed to list the output files for the run manifest - %v", err)
+		return
+	}
+
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		// modification times may be truncated to the second
+		if err != nil || !info.Mode().IsRegular() || path == manifestPath || info.ModTime().Before(m.StartTime.Truncate(time.Second)) {
+			continue
+		}
+
+		m.OutputFiles = append(m.OutputFiles, ManifestFile{Path: path, Size: info.Size()})
+	}
+}
+
+func (m *RunManifest) write(path string) {
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err == nil {
+		err = os.WriteFile(path, data, 0644)
+	}
+	if err != nil {
+		log.Warnf("Failed to write the run manifest - %v", err)
+	}
+}
+
+func readBuildInfo() BuildInfo {
+	info := BuildInfo{GoVersion: runtime.Version()}
+
+	if build, ok := debug.ReadBuildInfo(); ok {
+		info.Module, info.Version = build.Main.Path, build.Main.Version
+		for _, setting := range build.Settings {
+			switch setting.Key {
+			case "vcs.revision":
+				info.Commit = setting.Value
+			case "vcs.time":
+				info.CommitTime = setting.Value
+			case "vcs.modified":
+				info.Modified = setting.Value == "true"
+			}
+		}
+	}
+
+	// binaries built by go run are not stamped with the version control information
+	if info.Commit == "" {
+		if commit, err := exec.Command("git", "rev-parse", "HEAD").Output(); err == nil {
+			info.Commit = strings.TrimSpace(string(commit))
+		}
+		if status, err := exec.Command("git", "status", "--porcelain", "--untracked-files=no").Output(); err == nil {
+			info.Modified = len(strings.TrimSpace(string(status))) > 0
+		}
+	}
+
+	return info
+}
+
+func readHostInfo() HostInfo {
+	hostname, _ := os.Hostname()
+
+	return HostInfo{
+		Hostname: hostname,
+		OS:       runtime.GOOS,
+		Arch:     runtime.GOARCH,
+		CPUs:     runtime.NumCPU(),
+	}
+}
+
+func effectiveConfiguration(cfg *config.Configuration) EffectiveConfiguration {
+	effective := EffectiveConfiguration{
+		Loader:        cfg.LoaderConfiguration,
+		Failure:       cfg.FailureConfiguration,
+		Dirigent:      cfg.DirigentConfiguration,
+		Fission:       cfg.FissionConfiguration,
+		DynamicGRPC:   cfg.DynamicGRPCConfiguration,
+		Autoscaling:   cfg.AutoscalingConfiguration,
+		Simulated:     cfg.SimulatedConfiguration,
+		Local:         cfg.LocalConfiguration,
+		TraceDuration: cfg.TraceDuration,
+	}
+
+	switch cfg.TraceGranularity {
+	case common.MinuteGranularity:
+		effective.TraceGranularity = "minute"
+	case common.SecondGranularity:
+		effective.TraceGranularity = "second"
+	}
+
+	if cfg.OpenFaaSConfiguration != nil {
+		openFaaS := *cfg.OpenFaaSConfiguration
+		if openFaaS.Password != "" {
+			openFaaS.Password = redacted
+		}
+		effective.OpenFaaS = &openFaaS
+	}
+
+	if cfg.GenericHTTPConfiguration != nil {
+		genericHTTP := *cfg.GenericHTTPConfiguration
+		genericHTTP.HeaderTemplates = maps.Clone(genericHTTP.HeaderTemplates)
+		for header := range genericHTTP.HeaderTemplates {
+			if isCredentialHeader(header) {
+				genericHTTP.HeaderTemplates[header] = redacted
+			}
+		}
+		effective.GenericHTTP = &genericHTTP
+	}
+
+	return effective
+}
+
+func isCredentialHeader(header string) bool {
+	header = strings.ToLower(header)
+	for _, credential := range []string{"authorization", "cookie", "token", "key", "secret", "password"} {
+		if strings.Contains(header, credential) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// traceFiles returns the trace file or the files of the trace directory, sorted by name.
+func traceFiles(tracePath string) []string {
+	if tracePath == "" {
+		return nil
+	}
+
+	entries, err := os.ReadDir(tracePath)
+	if err != nil {
+		return []string{tracePath}
+	}
+
+	var files []string
+	for _, entry := range entries {
+		if entry.Type().IsRegular() {
+			files = append(files, filepath.Join(tracePath, entry.Name()))
+		}
+	}
+	slices.Sort(files)
+
+	return files
+}
+
+func hashFile(path string) (ManifestFile, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return ManifestFile{}, err
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return ManifestFile{}, err
+	}
+
+	digest := sha256.New()
+	if _, err = io.Copy(digest, file); err != nil {
+		return ManifestFile{}, err
+	}
+
+	return ManifestFile{Path: path, Size: info.Size(), SHA256: hex.EncodeToString(digest.Sum(nil))}, nil
+}
@@ -0,0 +1,128 @@
+package driver
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/vhive-serverless/loader/pkg/common"
+	"github.com/vhive-serverless/loader/pkg/config"
+)
+
+func TestRunManifest(t *testing.T) {
+	directory := t.TempDir()
+	traceDirectory := filepath.Join(directory, "trace")
+	if err := os.Mkdir(traceDirectory, 0755); err != nil {
+		t.Fatal(err)
+	}
+	_ = os.WriteFile(filepath.Join(traceDirectory, "invocations.csv"), []byte("invocations"), 0644)
+	_ = os.WriteFile(filepath.Join(traceDirectory, "durations.csv"), []byte("durations"), 0644)
+	_ = os.WriteFile(filepath.Join(directory, "config.json"), []byte("{}"), 0644)
+
+	d := &Driver{
+		Configuration: &config.Configuration{
+			LoaderConfiguration: &config.LoaderConfiguration{
+				Seed:             42,
+				TracePath:        traceDirectory,
+				OutputPathPrefix: filepath.Join(directory, "experiment"),
+			},
+			OpenFaaSConfiguration: &config.OpenFaaSConfig{Username: "admin", Password: "secret"},
+			GenericHTTPConfiguration: &config.GenericHTTPConfig{
+				HeaderTemplates: map[string]string{"Authorization": "Bearer token", "Content-Type": "application/json"},
+			},
+			TraceGranularity: common.SecondGranularity,
+			TraceDuration:    1,
+			ConfigPath:       filepath.Join(directory, "config.json"),
+			// missing files are not listed
+			FailureConfigPath: filepath.Join(directory, "failure.json"),
+			Functions: []*common.Function{{
+				Name:              "f",
+				CPURequestsMilli:  100,
+				MemoryRequestsMiB: 256,
+				Autoscaling:       &common.AutoscalingSettings{MaxScale: 3},
+				Specification:     &common.FunctionSpecification{IAT: common.IATArray{1, 2}},
+			}},
+		},
+	}
+
+	start := time.Now()
+	manifest := d.newRunManifest(start)
+
+	if manifest.Seed != 42 || manifest.Build.GoVersion == "" || manifest.Host.CPUs == 0 {
+		t.Errorf("Unexpected manifest %+v", manifest)
+	}
+
+	configuration := manifest.Configuration
+	if configuration.OpenFaaS.Password != redacted || d.Configuration.OpenFaaSConfiguration.Password != "secret" {
+		t.Error("The password should be redacted from the manifest only")
+	}
+	if headers := configuration.GenericHTTP.HeaderTemplates; headers["Authorization"] != redacted ||
+		headers["Content-Type"] != "application/json" || d.Configuration.GenericHTTPConfiguration.HeaderTemplates["Authorization"] == redacted {
+		t.Errorf("Unexpected headers %v", headers)
+	}
+	if configuration.TraceGranularity != "second" {
+		t.Errorf("Unexpected trace granularity %s", configuration.TraceGranularity)
+	}
+
+	expectedInputs := []struct {
+		path    string
+		content string
+	}{
+		{filepath.Join(directory, "config.json"), "{}"},
+		{filepath.Join(traceDirectory, "durations.csv"), "durations"},
+		{filepath.Join(traceDirectory, "invocations.csv"), "invocations"},
+	}
+	if len(manifest.InputFiles) != len(expectedInputs) {
+		t.Fatalf("Unexpected input files %+v", manifest.InputFiles)
+	}
+	for i, file := range manifest.InputFiles {
+		digest := sha256.Sum256([]byte(expectedInputs[i].content))
+		if file.Path != expectedInputs[i].path || file.SHA256 != hex.EncodeToString(digest[:]) {
+			t.Errorf("Unexpected input file %+v", file)
+		}
+	}
+
+	if len(manifest.Functions) != 1 || manifest.Functions[0].Invocations != 2 || manifest.Functions[0].MemoryRequestsMiB != 256 ||
+		manifest.Functions[0].Autoscaling.MaxScale != 3 {
+		t.Errorf("Unexpected functions %+v", manifest.Functions)
+	}
+
+	// the deployment sets the endpoints, and functions not becoming ready may be dropped
+	d.Configuration.Functions[0].Endpoint = "http://f.default.example.com"
+	d.Configuration.Functions = append(d.Configuration.Functions, &common.Function{Name: "g"})
+	manifest.setFunctions(d.Configuration.Functions)
+	if len(manifest.Functions) != 2 || manifest.Functions[0].Endpoint != "http://f.default.example.com" || manifest.Functions[1].Name != "g" {
+		t.Errorf("Unexpected deployed functions %+v", manifest.Functions)
+	}
+	manifest.setFunctions(d.Configuration.Functions[1:])
+	if len(manifest.Functions) != 1 || manifest.Functions[0].Name != "g" {
+		t.Errorf("Dropped functions should be removed from the manifest, got %+v", manifest.Functions)
+	}
+
+	manifestPath := filepath.Join(directory, "experiment_manifest_1.json")
+	_ = os.WriteFile(filepath.Join(directory, "experiment_duration_1.csv"), []byte("duration"), 0644)
+	manifest.addPhase("execution", start, time.Now())
+	manifest.write(manifestPath)
+	manifest.finish(d.Configuration.LoaderConfiguration.OutputPathPrefix, manifestPath)
+	manifest.write(manifestPath)
+
+	data, err := os.ReadFile(manifestPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var read RunManifest
+	if err = json.Unmarshal(data, &read); err != nil {
+		t.Fatal(err)
+	}
+	if len(read.OutputFiles) != 1 || read.OutputFiles[0].Path != filepath.Join(directory, "experiment_duration_1.csv") ||
+		read.OutputFiles[0].Size != 8 {
+		t.Errorf("Unexpected output files %+v", read.OutputFiles)
+	}
+	if len(read.Phases) != 1 || read.EndTime.Before(read.StartTime) {
+		t.Errorf("Unexpected phases %+v from %v to %v", read.Phases, read.StartTime, read.EndTime)
+	}
+}
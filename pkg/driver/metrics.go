@@ -1,14 +1,17 @@
 package driver
 
 import (
-	"encoding/json"
+	log "github.com/sirupsen/logrus"
 	"github.com/vhive-serverless/loader/pkg/common"
 	mc "github.com/vhive-serverless/loader/pkg/metric"
-	"os"
+	"strings"
 	"sync"
 	"time"
 )
 
+// failureImpactWindow is the granularity at which the invocations are compared to their baseline after a failure
+const failureImpactWindow = 10 * time.Second
+
 func (d *Driver) CreateMetricsScrapper(interval time.Duration,
 	signalReady *sync.WaitGroup, finishCh chan int, allRecordsWritten *sync.WaitGroup) func() {
 	timer := time.NewTicker(interval)
@@ -17,17 +20,19 @@ func (d *Driver) CreateMetricsScrapper(interval time.Duration,
 		signalReady.Done()
 		knStatRecords := make(chan any, 100)
 		scaleRecords := make(chan any, 100)
+		clusterUsageRecords := make(chan any, 100)
 		writerDone := sync.WaitGroup{}
 
-		clusterUsageFile, err := os.Create(d.outputFilename("cluster_usage"))
-		common.Check(err)
-		defer clusterUsageFile.Close()
+		format := d.Configuration.LoaderConfiguration.OutputFormat
+
+		writerDone.Add(1)
+		go mc.RunRecordWriter(clusterUsageRecords, d.createClusterUsageSink(), &writerDone)
 
 		writerDone.Add(1)
-		go mc.RunCSVWriter(knStatRecords, d.outputFilename("kn_stats"), &writerDone)
+		go mc.RunRecordWriter(knStatRecords, d.createRecordSink("kn_stats", format), &writerDone)
 
 		writerDone.Add(1)
-		go mc.RunCSVWriter(scaleRecords, d.outputFilename("deployment_scale"), &writerDone)
+		go mc.RunRecordWriter(scaleRecords, d.createRecordSink("deployment_scale", format), &writerDone)
 
 		for {
 			select {
@@ -35,14 +40,7 @@ func (d *Driver) CreateMetricsScrapper(interval time.Duration,
 				recCluster := mc.ScrapeClusterUsage()
 				recCluster.Timestamp = time.Now().UnixMicro()
 
-				byteArr, err := json.Marshal(recCluster)
-				common.Check(err)
-
-				_, err = clusterUsageFile.Write(byteArr)
-				common.Check(err)
-
-				_, err = clusterUsageFile.WriteString("\n")
-				common.Check(err)
+				clusterUsageRecords <- recCluster
 
 				recScale := mc.ScrapeDeploymentScales()
 				timestamp := time.Now().UnixMicro()
@@ -55,6 +53,7 @@ func (d *Driver) CreateMetricsScrapper(interval time.Duration,
 				recKnative.Timestamp = time.Now().UnixMicro()
 				knStatRecords <- recKnative
 			case <-finishCh:
+				close(clusterUsageRecords)
 				close(knStatRecords)
 				close(scaleRecords)
 
@@ -66,3 +65,123 @@ func (d *Driver) CreateMetricsScrapper(interval time.Duration,
 		}
 	}
 }
+
+// createClusterUsageSink creates the cluster usage output. Its records have per-node lists, which are written as JSON
+// lines unless the format supports them. The file keeps the name of the configured format, so the CSV default is still
+// written to <prefix>_cluster_usage_<duration>.csv, as expected by the experiment driver.
+func (d *Driver) createClusterUsageSink() mc.RecordSink {
+	format := d.Configuration.LoaderConfiguration.OutputFormat
+	if format == "" || format == common.OutputFormatCSV {
+		format = common.OutputFormatJSONLines
+	}
+
+	return d.createRecordSink("cluster_usage", format)
+}
+
+// writeColdStartSummary writes the per-function cold start summary once all the records have been written.
+func (d *Driver) writeColdStartSummary() {
+	summaries := d.coldStarts.Summaries()
+	if len(summaries) == 0 {
+		return
+	}
+
+	var reported, coldStarts int
+	records := make(chan any, len(summaries))
+	for _, summary := range summaries {
+		records <- summary
+
+		reported += summary.Reported
+		coldStarts += summary.ColdStarts
+		log.Debugf("Cold starts of %s: %d/%d (%.2f%%), %d instances, %.0f[us] average init time", summary.Function,
+			summary.ColdStarts, summary.Reported, summary.ColdRatio*100, summary.Instances, summary.AvgInitTime)
+	}
+	close(records)
+
+	writerDone := sync.WaitGroup{}
+	writerDone.Add(1)
+	mc.RunCSVWriter(records, d.outputFilename("cold_starts"), &writerDone)
+
+	if reported > 0 {
+		log.Infof("Cold starts: \t\t\t%d/%d (%.2f%%)", coldStarts, reported, float64(coldStarts)*100.0/float64(reported))
+	}
+}
+
+// writeFailureRecords writes the start and end of the failures injected during the experiment, if any.
+func (d *Driver) writeFailureRecords(failures []*mc.FailureRecord) {
+	if len(failures) == 0 {
+		return
+	}
+
+	records := make(chan any, len(failures))
+	for _, failure := range failures {
+		records <- failure
+	}
+	close(records)
+
+	writerDone := sync.WaitGroup{}
+	writerDone.Add(1)
+	mc.RunCSVWriter(records, d.outputFilename("failures"), &writerDone)
+}
+
+// writeFailureImpact analyzes the impact of the failures injected during the experiment on the invocations written
+// to the duration file, and writes it as JSON and CSV.
+func (d *Driver) writeFailureImpact(failures []*mc.FailureRecord) {
+	if len(failures) == 0 {
+		return
+	}
+
+	cfg := d.Configuration.LoaderConfiguration
+	records, err := mc.ReadExecutionRecords(d.recordFilename("duration"), cfg.OutputFormat, cfg.OutputCompression)
+	if err != nil {
+		log.Warnf("Failed to read the invocation records for the failure impact analysis - %v", err)
+		return
+	}
+
+	report, err := mc.AnalyzeFailureImpact(records, failures, failureImpactWindow)
+	if err != nil {
+		log.Warnf("Failed to analyze the impact of the failures - %v", err)
+		return
+	}
+
+	csvPath := d.outputFilename("failure_impact")
+	if err = mc.WriteFailureImpactReport(report, strings.TrimSuffix(csvPath, ".csv")+".json", csvPath); err != nil {
+		log.Warnf("Failed to write the failure impact report - %v", err)
+		return
+	}
+
+	for _, impact := range report.Failures {
+		log.Infof("Failure %d.%d (%s): availability dip %.2f%%, %d affected invocations, first error after %d[us], recovery after %d[us]",
+			impact.Event, impact.Occurrence, impact.Component, impact.AvailabilityDip*100, impact.AffectedInvocations,
+			impact.TimeToFirstError, impact.TimeToRecovery)
+	}
+}
+
+// writeExperimentReport writes the report of the invocations of the execution phase as JSON and Markdown.
+func (d *Driver) writeExperimentReport() {
+	requested := make(map[string]int)
+	for _, function := range d.Configuration.Functions {
+		if function.Specification == nil {
+			continue
+		}
+
+		for minute, count := range function.Specification.PerMinuteCount {
+			if minute >= d.Configuration.LoaderConfiguration.WarmupDuration {
+				requested[function.Name] += count
+			}
+		}
+	}
+
+	report := d.reporter.Report(requested)
+
+	prefix := strings.TrimSuffix(d.outputFilename("report"), ".csv")
+	if err := mc.WriteExperimentReport(report, prefix+".json", prefix+".md"); err != nil {
+		log.Warnf("Failed to write the experiment report - %v", err)
+		return
+	}
+
+	overall := report.Overall
+	log.Infof("Issued invocations: \t\t%d/%d (%.2f%%)", overall.Issued, overall.Requested, overall.IssuedAccuracy*100)
+	log.Infof("Response time [ms]: \t\tp50 %.2f, p99 %.2f, p99.9 %.2f", float64(overall.ResponseTime.P50)/1e3,
+		float64(overall.ResponseTime.P99)/1e3, float64(overall.ResponseTime.P999)/1e3)
+	log.Infof("Slowdown: \t\t\tp50 %.2f, p99 %.2f", overall.Slowdown.P50, overall.Slowdown.P99)
+}
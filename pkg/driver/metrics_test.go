@@ -0,0 +1,42 @@
+package driver
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/vhive-serverless/loader/pkg/config"
+	mc "github.com/vhive-serverless/loader/pkg/metric"
+)
+
+func TestClusterUsageFilename(t *testing.T) {
+	prefix := filepath.Join(t.TempDir(), "experiment")
+	d := &Driver{Configuration: &config.Configuration{
+		LoaderConfiguration: &config.LoaderConfiguration{OutputPathPrefix: prefix},
+		TraceDuration:       5,
+	}}
+
+	sink := d.createClusterUsageSink()
+	if err := sink.Write(&mc.ClusterUsage{Timestamp: 1, Cpu: []string{"10%", "20%"}}); err != nil {
+		t.Fatal(err)
+	}
+	if err := sink.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	// the file name of the experiment driver, with the per-node lists written as JSON lines
+	content, err := os.ReadFile(prefix + "_cluster_usage_5.csv")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var record mc.ClusterUsage
+	if err = json.Unmarshal([]byte(strings.TrimSpace(string(content))), &record); err != nil {
+		t.Fatal(err)
+	}
+	if record.Timestamp != 1 || len(record.Cpu) != 2 {
+		t.Errorf("Unexpected cluster usage record %+v", record)
+	}
+}
@@ -0,0 +1,188 @@
+package driver
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/vhive-serverless/loader/pkg/common"
+	"github.com/vhive-serverless/loader/pkg/driver/clients"
+	"github.com/vhive-serverless/loader/pkg/metric"
+)
+
+const (
+	readinessProbeRuntimeMs = 10
+	readinessProbeMemoryMiB = 128
+	readinessInitialBackoff = 500 * time.Millisecond
+	readinessMaxBackoff     = 10 * time.Second
+
+	// long enough for concurrent pre-warm invocations to be served by distinct instances
+	preWarmRuntimeMs = 2000
+
+	// limits the number of functions probed at the same time
+	maxConcurrentReadinessChecks = 50
+)
+
+type readinessResult struct {
+	function *common.Function
+	ready    bool
+	attempts int
+	elapsed  time.Duration
+	warmed   int
+}
+
+// awaitReadiness probes each deployed function with health invocations until it succeeds or the readiness timeout
+// expires, and optionally pre-warms it to its initial scale, so that the experiment does not start with functions
+// still being deployed. Functions that do not become ready are dropped or abort the experiment, depending on the
+// readiness policy, in which case an error is returned.
+func (d *Driver) awaitReadiness() error {
+	lcfg := d.Configuration.LoaderConfiguration
+	if lcfg.ReadinessTimeoutSeconds <= 0 {
+		return nil
+	}
+
+	timeout := time.Duration(lcfg.ReadinessTimeoutSeconds) * time.Second
+	log.Infof("Waiting for %d functions to become ready (timeout %v)...", len(d.Configuration.Functions), timeout)
+
+	results := make([]readinessResult, len(d.Configuration.Functions))
+	semaphore := make(chan struct{}, maxConcurrentReadinessChecks)
+	wg := sync.WaitGroup{}
+
+	deadline := time.Now().Add(timeout)
+	for i, function := range d.Configuration.Functions {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			semaphore <- struct{}{}
+			defer func() { <-semaphore }()
+
+			results[i] = d.probeFunction(function, deadline)
+			if results[i].ready && lcfg.PreWarm {
+				results[i].warmed = d.preWarmFunction(function)
+			}
+		}()
+	}
+	wg.Wait()
+
+	var notReady []string
+	for _, result := range results {
+		if !result.ready {
+			log.Warnf("Function %s not ready after %d probes", result.function.Name, result.attempts)
+			notReady = append(notReady, result.function.Name)
+			continue
+		}
+
+		log.Debugf("Function %s ready after %d probes in %v", result.function.Name, result.attempts, result.elapsed)
+		if lcfg.PreWarm && result.warmed < result.function.InitialScale {
+			log.Warnf("Function %s pre-warmed with %d/%d invocations", result.function.Name, result.warmed, result.function.InitialScale)
+		}
+	}
+
+	if len(notReady) == 0 {
+		log.Infof("All functions are ready")
+		return nil
+	}
+
+	if lcfg.ReadinessPolicy != common.ReadinessPolicyDrop {
+		return fmt.Errorf("%d/%d functions not ready: %s", len(notReady), len(results), strings.Join(notReady, ", "))
+	}
+
+	// the deployer keeps the deployed functions to clean them up, so they are dropped from a copy
+	d.Configuration.Functions = slices.DeleteFunc(slices.Clone(d.Configuration.Functions), func(function *common.Function) bool {
+		return slices.Contains(notReady, function.Name)
+	})
+	if len(d.Configuration.Functions) == 0 {
+		return errors.New("no function became ready")
+	}
+
+	log.Warnf("Dropped %d functions that did not become ready: %s", len(notReady), strings.Join(notReady, ", "))
+
+	return nil
+}
+
+func (d *Driver) probeFunction(function *common.Function, deadline time.Time) readinessResult {
+	result := readinessResult{function: function}
+	start := time.Now()
+
+	backoff := readinessInitialBackoff
+	for time.Now().Before(deadline) {
+		result.attempts++
+
+		success, record := d.Invoker.Invoke(function, &common.RuntimeSpecification{
+			Runtime: readinessProbeRuntimeMs,
+			Memory:  readinessProbeMemoryMiB,
+		}, fmt.Sprintf("readiness-%s-%d", function.Name, result.attempts))
+		if success && record.AsyncResponseID != "" {
+			success = d.collectProbe(record, deadline)
+		}
+		if success {
+			result.ready = true
+			result.elapsed = time.Since(start)
+
+			return result
+		}
+
+		time.Sleep(min(backoff, time.Until(deadline)))
+		backoff = min(2*backoff, readinessMaxBackoff)
+	}
+
+	return result
+}
+
+// collectProbe waits for the result of an asynchronous probe, as its submission does not show that the function
+// serves invocations. Results delivered to the callback receiver, which only runs during the experiment, cannot be
+// awaited, so the submission is then considered enough.
+func (d *Driver) collectProbe(record *metric.ExecutionRecord, deadline time.Time) bool {
+	source, ok := d.Invoker.(clients.AsyncCollector)
+	if !ok || d.Configuration.LoaderConfiguration.AsyncCallbackAddress != "" {
+		return true
+	}
+
+	backoff := defaultAsyncInitialBackoff
+	for time.Now().Before(deadline) {
+		time.Sleep(min(backoff, time.Until(deadline)))
+
+		done, err := source.Collect(record)
+		if done {
+			return !record.FunctionTimeout && !record.ConnectionTimeout
+		}
+		if err != nil {
+			log.Debugf("Failed to collect the result of %s - %v", record.InvocationID, err)
+		}
+
+		backoff = min(2*backoff, readinessMaxBackoff)
+	}
+
+	return false
+}
+
+// preWarmFunction issues as many concurrent invocations as the initial scale of the function and returns the number
+// of successful ones.
+func (d *Driver) preWarmFunction(function *common.Function) int {
+	var warmed atomic.Int64
+	wg := sync.WaitGroup{}
+
+	for i := 0; i < function.InitialScale; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			success, _ := d.Invoker.Invoke(function, &common.RuntimeSpecification{
+				Runtime: preWarmRuntimeMs,
+				Memory:  readinessProbeMemoryMiB,
+			}, fmt.Sprintf("prewarm-%s-%d", function.Name, i))
+			if success {
+				warmed.Add(1)
+			}
+		}()
+	}
+	wg.Wait()
+
+	return int(warmed.Load())
+}
@@ -0,0 +1,171 @@
+package driver
+
+import (
+	"slices"
+	"sync"
+	"testing"
+
+	"github.com/vhive-serverless/loader/pkg/common"
+	"github.com/vhive-serverless/loader/pkg/config"
+	"github.com/vhive-serverless/loader/pkg/metric"
+)
+
+// fakeReadinessInvoker fails the first invocations of each function, or all of them if negative.
+type fakeReadinessInvoker struct {
+	mutex       sync.Mutex
+	failures    map[string]int
+	invocations map[string]int
+}
+
+func (i *fakeReadinessInvoker) Invoke(function *common.Function, _ *common.RuntimeSpecification, _ string) (bool, *metric.ExecutionRecord) {
+	i.mutex.Lock()
+	defer i.mutex.Unlock()
+
+	i.invocations[function.Name]++
+	failures := i.failures[function.Name]
+
+	return failures >= 0 && i.invocations[function.Name] > failures, &metric.ExecutionRecord{}
+}
+
+func TestAwaitReadiness(t *testing.T) {
+	invoker := &fakeReadinessInvoker{
+		failures:    map[string]int{"ready": 0, "slow": 2, "never": -1},
+		invocations: make(map[string]int),
+	}
+	d := &Driver{
+		Configuration: &config.Configuration{
+			LoaderConfiguration: &config.LoaderConfiguration{
+				ReadinessTimeoutSeconds: 3,
+				ReadinessPolicy:         common.ReadinessPolicyDrop,
+				PreWarm:                 true,
+			},
+			Functions: []*common.Function{
+				{Name: "ready", InitialScale: 3},
+				{Name: "slow", InitialScale: 0},
+				{Name: "never", InitialScale: 1},
+			},
+		},
+		Invoker: invoker,
+	}
+
+	deployed := d.Configuration.Functions
+	if err := d.awaitReadiness(); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(d.Configuration.Functions) != 2 || d.Configuration.Functions[0].Name != "ready" || d.Configuration.Functions[1].Name != "slow" {
+		t.Errorf("Only functions that became ready should be kept, got %d functions", len(d.Configuration.Functions))
+	}
+
+	// one probe followed by the pre-warm invocations
+	if invoker.invocations["ready"] != 4 {
+		t.Errorf("Expected 4 invocations of the ready function, got %d", invoker.invocations["ready"])
+	}
+	if invoker.invocations["slow"] != 3 {
+		t.Errorf("Expected 3 probes of the slow function, got %d", invoker.invocations["slow"])
+	}
+	if invoker.invocations["never"] < 2 {
+		t.Errorf("Functions should be probed until the timeout, got %d probes", invoker.invocations["never"])
+	}
+	if deployed[2] == nil || deployed[2].Name != "never" {
+		t.Error("Dropped functions should be kept in the deployed functions, to be cleaned up")
+	}
+}
+
+func TestAwaitReadinessFailure(t *testing.T) {
+	for _, policy := range []string{common.ReadinessPolicyAbort, common.ReadinessPolicyDrop} {
+		d := &Driver{
+			Configuration: &config.Configuration{
+				LoaderConfiguration: &config.LoaderConfiguration{ReadinessTimeoutSeconds: 1, ReadinessPolicy: policy},
+				Functions:           []*common.Function{{Name: "never"}},
+			},
+			Invoker: &fakeReadinessInvoker{failures: map[string]int{"never": -1}, invocations: make(map[string]int)},
+		}
+
+		// aborting or dropping every function is left to the caller, which cleans the deployment up
+		if err := d.awaitReadiness(); err == nil {
+			t.Errorf("Expected an error with the %s policy", policy)
+		}
+	}
+}
+
+func TestAwaitReadinessDisabled(t *testing.T) {
+	invoker := &fakeReadinessInvoker{failures: map[string]int{}, invocations: make(map[string]int)}
+	d := &Driver{
+		Configuration: &config.Configuration{
+			LoaderConfiguration: &config.LoaderConfiguration{},
+			Functions:           []*common.Function{{Name: "function", InitialScale: 1}},
+		},
+		Invoker: invoker,
+	}
+
+	if err := d.awaitReadiness(); err != nil {
+		t.Fatal(err)
+	}
+
+	if invoker.invocations["function"] != 0 {
+		t.Errorf("Functions should not be probed without readiness timeout")
+	}
+}
+
+// fakeAsyncReadinessInvoker accepts every invocation, whose result is collected on the second poll, as failed for
+// the failing function, and never for the pending one.
+type fakeAsyncReadinessInvoker struct {
+	mutex sync.Mutex
+	polls map[string]int
+}
+
+func (i *fakeAsyncReadinessInvoker) Invoke(function *common.Function, _ *common.RuntimeSpecification, invocationID string) (bool, *metric.ExecutionRecord) {
+	return true, &metric.ExecutionRecord{
+		ExecutionRecordBase: metric.ExecutionRecordBase{InvocationID: invocationID},
+		AsyncResponseID:     invocationID,
+		Function:            function.Name,
+	}
+}
+
+func (i *fakeAsyncReadinessInvoker) Collect(record *metric.ExecutionRecord) (bool, error) {
+	i.mutex.Lock()
+	defer i.mutex.Unlock()
+
+	i.polls[record.Function]++
+	if record.Function == "pending" || i.polls[record.Function] < 2 {
+		return false, nil
+	}
+
+	record.FunctionTimeout = record.Function == "failing"
+	return true, nil
+}
+
+func TestAwaitReadinessAsync(t *testing.T) {
+	for _, callbackAddress := range []string{"", "localhost:0"} {
+		d := &Driver{
+			Configuration: &config.Configuration{
+				LoaderConfiguration: &config.LoaderConfiguration{
+					ReadinessTimeoutSeconds: 2,
+					ReadinessPolicy:         common.ReadinessPolicyDrop,
+					AsyncCallbackAddress:    callbackAddress,
+				},
+				Functions: []*common.Function{{Name: "completed"}, {Name: "failing"}, {Name: "pending"}},
+			},
+			Invoker: &fakeAsyncReadinessInvoker{polls: make(map[string]int)},
+		}
+
+		if err := d.awaitReadiness(); err != nil {
+			t.Fatal(err)
+		}
+
+		// results delivered to the callback receiver cannot be awaited before the experiment
+		expected := []string{"completed"}
+		if callbackAddress != "" {
+			expected = []string{"completed", "failing", "pending"}
+		}
+
+		var names []string
+		for _, function := range d.Configuration.Functions {
+			names = append(names, function.Name)
+		}
+		if !slices.Equal(names, expected) {
+			t.Errorf("Expected functions %v to be ready with callback address '%s', got %v", expected, callbackAddress, names)
+		}
+	}
+}
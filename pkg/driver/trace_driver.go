@@ -28,8 +28,10 @@ import (
 	"container/list"
 	"encoding/json"
 	"fmt"
+	"io"
 	"os"
 	"strconv"
+	"strings"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -50,10 +52,13 @@ type Driver struct {
 	Configuration          *config.Configuration
 	SpecificationGenerator *generator.SpecificationGenerator
 	Invoker                clients.Invoker
+	Deployer               deployment.FunctionDeployer
 
-	AsyncRecords          *common.LockFreeQueue[*mc.ExecutionRecord]
-	readOpenWhiskMetadata sync.Mutex
-	allFunctionsInvoked   sync.WaitGroup
+	AsyncRecords      *common.LockFreeQueue[*mc.ExecutionRecord]
+	asyncCollector    *asyncCollector
+	coldStarts        *mc.ColdStartSummarizer
+	invocationSummary *mc.InvocationSummarizer
+	reporter          *mc.ExperimentReporter
 }
 
 func NewDriver(driverConfig *config.Configuration) *Driver {
@@ -61,12 +66,13 @@ func NewDriver(driverConfig *config.Configuration) *Driver {
 		Configuration:          driverConfig,
 		SpecificationGenerator: generator.NewSpecificationGenerator(driverConfig.LoaderConfiguration.Seed),
 
-		AsyncRecords:          common.NewLockFreeQueue[*mc.ExecutionRecord](),
-		readOpenWhiskMetadata: sync.Mutex{},
-		allFunctionsInvoked:   sync.WaitGroup{},
+		AsyncRecords: common.NewLockFreeQueue[*mc.ExecutionRecord](),
+		coldStarts:   mc.NewColdStartSummarizer(),
+		reporter:     mc.NewExperimentReporter(),
 	}
 
-	d.Invoker = clients.CreateInvoker(driverConfig, &d.allFunctionsInvoked, &d.readOpenWhiskMetadata)
+	d.Invoker = clients.CreateInvoker(driverConfig)
+	d.Deployer = deployment.CreateDeployer(driverConfig)
 
 	return d
 }
@@ -78,6 +84,51 @@ func (d *Driver) outputFilename(name string) string {
 	return fmt.Sprintf("%s_%s_%d.csv", d.Configuration.LoaderConfiguration.OutputPathPrefix, name, d.Configuration.TraceDuration)
 }
 
+// recordFilename returns the path of an output written in the configured format, which is CSV by default.
+func (d *Driver) recordFilename(name string) string {
+	cfg := d.Configuration.LoaderConfiguration
+	return fmt.Sprintf("%s_%s_%d%s", cfg.OutputPathPrefix, name, d.Configuration.TraceDuration,
+		mc.OutputExtension(cfg.OutputFormat, cfg.OutputCompression))
+}
+
+// createRecordSink creates the output written in the format, in the file named after the configured format.
+func (d *Driver) createRecordSink(name string, format string) mc.RecordSink {
+	sink, err := mc.NewRecordSink(d.recordFilename(name), format, d.Configuration.LoaderConfiguration.OutputCompression)
+	if err != nil {
+		log.Fatalf("Failed to create the %s output - %v", name, err)
+	}
+
+	return sink
+}
+
+// granularityInterval returns the duration of an entry of the trace, i.e., a minute or a second.
+func (d *Driver) granularityInterval() time.Duration {
+	if d.Configuration.TraceGranularity == common.SecondGranularity {
+		return time.Second
+	}
+
+	return time.Minute
+}
+
+// createInvocationSummarizer creates the summary of the invocations per minute, or per second with the second
+// trace granularity.
+func (d *Driver) createInvocationSummarizer() *mc.InvocationSummarizer {
+	buckets := 0
+	for _, function := range d.Configuration.Functions {
+		if function.Specification != nil {
+			buckets = max(buckets, len(function.Specification.PerMinuteCount))
+		}
+	}
+
+	summarizer, err := mc.NewInvocationSummarizer(d.outputFilename("invocation_summary"), buckets, d.granularityInterval(),
+		d.Configuration.LoaderConfiguration.WarmupDuration)
+	if err != nil {
+		log.Fatalf("Failed to create the invocation summary - %v", err)
+	}
+
+	return summarizer
+}
+
 /////////////////////////////////////////
 // DRIVER LOGIC
 /////////////////////////////////////////
@@ -92,7 +143,7 @@ type InvocationMetadata struct {
 	SuccessCount        *int64
 	FailedCount         *int64
 	FunctionsInvoked    *int64
-	RecordOutputChannel chan *mc.ExecutionRecord
+	RecordCollector     mc.RecordCollector
 	AnnounceDoneWG      *sync.WaitGroup
 	AnnounceDoneExe     *sync.WaitGroup
 }
@@ -125,7 +176,7 @@ func (d *Driver) invokeFunction(metadata *InvocationMetadata) {
 		function := node.Value.(*common.Node).Function
 		runtimeSpecifications = &function.Specification.RuntimeSpecification[metadata.IatIndex]
 
-		success, record = d.Invoker.Invoke(function, runtimeSpecifications)
+		success, record = d.Invoker.Invoke(function, runtimeSpecifications, metadata.InvocationID)
 
 		if !success && (d.Configuration.LoaderConfiguration.DAGMode && invocationRetries == 0) {
 			log.Debugf("Invocation with for function %s with ID %s failed. Retrying Invocation", function.Name, metadata.InvocationID)
@@ -133,15 +184,18 @@ func (d *Driver) invokeFunction(metadata *InvocationMetadata) {
 			continue
 		}
 		record.Phase = int(metadata.Phase)
+		record.Function = function.Name
 		record.Instance = fmt.Sprintf("%s%s", node.Value.(*common.Node).DAG, record.Instance)
 		record.InvocationID = metadata.InvocationID
 
-		if d.Configuration.DirigentConfiguration != nil &&
-			d.Configuration.DirigentConfiguration.AsyncMode && record.AsyncResponseID != "" {
+		if d.asyncCollector != nil && record.AsyncResponseID != "" {
 			record.TimeToSubmitMs = record.ResponseTime
+			d.asyncCollector.submit(record)
+		} else if d.Configuration.LoaderConfiguration.Platform == common.PlatformOpenWhisk && record.ActivationID != "" {
+			// completed with the OpenWhisk activation record once the experiment ends
 			d.AsyncRecords.Enqueue(record)
 		} else {
-			metadata.RecordOutputChannel <- record
+			metadata.RecordCollector.Collect(record)
 		}
 		atomic.AddInt64(metadata.FunctionsInvoked, 1)
 		if !success {
@@ -163,7 +217,7 @@ func (d *Driver) invokeFunction(metadata *InvocationMetadata) {
 	}
 }
 
-func (d *Driver) functionsDriver(functionLinkedList *list.List, announceFunctionDone *sync.WaitGroup, addInvocationsToGroup *sync.WaitGroup, totalSuccessful *int64, totalFailed *int64, totalIssued *int64, recordOutputChannel chan *mc.ExecutionRecord) {
+func (d *Driver) functionsDriver(functionLinkedList *list.List, announceFunctionDone *sync.WaitGroup, addInvocationsToGroup *sync.WaitGroup, totalSuccessful *int64, totalFailed *int64, totalIssued *int64, recordCollector mc.RecordCollector) {
 	defer announceFunctionDone.Done()
 
 	function := functionLinkedList.Front().Value.(*common.Node).Function
@@ -175,6 +229,10 @@ func (d *Driver) functionsDriver(functionLinkedList *list.List, announceFunction
 		return
 	}
 
+	if d.invocationSummary != nil {
+		d.invocationSummary.Target(function.Specification.PerMinuteCount)
+	}
+
 	// result statistics
 	minuteIndexSearch := common.NewIntervalSearch(function.Specification.PerMinuteCount)
 	interval := minuteIndexSearch.SearchInterval(0)
@@ -211,6 +269,10 @@ func (d *Driver) functionsDriver(functionLinkedList *list.List, announceFunction
 
 		previousIATSum += iat.Microseconds()
 
+		if d.invocationSummary != nil {
+			d.invocationSummary.Issued(minuteIndex, invocationSinceTheBeginningOfMinute == 0)
+		}
+
 		if !d.Configuration.TestMode {
 			waitForInvocations.Add(1)
 			go d.invokeFunction(&InvocationMetadata{
@@ -221,7 +283,7 @@ func (d *Driver) functionsDriver(functionLinkedList *list.List, announceFunction
 				SuccessCount:        &successfulInvocations,
 				FailedCount:         &failedInvocations,
 				FunctionsInvoked:    &functionsInvoked,
-				RecordOutputChannel: recordOutputChannel,
+				RecordCollector:     recordCollector,
 				AnnounceDoneWG:      &waitForInvocations,
 				AnnounceDoneExe:     addInvocationsToGroup,
 			})
@@ -230,13 +292,13 @@ func (d *Driver) functionsDriver(functionLinkedList *list.List, announceFunction
 			invocationID := composeInvocationID(d.Configuration.TraceGranularity, minuteIndex, invocationSinceTheBeginningOfMinute)
 			log.Debugf("Test mode invocation fired - ID = %s.\n", invocationID)
 
-			recordOutputChannel <- &mc.ExecutionRecord{
+			recordCollector.Collect(&mc.ExecutionRecord{
 				ExecutionRecordBase: mc.ExecutionRecordBase{
 					Phase:        int(currentPhase),
 					InvocationID: invocationID,
 					StartTime:    time.Now().UnixNano(),
 				},
-			}
+			})
 			functionsInvoked++
 			successfulInvocations++
 		}
@@ -332,7 +394,7 @@ func (d *Driver) globalTimekeeper(totalTraceDuration int, signalReady *sync.Wait
 	ticker.Stop()
 }
 
-func (d *Driver) startBackgroundProcesses(allRecordsWritten *sync.WaitGroup) (*sync.WaitGroup, chan *mc.ExecutionRecord, chan int64, chan int) {
+func (d *Driver) startBackgroundProcesses(allRecordsWritten *sync.WaitGroup) (*sync.WaitGroup, *mc.ShardedCollector, chan int) {
 	auxiliaryProcessBarrier := &sync.WaitGroup{}
 
 	finishCh := make(chan int, 1)
@@ -345,19 +407,20 @@ func (d *Driver) startBackgroundProcesses(allRecordsWritten *sync.WaitGroup) (*s
 		go metricsScrapper()
 	}
 
-	auxiliaryProcessBarrier.Add(2)
+	auxiliaryProcessBarrier.Add(1)
 
-	globalMetricsCollector := make(chan *mc.ExecutionRecord)
-	totalIssuedChannel := make(chan int64)
-	go mc.CreateGlobalMetricsCollector(d.outputFilename("duration"), globalMetricsCollector, auxiliaryProcessBarrier, allRecordsWritten, totalIssuedChannel)
+	d.invocationSummary = d.createInvocationSummarizer()
+	recordCollector := mc.NewShardedCollector(d.createRecordSink("duration", d.Configuration.LoaderConfiguration.OutputFormat), 0, 0,
+		d.coldStarts, d.invocationSummary, d.reporter)
 
 	traceDurationInMinutes := d.Configuration.TraceDuration
 	go d.globalTimekeeper(traceDurationInMinutes, auxiliaryProcessBarrier)
 
-	return auxiliaryProcessBarrier, globalMetricsCollector, totalIssuedChannel, finishCh
+	return auxiliaryProcessBarrier, recordCollector, finishCh
 }
 
-func (d *Driver) internalRun() {
+// internalRun issues the invocations and writes their records, returning the time the invocations started.
+func (d *Driver) internalRun() time.Time {
 	var successfulInvocations int64
 	var failedInvocations int64
 	var invocationsIssued int64
@@ -365,11 +428,26 @@ func (d *Driver) internalRun() {
 	allFunctionsInvoked := sync.WaitGroup{}
 	allIndividualDriversCompleted := sync.WaitGroup{}
 	allRecordsWritten := sync.WaitGroup{}
-	allRecordsWritten.Add(1)
 
-	backgroundProcessesInitializationBarrier, globalMetricsCollector, totalIssuedChannel, scraperFinishCh := d.startBackgroundProcesses(&allRecordsWritten)
+	backgroundProcessesInitializationBarrier, recordCollector, scraperFinishCh := d.startBackgroundProcesses(&allRecordsWritten)
 	backgroundProcessesInitializationBarrier.Wait()
 
+	if address := d.Configuration.LoaderConfiguration.AsyncCallbackAddress; address != "" {
+		parser, _ := d.Invoker.(clients.AsyncResponseParser)
+		receiver, err := newCallbackReceiver(address, parser, asyncCollectionTimeout(d.Configuration))
+		if err != nil {
+			log.Fatalf("Failed to start the callback receiver on %s - %v", address, err)
+		}
+		defer receiver.close()
+
+		d.asyncCollector = newAsyncCollector(receiver, recordCollector, d.Configuration)
+	} else if source, ok := d.Invoker.(clients.AsyncCollector); ok {
+		d.asyncCollector = newAsyncCollector(source, recordCollector, d.Configuration)
+	}
+
+	d.invocationSummary.Start()
+	invocationsStart := time.Now()
+
 	if d.Configuration.LoaderConfiguration.DAGMode {
 		functions := d.Configuration.Functions
 		dagLists := generator.GenerateDAGs(d.Configuration.LoaderConfiguration, functions, false)
@@ -383,7 +461,7 @@ func (d *Driver) internalRun() {
 				&successfulInvocations,
 				&failedInvocations,
 				&invocationsIssued,
-				globalMetricsCollector,
+				recordCollector,
 			)
 		}
 	} else {
@@ -399,7 +477,7 @@ func (d *Driver) internalRun() {
 				&successfulInvocations,
 				&failedInvocations,
 				&invocationsIssued,
-				globalMetricsCollector,
+				recordCollector,
 			)
 		}
 	}
@@ -407,19 +485,30 @@ func (d *Driver) internalRun() {
 	if atomic.LoadInt64(&successfulInvocations)+atomic.LoadInt64(&failedInvocations) != 0 {
 		log.Debugf("Waiting for all the invocations record to be written.\n")
 
-		if d.Configuration.DirigentConfiguration != nil && d.Configuration.DirigentConfiguration.AsyncMode {
-			sleepFor := time.Duration(d.Configuration.DirigentConfiguration.AsyncWaitToCollectMin) * time.Minute
-
-			log.Infof("Sleeping for %v...", sleepFor)
-			time.Sleep(sleepFor)
-
-			d.writeAsyncRecordsToLog(globalMetricsCollector)
+		if d.asyncCollector != nil {
+			d.asyncCollector.finish()
 		}
-		totalIssuedChannel <- atomic.LoadInt64(&invocationsIssued)
-		scraperFinishCh <- 0 // Ask the scraper to finish metrics collection
+		if d.Configuration.LoaderConfiguration.Platform == common.PlatformOpenWhisk {
+			d.writeOpenWhiskRecordsToLog(recordCollector)
+		}
+	}
 
-		allRecordsWritten.Wait()
+	// all the records have been collected once the drivers and the asynchronous collection complete
+	written, err := recordCollector.Close()
+	if err != nil {
+		log.Errorf("Failed to write the invocation records - %v", err)
 	}
+	if issued := atomic.LoadInt64(&invocationsIssued); written != issued {
+		log.Warnf("Wrote %d invocation records for %d issued invocations", written, issued)
+	}
+	if err = d.invocationSummary.Close(); err != nil {
+		log.Errorf("Failed to write the invocation summary - %v", err)
+	}
+
+	scraperFinishCh <- 0 // Ask the scraper to finish metrics collection
+	allRecordsWritten.Wait()
+	d.writeColdStartSummary()
+	d.writeExperimentReport()
 
 	statSuccess := atomic.LoadInt64(&successfulInvocations)
 	statFailed := atomic.LoadInt64(&failedInvocations)
@@ -429,6 +518,8 @@ func (d *Driver) internalRun() {
 	log.Infof("Number of failed invocations: \t%d", statFailed)
 	log.Infof("Total invocations: \t\t\t%d", statSuccess+statFailed)
 	log.Infof("Failure rate: \t\t\t%.2f%%", float64(statFailed)*100.0/float64(statSuccess+statFailed))
+
+	return invocationsStart
 }
 
 // Writes OR Reads IATs to/from .json files.
@@ -471,21 +562,79 @@ func ReadOrWriteSpecificationToFile(functions []*common.Function, writeIATsToFil
 
 
 
-func (d *Driver) RunExperiment() {
+func (d *Driver) prepareDeployment() {
 	if d.Configuration.WithWarmup() {
 		trace.DoStaticTraceProfiling(d.Configuration.Functions)
 	}
 
 	trace.ApplyResourceLimits(d.Configuration.Functions, d.Configuration.LoaderConfiguration.CPULimit)
+	trace.ApplyAutoscalingPolicies(d.Configuration.Functions, d.Configuration.AutoscalingConfiguration)
+}
+
+// RenderDeployment writes the artifacts the deployer of the platform would deploy to the output directory, without
+// deploying the functions or running the experiment.
+func (d *Driver) RenderDeployment(outputDir string) {
+	d.prepareDeployment()
+
+	renderer, ok := d.Deployer.(deployment.ArtifactRenderer)
+	if !ok {
+		log.Fatalf("Rendering the deployment is not supported on platform %s", d.Configuration.LoaderConfiguration.Platform)
+	}
+
+	if err := renderer.Render(d.Configuration, outputDir); err != nil {
+		log.Fatalf("Failed to render the deployment - %v", err)
+	}
+
+	log.Infof("Deployment of %d functions written to %s", len(d.Configuration.Functions), outputDir)
+}
 
-	deployer := deployment.CreateDeployer(d.Configuration)
-	deployer.Deploy(d.Configuration)
+func (d *Driver) RunExperiment() {
+	start := time.Now()
+	d.prepareDeployment()
+
+	manifestPath := strings.TrimSuffix(d.outputFilename("manifest"), ".csv") + ".json"
+	manifest := d.newRunManifest(start)
+	manifest.write(manifestPath)
+
+	if err := d.Deployer.Deploy(d.Configuration); err != nil {
+		// removes the functions deployed before the failure
+		d.Deployer.Clean()
+		log.Fatalf("Failed to deploy the functions - %v", err)
+	}
+	if err := d.awaitReadiness(); err != nil {
+		// removes the functions that are deployed but will not be invoked
+		d.Deployer.Clean()
+		log.Fatalf("Functions failed the readiness checks - %v", err)
+	}
+	manifest.setFunctions(d.Configuration.Functions)
+	manifest.addPhase("deployment", start, time.Now())
 
-	go failure.ScheduleFailure(d.Configuration.LoaderConfiguration.Platform, d.Configuration.FailureConfiguration)
+	failureScheduler := failure.NewScheduler(d.Configuration.LoaderConfiguration.Platform, d.Configuration.FailureConfiguration,
+		time.Duration(d.Configuration.TraceDuration)*time.Minute, d.Configuration.LoaderConfiguration.Seed)
+	failureScheduler.Start()
 
 	// Generate load
-	d.internalRun()
+	invocationsStart := d.internalRun()
+	executionStart := invocationsStart.Add(time.Duration(d.Configuration.LoaderConfiguration.WarmupDuration) * d.granularityInterval())
+	if d.Configuration.WithWarmup() {
+		manifest.addPhase("warmup", invocationsStart, executionStart)
+	}
+	manifest.addPhase("execution", executionStart, time.Now())
+
+	failures := failureScheduler.Stop()
+	d.writeFailureRecords(failures)
+	d.writeFailureImpact(failures)
 
 	// Clean up
-	deployer.Clean()
+	cleanupStart := time.Now()
+	if closer, ok := d.Invoker.(io.Closer); ok {
+		if err := closer.Close(); err != nil {
+			log.Warnf("Failed to release invoker resources - %v", err)
+		}
+	}
+	d.Deployer.Clean()
+	manifest.addPhase("cleanup", cleanupStart, time.Now())
+
+	manifest.finish(d.Configuration.LoaderConfiguration.OutputPathPrefix, manifestPath)
+	manifest.write(manifestPath)
 }
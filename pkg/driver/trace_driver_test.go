@@ -44,6 +44,20 @@ import (
 	"github.com/vhive-serverless/loader/pkg/workload/vswarm"
 )
 
+// channelCollector passes the collected records to the test through the channel.
+type channelCollector chan *metric.ExecutionRecord
+
+func (c channelCollector) Collect(record *metric.ExecutionRecord) {
+	c <- record
+}
+
+// noopDeployer leaves the functions as configured by the test, without a cluster to deploy them to.
+type noopDeployer struct{}
+
+func (noopDeployer) Deploy(*config.Configuration) error { return nil }
+
+func (noopDeployer) Clean() {}
+
 func createFakeLoaderConfiguration(vSwarm bool) *config.LoaderConfiguration {
 	return &config.LoaderConfiguration{
 		Platform:                     common.PlatformKnative,
@@ -151,15 +165,15 @@ func TestInvokeFunctionFromDriver(t *testing.T) {
 				Memory:  128,
 			}}
 			metadata := &InvocationMetadata{
-				RootFunction:        list,
-				Phase:               common.ExecutionPhase,
-				IatIndex:            0,
-				InvocationID:        composeInvocationID(common.MinuteGranularity, 0, 0),
-				SuccessCount:        &successCount,
-				FailedCount:         &failureCount,
-				FunctionsInvoked:    &functionsInvoked,
-				RecordOutputChannel: invocationRecordOutputChannel,
-				AnnounceDoneWG:      announceDone,
+				RootFunction:     list,
+				Phase:            common.ExecutionPhase,
+				IatIndex:         0,
+				InvocationID:     composeInvocationID(common.MinuteGranularity, 0, 0),
+				SuccessCount:     &successCount,
+				FailedCount:      &failureCount,
+				FunctionsInvoked: &functionsInvoked,
+				RecordCollector:  channelCollector(invocationRecordOutputChannel),
+				AnnounceDoneWG:   announceDone,
 			}
 
 			announceDone.Add(1)
@@ -234,15 +248,15 @@ func TestVSwarmInvokeFunctionFromDriver(t *testing.T) {
 				Memory:  128,
 			}}
 			metadata := &InvocationMetadata{
-				RootFunction:        list,
-				Phase:               common.ExecutionPhase,
-				IatIndex:            0,
-				InvocationID:        composeInvocationID(common.MinuteGranularity, 0, 0),
-				SuccessCount:        &successCount,
-				FailedCount:         &failureCount,
-				FunctionsInvoked:    &functionsInvoked,
-				RecordOutputChannel: invocationRecordOutputChannel,
-				AnnounceDoneWG:      announceDone,
+				RootFunction:     list,
+				Phase:            common.ExecutionPhase,
+				IatIndex:         0,
+				InvocationID:     composeInvocationID(common.MinuteGranularity, 0, 0),
+				SuccessCount:     &successCount,
+				FailedCount:      &failureCount,
+				FunctionsInvoked: &functionsInvoked,
+				RecordCollector:  channelCollector(invocationRecordOutputChannel),
+				AnnounceDoneWG:   announceDone,
 			}
 
 			announceDone.Add(1)
@@ -314,15 +328,15 @@ func TestDAGInvocation(t *testing.T) {
 	time.Sleep(2 * time.Second)
 
 	metadata := &InvocationMetadata{
-		RootFunction:        rootFunction,
-		Phase:               common.ExecutionPhase,
-		IatIndex:            0,
-		InvocationID:        composeInvocationID(common.MinuteGranularity, 0, 0),
-		SuccessCount:        &successCount,
-		FailedCount:         &failureCount,
-		FunctionsInvoked:    &functionsInvoked,
-		RecordOutputChannel: invocationRecordOutputChannel,
-		AnnounceDoneWG:      announceDone,
+		RootFunction:     rootFunction,
+		Phase:            common.ExecutionPhase,
+		IatIndex:         0,
+		InvocationID:     composeInvocationID(common.MinuteGranularity, 0, 0),
+		SuccessCount:     &successCount,
+		FailedCount:      &failureCount,
+		FunctionsInvoked: &functionsInvoked,
+		RecordCollector:  channelCollector(invocationRecordOutputChannel),
+		AnnounceDoneWG:   announceDone,
 	}
 
 	announceDone.Add(1)
@@ -385,15 +399,15 @@ func TestVSwarmDAGInvocation(t *testing.T) {
 	time.Sleep(2 * time.Second)
 
 	metadata := &InvocationMetadata{
-		RootFunction:        rootFunction,
-		Phase:               common.ExecutionPhase,
-		IatIndex:            0,
-		InvocationID:        composeInvocationID(common.MinuteGranularity, 0, 0),
-		SuccessCount:        &successCount,
-		FailedCount:         &failureCount,
-		FunctionsInvoked:    &functionsInvoked,
-		RecordOutputChannel: invocationRecordOutputChannel,
-		AnnounceDoneWG:      announceDone,
+		RootFunction:     rootFunction,
+		Phase:            common.ExecutionPhase,
+		IatIndex:         0,
+		InvocationID:     composeInvocationID(common.MinuteGranularity, 0, 0),
+		SuccessCount:     &successCount,
+		FailedCount:      &failureCount,
+		FunctionsInvoked: &functionsInvoked,
+		RecordCollector:  channelCollector(invocationRecordOutputChannel),
+		AnnounceDoneWG:   announceDone,
 	}
 
 	announceDone.Add(1)
@@ -415,15 +429,7 @@ func TestVSwarmDAGInvocation(t *testing.T) {
 func TestGlobalMetricsCollector(t *testing.T) {
 	driver := createTestDriver([]int{5}, false)
 
-	inputChannel := make(chan *metric.ExecutionRecord)
-	totalIssuedChannel := make(chan int64)
-	collectorReady, collectorFinished := &sync.WaitGroup{}, &sync.WaitGroup{}
-
-	collectorReady.Add(1)
-	collectorFinished.Add(1)
-
-	go metric.CreateGlobalMetricsCollector(driver.outputFilename("duration"), inputChannel, collectorReady, collectorFinished, totalIssuedChannel)
-	collectorReady.Wait()
+	collector := metric.NewShardedCollector(driver.createRecordSink("duration", ""), 0, 0)
 
 	bogusRecord := &metric.ExecutionRecord{
 		ExecutionRecordBase: metric.ExecutionRecordBase{
@@ -443,11 +449,13 @@ func TestGlobalMetricsCollector(t *testing.T) {
 	}
 
 	for i := 0; i < driver.Configuration.Functions[0].InvocationStats.Invocations[0]; i++ {
-		inputChannel <- bogusRecord
+		collector.Collect(bogusRecord)
 	}
 
-	totalIssuedChannel <- int64(driver.Configuration.Functions[0].InvocationStats.Invocations[0])
-	collectorFinished.Wait()
+	written, err := collector.Close()
+	if err != nil || written != int64(driver.Configuration.Functions[0].InvocationStats.Invocations[0]) {
+		t.Errorf("Expected all the records to be written, got %d - %v", written, err)
+	}
 
 	f, err := os.Open(driver.outputFilename("duration"))
 	if err != nil {
@@ -492,7 +500,7 @@ func TestDriverBackgroundProcesses(t *testing.T) {
 			driver := createTestDriver([]int{5}, false)
 			globalCollectorAnnounceDone := &sync.WaitGroup{}
 
-			completed, _, _, _ := driver.startBackgroundProcesses(globalCollectorAnnounceDone)
+			completed, _, _ := driver.startBackgroundProcesses(globalCollectorAnnounceDone)
 
 			completed.Wait()
 		})
@@ -585,6 +593,7 @@ func TestDriverCompletely(t *testing.T) {
 				driver.Configuration.Functions, driver.Configuration.LoaderConfiguration,
 				iatDistribution, shiftIAT, driver.Configuration.TraceGranularity)
 
+			driver.Deployer = noopDeployer{}
 			driver.RunExperiment()
 
 			f, err := os.Open(driver.outputFilename("duration"))
@@ -687,6 +696,7 @@ func TestVSwarmDriverCompletely(t *testing.T) {
 				driver.Configuration.Functions, driver.Configuration.LoaderConfiguration,
 				iatDistribution, shiftIAT, driver.Configuration.TraceGranularity)
 
+			driver.Deployer = noopDeployer{}
 			driver.RunExperiment()
 
 			f, err := os.Open(driver.outputFilename("duration"))
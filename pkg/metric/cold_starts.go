@@ -0,0 +1,104 @@
+package metric
+
+import (
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/vhive-serverless/loader/pkg/common"
+)
+
+type ColdStartSummary struct {
+	Function    string `csv:"function"`
+	Invocations int    `csv:"invocations"`
+	// invocations for which the platform or the function instance reported whether they were cold
+	Reported   int     `csv:"reported"`
+	ColdStarts int     `csv:"coldStarts"`
+	ColdRatio  float64 `csv:"coldStartRatio"`
+	Instances  int     `csv:"instances"`
+	// Measurements in microseconds, over the cold starts with a known init time
+	AvgInitTime float64 `csv:"avgInitTime"`
+}
+
+type coldStartStatistics struct {
+	ColdStartSummary
+
+	instances     map[string]struct{}
+	initTimeSum   int64
+	initTimeCount int
+}
+
+// ColdStartSummarizer aggregates the start type of the execution records per function. Records of the warmup
+// phase, which mostly consist of cold starts, are not included.
+type ColdStartSummarizer struct {
+	mutex     sync.Mutex
+	functions map[string]*coldStartStatistics
+}
+
+func NewColdStartSummarizer() *ColdStartSummarizer {
+	return &ColdStartSummarizer{
+		functions: make(map[string]*coldStartStatistics),
+	}
+}
+
+func (s *ColdStartSummarizer) Add(record *ExecutionRecord) {
+	if record.Phase == int(common.WarmupPhase) {
+		return
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	statistics, ok := s.functions[record.Function]
+	if !ok {
+		statistics = &coldStartStatistics{
+			ColdStartSummary: ColdStartSummary{Function: record.Function},
+			instances:        make(map[string]struct{}),
+		}
+		s.functions[record.Function] = statistics
+	}
+
+	statistics.Invocations++
+	if record.InstanceID != "" {
+		statistics.instances[record.InstanceID] = struct{}{}
+	}
+
+	switch record.StartType {
+	case Cold:
+		statistics.Reported++
+		statistics.ColdStarts++
+
+		if record.InitTime > 0 {
+			statistics.initTimeSum += record.InitTime
+			statistics.initTimeCount++
+		}
+	case Hot:
+		statistics.Reported++
+	}
+}
+
+// Summaries returns the summary of each function, sorted by function name.
+func (s *ColdStartSummarizer) Summaries() []ColdStartSummary {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	result := make([]ColdStartSummary, 0, len(s.functions))
+	for _, statistics := range s.functions {
+		summary := statistics.ColdStartSummary
+		summary.Instances = len(statistics.instances)
+		if summary.Reported > 0 {
+			summary.ColdRatio = float64(summary.ColdStarts) / float64(summary.Reported)
+		}
+		if statistics.initTimeCount > 0 {
+			summary.AvgInitTime = float64(statistics.initTimeSum) / float64(statistics.initTimeCount)
+		}
+
+		result = append(result, summary)
+	}
+
+	slices.SortFunc(result, func(a, b ColdStartSummary) int {
+		return strings.Compare(a.Function, b.Function)
+	})
+
+	return result
+}
@@ -0,0 +1,44 @@
+package metric
+
+import (
+	"testing"
+
+	"github.com/vhive-serverless/loader/pkg/common"
+)
+
+func TestColdStartSummarizer(t *testing.T) {
+	summarizer := NewColdStartSummarizer()
+
+	add := func(function string, phase common.ExperimentPhase, startType StartType, instanceID string, initTime int64) {
+		summarizer.Add(&ExecutionRecord{
+			ExecutionRecordBase: ExecutionRecordBase{Phase: int(phase)},
+			StartType:           startType,
+			InstanceID:          instanceID,
+			InitTime:            initTime,
+			Function:            function,
+		})
+	}
+
+	add("b", common.ExecutionPhase, Cold, "i1", 100)
+	add("b", common.ExecutionPhase, Hot, "i1", 0)
+	add("b", common.ExecutionPhase, Cold, "i2", 300)
+	add("b", common.ExecutionPhase, Hot, "i2", 0)
+	add("b", common.ExecutionPhase, "", "", 0)
+	add("b", common.WarmupPhase, Cold, "i0", 1000)
+	add("a", common.ExecutionPhase, Cold, "", 0)
+
+	summaries := summarizer.Summaries()
+	if len(summaries) != 2 || summaries[0].Function != "a" || summaries[1].Function != "b" {
+		t.Fatalf("Unexpected summaries: %+v", summaries)
+	}
+
+	a, b := summaries[0], summaries[1]
+	if a.Invocations != 1 || a.ColdStarts != 1 || a.ColdRatio != 1 || a.Instances != 0 || a.AvgInitTime != 0 {
+		t.Errorf("Unexpected summary: %+v", a)
+	}
+	if b.Invocations != 5 || b.Reported != 4 || b.ColdStarts != 2 || b.ColdRatio != 0.5 ||
+		b.Instances != 2 || b.AvgInitTime != 200 {
+
+		t.Errorf("Unexpected summary: %+v", b)
+	}
+}
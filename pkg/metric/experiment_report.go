@@ -0,0 +1,244 @@
+package metric
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/vhive-serverless/loader/pkg/common"
+)
+
+// LatencyStatistics are nearest-rank percentiles in microseconds.
+type LatencyStatistics struct {
+	P50  int64 `json:"p50"`
+	P90  int64 `json:"p90"`
+	P99  int64 `json:"p99"`
+	P999 int64 `json:"p99.9"`
+}
+
+// SlowdownStatistics are the mean and the nearest-rank percentiles of the ratio of the response time to the
+// requested duration.
+type SlowdownStatistics struct {
+	Mean float64 `json:"mean"`
+	P50  float64 `json:"p50"`
+	P90  float64 `json:"p90"`
+	P99  float64 `json:"p99"`
+	P999 float64 `json:"p99.9"`
+}
+
+type ErrorBreakdown struct {
+	ConnectionTimeout       int `json:"connectionTimeout"`
+	FunctionTimeout         int `json:"functionTimeout"`
+	MemoryAllocationTimeout int `json:"memoryAllocationTimeout"`
+}
+
+// InvocationStatistics describe the invocations of the execution phase, of a function or of the whole experiment.
+// Response times and slowdowns are those of the successful invocations.
+type InvocationStatistics struct {
+	Function string `json:"function,omitempty"`
+
+	Requested int `json:"requested"`
+	Issued    int `json:"issued"`
+	// ratio of the issued to the requested invocations
+	IssuedAccuracy float64 `json:"issuedAccuracy"`
+
+	Successful int            `json:"successful"`
+	Failed     int            `json:"failed"`
+	Errors     ErrorBreakdown `json:"errors"`
+
+	// ratio of the cold starts to the invocations reporting their start type
+	ColdStartFraction float64 `json:"coldStartFraction"`
+
+	ResponseTime LatencyStatistics  `json:"responseTime"`
+	Slowdown     SlowdownStatistics `json:"slowdown"`
+}
+
+type ExperimentReport struct {
+	// invocations of the warmup phase, which are excluded from the statistics
+	WarmupInvocations int                    `json:"warmupInvocations"`
+	Overall           InvocationStatistics   `json:"overall"`
+	Functions         []InvocationStatistics `json:"functions"`
+}
+
+type invocationAggregate struct {
+	statistics    InvocationStatistics
+	reported      int
+	coldStarts    int
+	responseTimes []int64
+	slowdowns     []float64
+}
+
+func (a *invocationAggregate) add(record *ExecutionRecord) {
+	a.statistics.Issued++
+
+	switch record.StartType {
+	case Cold:
+		a.reported++
+		a.coldStarts++
+	case Hot:
+		a.reported++
+	}
+
+	if record.ConnectionTimeout {
+		a.statistics.Errors.ConnectionTimeout++
+	}
+	if record.FunctionTimeout {
+		a.statistics.Errors.FunctionTimeout++
+	}
+	if record.MemoryAllocationTimeout {
+		a.statistics.Errors.MemoryAllocationTimeout++
+	}
+
+	if failed(record) {
+		a.statistics.Failed++
+		return
+	}
+
+	a.statistics.Successful++
+	a.responseTimes = append(a.responseTimes, record.ResponseTime)
+	if record.RequestedDuration > 0 {
+		a.slowdowns = append(a.slowdowns, float64(record.ResponseTime)/float64(record.RequestedDuration))
+	}
+}
+
+func (a *invocationAggregate) summarize(requested int) InvocationStatistics {
+	statistics := a.statistics
+	statistics.Requested = requested
+	if requested > 0 {
+		statistics.IssuedAccuracy = float64(statistics.Issued) / float64(requested)
+	}
+	if a.reported > 0 {
+		statistics.ColdStartFraction = float64(a.coldStarts) / float64(a.reported)
+	}
+
+	responseTimes := slices.Sorted(slices.Values(a.responseTimes))
+	statistics.ResponseTime = LatencyStatistics{
+		P50:  percentile(responseTimes, 0.5),
+		P90:  percentile(responseTimes, 0.9),
+		P99:  percentile(responseTimes, 0.99),
+		P999: percentile(responseTimes, 0.999),
+	}
+
+	slowdowns := slices.Sorted(slices.Values(a.slowdowns))
+	statistics.Slowdown = SlowdownStatistics{
+		P50:  percentile(slowdowns, 0.5),
+		P90:  percentile(slowdowns, 0.9),
+		P99:  percentile(slowdowns, 0.99),
+		P999: percentile(slowdowns, 0.999),
+	}
+	for _, slowdown := range slowdowns {
+		statistics.Slowdown.Mean += slowdown / float64(len(slowdowns))
+	}
+
+	return statistics
+}
+
+// ExperimentReporter aggregates the execution records into the report of the experiment.
+type ExperimentReporter struct {
+	mutex     sync.Mutex
+	warmup    int
+	overall   invocationAggregate
+	functions map[string]*invocationAggregate
+}
+
+func NewExperimentReporter() *ExperimentReporter {
+	return &ExperimentReporter{
+		functions: make(map[string]*invocationAggregate),
+	}
+}
+
+func (r *ExperimentReporter) Add(record *ExecutionRecord) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if record.Phase == int(common.WarmupPhase) {
+		r.warmup++
+		return
+	}
+
+	function, ok := r.functions[record.Function]
+	if !ok {
+		function = &invocationAggregate{statistics: InvocationStatistics{Function: record.Function}}
+		r.functions[record.Function] = function
+	}
+
+	function.add(record)
+	r.overall.add(record)
+}
+
+// Report returns the report of the records added so far, given the invocations of the execution phase requested per
+// function. The functions are sorted by name.
+func (r *ExperimentReporter) Report(requested map[string]int) *ExperimentReport {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	report := &ExperimentReport{WarmupInvocations: r.warmup}
+
+	totalRequested := 0
+	for name, count := range requested {
+		totalRequested += count
+		if _, ok := r.functions[name]; !ok && count > 0 {
+			// functions that were never invoked are reported as well
+			r.functions[name] = &invocationAggregate{statistics: InvocationStatistics{Function: name}}
+		}
+	}
+	report.Overall = r.overall.summarize(totalRequested)
+
+	for name, function := range r.functions {
+		report.Functions = append(report.Functions, function.summarize(requested[name]))
+	}
+	slices.SortFunc(report.Functions, func(a, b InvocationStatistics) int {
+		return strings.Compare(a.Function, b.Function)
+	})
+
+	return report
+}
+
+// WriteExperimentReport writes the report as JSON and as Markdown tables.
+func WriteExperimentReport(report *ExperimentReport, jsonPath string, markdownPath string) error {
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err = os.WriteFile(jsonPath, data, 0644); err != nil {
+		return err
+	}
+
+	var markdown strings.Builder
+	markdown.WriteString("# Experiment report\n\n")
+	fmt.Fprintf(&markdown, "Statistics of the execution phase; %d invocations of the warmup phase are excluded. "+
+		"Response times are in milliseconds and, as the slowdowns, those of the successful invocations.\n\n", report.WarmupInvocations)
+
+	markdown.WriteString("## Invocations\n\n")
+	markdown.WriteString("| Function | Requested | Issued | Accuracy | Successful | Failed | Connection timeouts | Function timeouts | Memory allocation timeouts | Cold starts |\n")
+	markdown.WriteString("|---|---:|---:|---:|---:|---:|---:|---:|---:|---:|\n")
+	for _, statistics := range append([]InvocationStatistics{report.Overall}, report.Functions...) {
+		fmt.Fprintf(&markdown, "| %s | %d | %d | %.2f%% | %d | %d | %d | %d | %d | %.2f%% |\n", markdownFunctionName(statistics),
+			statistics.Requested, statistics.Issued, statistics.IssuedAccuracy*100, statistics.Successful, statistics.Failed,
+			statistics.Errors.ConnectionTimeout, statistics.Errors.FunctionTimeout, statistics.Errors.MemoryAllocationTimeout,
+			statistics.ColdStartFraction*100)
+	}
+
+	markdown.WriteString("\n## Response time and slowdown\n\n")
+	markdown.WriteString("| Function | p50 [ms] | p90 [ms] | p99 [ms] | p99.9 [ms] | Mean slowdown | p50 slowdown | p90 slowdown | p99 slowdown | p99.9 slowdown |\n")
+	markdown.WriteString("|---|---:|---:|---:|---:|---:|---:|---:|---:|---:|\n")
+	for _, statistics := range append([]InvocationStatistics{report.Overall}, report.Functions...) {
+		latency, slowdown := statistics.ResponseTime, statistics.Slowdown
+		fmt.Fprintf(&markdown, "| %s | %.2f | %.2f | %.2f | %.2f | %.2f | %.2f | %.2f | %.2f | %.2f |\n", markdownFunctionName(statistics),
+			float64(latency.P50)/1e3, float64(latency.P90)/1e3, float64(latency.P99)/1e3, float64(latency.P999)/1e3,
+			slowdown.Mean, slowdown.P50, slowdown.P90, slowdown.P99, slowdown.P999)
+	}
+
+	return os.WriteFile(markdownPath, []byte(markdown.String()), 0644)
+}
+
+func markdownFunctionName(statistics InvocationStatistics) string {
+	if statistics.Function == "" {
+		return "**All**"
+	}
+
+	return statistics.Function
+}
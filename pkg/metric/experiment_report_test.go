@@ -0,0 +1,107 @@
+package metric
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/vhive-serverless/loader/pkg/common"
+)
+
+func TestExperimentReporter(t *testing.T) {
+	reporter := NewExperimentReporter()
+
+	for i := 1; i <= 10; i++ {
+		reporter.Add(&ExecutionRecord{
+			ExecutionRecordBase: ExecutionRecordBase{
+				Phase:             int(common.ExecutionPhase),
+				RequestedDuration: 1000,
+				ResponseTime:      int64(i) * 1000,
+			},
+			StartType: Hot,
+			Function:  "f",
+		})
+	}
+	reporter.Add(&ExecutionRecord{
+		ExecutionRecordBase: ExecutionRecordBase{Phase: int(common.ExecutionPhase), RequestedDuration: 1000, ResponseTime: 4000},
+		StartType:           Cold,
+		Function:            "g",
+	})
+	reporter.Add(&ExecutionRecord{
+		ExecutionRecordBase: ExecutionRecordBase{Phase: int(common.ExecutionPhase), ConnectionTimeout: true},
+		Function:            "g",
+	})
+	reporter.Add(&ExecutionRecord{
+		ExecutionRecordBase:     ExecutionRecordBase{Phase: int(common.ExecutionPhase), FunctionTimeout: true},
+		MemoryAllocationTimeout: true,
+		Function:                "g",
+	})
+	// excluded from the statistics
+	reporter.Add(&ExecutionRecord{
+		ExecutionRecordBase: ExecutionRecordBase{Phase: int(common.WarmupPhase), ResponseTime: 1e9},
+		StartType:           Cold,
+		Function:            "f",
+	})
+
+	report := reporter.Report(map[string]int{"f": 10, "g": 4, "h": 2})
+	if report.WarmupInvocations != 1 || len(report.Functions) != 3 {
+		t.Fatalf("Unexpected report %+v", report)
+	}
+
+	overall := report.Overall
+	if overall.Requested != 16 || overall.Issued != 13 || overall.Successful != 11 || overall.Failed != 2 {
+		t.Errorf("Unexpected overall invocations %+v", overall)
+	}
+	if overall.Errors != (ErrorBreakdown{ConnectionTimeout: 1, FunctionTimeout: 1, MemoryAllocationTimeout: 1}) {
+		t.Errorf("Unexpected error breakdown %+v", overall.Errors)
+	}
+	if overall.ColdStartFraction != 1.0/11 || overall.ResponseTime.P999 != 10000 {
+		t.Errorf("Unexpected overall statistics %+v", overall)
+	}
+
+	f := report.Functions[0]
+	if f.Function != "f" || f.IssuedAccuracy != 1 || f.ColdStartFraction != 0 {
+		t.Errorf("Unexpected statistics of f %+v", f)
+	}
+	if f.ResponseTime != (LatencyStatistics{P50: 5000, P90: 9000, P99: 10000, P999: 10000}) {
+		t.Errorf("Unexpected response time of f %+v", f.ResponseTime)
+	}
+	if f.Slowdown.Mean != 5.5 || f.Slowdown.P50 != 5 || f.Slowdown.P999 != 10 {
+		t.Errorf("Unexpected slowdown of f %+v", f.Slowdown)
+	}
+
+	g := report.Functions[1]
+	if g.IssuedAccuracy != 0.75 || g.ColdStartFraction != 1 || g.Slowdown.P50 != 4 || g.Errors.ConnectionTimeout != 1 {
+		t.Errorf("Unexpected statistics of g %+v", g)
+	}
+
+	if h := report.Functions[2]; h.Function != "h" || h.Requested != 2 || h.Issued != 0 || h.IssuedAccuracy != 0 {
+		t.Errorf("Functions never invoked should be reported, got %+v", h)
+	}
+}
+
+func TestWriteExperimentReport(t *testing.T) {
+	reporter := NewExperimentReporter()
+	reporter.Add(&ExecutionRecord{ExecutionRecordBase: ExecutionRecordBase{RequestedDuration: 1000, ResponseTime: 2500}, Function: "f"})
+	report := reporter.Report(map[string]int{"f": 1})
+
+	directory := t.TempDir()
+	jsonPath, markdownPath := filepath.Join(directory, "report.json"), filepath.Join(directory, "report.md")
+	if err := WriteExperimentReport(report, jsonPath, markdownPath); err != nil {
+		t.Fatal(err)
+	}
+
+	data, _ := os.ReadFile(jsonPath)
+	var read ExperimentReport
+	if err := json.Unmarshal(data, &read); err != nil || read.Overall != report.Overall {
+		t.Errorf("Unexpected JSON report %s - %v", data, err)
+	}
+
+	markdown, _ := os.ReadFile(markdownPath)
+	if !strings.Contains(string(markdown), "| f | 1 | 1 | 100.00% | 1 | 0 | 0 | 0 | 0 | 0.00% |") ||
+		!strings.Contains(string(markdown), "| f | 2.50 | 2.50 | 2.50 | 2.50 | 2.50 |") {
+		t.Errorf("Unexpected Markdown report:\n%s", markdown)
+	}
+}
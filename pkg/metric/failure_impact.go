@@ -0,0 +1,224 @@
+package metric
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/gocarina/gocsv"
+)
+
+const (
+	// invocations started within this period before a failure form its baseline
+	failureBaselinePeriod = time.Minute
+	// a window is degraded if its success rate or p99 response time is worse than the baseline by more than these
+	successRateTolerance = 0.01
+	p99Tolerance         = 0.1
+)
+
+// FailureImpact quantifies the impact of an injected failure on the invocations. Times are in µs; TimeToFirstError
+// and TimeToRecovery are measured since the start of the failure and are -1 if no invocation failed or if the
+// invocations did not recover before the next failure or the end of the experiment.
+type FailureImpact struct {
+	Event      int    `csv:"event" json:"event"`
+	Occurrence int    `csv:"occurrence" json:"occurrence"`
+	Component  string `csv:"component" json:"component"`
+	Target     string `csv:"target" json:"target"`
+	StartTime  int64  `csv:"startTime" json:"startTime"`
+	EndTime    int64  `csv:"endTime" json:"endTime"`
+
+	BaselineSuccessRate     float64 `csv:"baselineSuccessRate" json:"baselineSuccessRate"`
+	BaselineP99ResponseTime int64   `csv:"baselineP99ResponseTime" json:"baselineP99ResponseTime"`
+	MinSuccessRate          float64 `csv:"minSuccessRate" json:"minSuccessRate"`
+	AvailabilityDip         float64 `csv:"availabilityDip" json:"availabilityDip"`
+
+	TimeToFirstError int64 `csv:"timeToFirstError" json:"timeToFirstError"`
+	TimeToRecovery   int64 `csv:"timeToRecovery" json:"timeToRecovery"`
+	// invocations that failed or exceeded the baseline p99 response time until recovery
+	AffectedInvocations int `csv:"affectedInvocations" json:"affectedInvocations"`
+}
+
+// FunctionFailureImpact is the impact of a failure on the invocations of a function, until recovery.
+type FunctionFailureImpact struct {
+	Event               int    `csv:"event" json:"event"`
+	Occurrence          int    `csv:"occurrence" json:"occurrence"`
+	Function            string `csv:"function" json:"function"`
+	Invocations         int    `csv:"invocations" json:"invocations"`
+	FailedInvocations   int    `csv:"failedInvocations" json:"failedInvocations"`
+	AffectedInvocations int    `csv:"affectedInvocations" json:"affectedInvocations"`
+}
+
+type FailureImpactReport struct {
+	Failures  []FailureImpact         `json:"failures"`
+	Functions []FunctionFailureImpact `json:"functions"`
+}
+
+// AnalyzeFailureImpact compares the invocations following each failure to those preceding it. The invocations are
+// split into windows of the given length, starting with the failure and ending with the next failure or the end of
+// the experiment, and the failure is recovered from at the end of the last degraded window.
+func AnalyzeFailureImpact(records []*ExecutionRecord, failures []*FailureRecord, window time.Duration) (*FailureImpactReport, error) {
+	if window <= 0 {
+		return nil, fmt.Errorf("invalid failure impact window %v", window)
+	}
+
+	records = slices.Clone(records)
+	slices.SortFunc(records, func(a, b *ExecutionRecord) int {
+		return int(a.StartTime - b.StartTime)
+	})
+	failures = slices.Clone(failures)
+	slices.SortStableFunc(failures, func(a, b *FailureRecord) int {
+		return int(a.StartTime - b.StartTime)
+	})
+
+	report := &FailureImpactReport{}
+	if len(records) == 0 {
+		return report, nil
+	}
+
+	runEnd := records[len(records)-1].StartTime + 1
+	_, runP99 := successStatistics(records)
+
+	for i, failure := range failures {
+		horizonEnd := runEnd
+		if i+1 < len(failures) {
+			horizonEnd = min(horizonEnd, failures[i+1].StartTime)
+		}
+
+		impact := FailureImpact{
+			Event:            failure.Event,
+			Occurrence:       failure.Occurrence,
+			Component:        failure.Component,
+			Target:           failure.Target,
+			StartTime:        failure.StartTime,
+			EndTime:          failure.EndTime,
+			TimeToFirstError: -1,
+		}
+
+		baseline := recordsBetween(records, failure.StartTime-failureBaselinePeriod.Microseconds(), failure.StartTime)
+		impact.BaselineSuccessRate, impact.BaselineP99ResponseTime = successStatistics(baseline)
+		if len(baseline) == 0 {
+			// failures at the beginning of the experiment are compared to the run as a whole
+			impact.BaselineSuccessRate, impact.BaselineP99ResponseTime = 1, runP99
+		}
+		p99Threshold := int64(float64(impact.BaselineP99ResponseTime) * (1 + p99Tolerance))
+
+		// the last non-empty window decides whether the invocations recovered before the horizon
+		impact.MinSuccessRate = impact.BaselineSuccessRate
+		recoveredAt, lastDegraded := failure.StartTime, false
+		for start := failure.StartTime; start < horizonEnd; start += window.Microseconds() {
+			end := min(start+window.Microseconds(), horizonEnd)
+			windowRecords := recordsBetween(records, start, end)
+			if len(windowRecords) == 0 {
+				continue
+			}
+
+			successRate, p99 := successStatistics(windowRecords)
+			impact.MinSuccessRate = min(impact.MinSuccessRate, successRate)
+
+			lastDegraded = successRate < impact.BaselineSuccessRate-successRateTolerance ||
+				(impact.BaselineP99ResponseTime > 0 && p99 > p99Threshold)
+			if lastDegraded {
+				recoveredAt = end
+			}
+		}
+		impact.AvailabilityDip = max(0, impact.BaselineSuccessRate-impact.MinSuccessRate)
+
+		impactEnd := recoveredAt
+		impact.TimeToRecovery = recoveredAt - failure.StartTime
+		if lastDegraded {
+			impactEnd = horizonEnd
+			impact.TimeToRecovery = -1
+		}
+
+		functions := make(map[string]*FunctionFailureImpact)
+		for _, record := range recordsBetween(records, failure.StartTime, horizonEnd) {
+			if failed(record) && impact.TimeToFirstError < 0 {
+				impact.TimeToFirstError = record.StartTime - failure.StartTime
+			}
+			if record.StartTime >= impactEnd {
+				continue
+			}
+
+			function, ok := functions[record.Function]
+			if !ok {
+				function = &FunctionFailureImpact{Event: failure.Event, Occurrence: failure.Occurrence, Function: record.Function}
+				functions[record.Function] = function
+			}
+
+			function.Invocations++
+			if failed(record) {
+				function.FailedInvocations++
+			}
+			if failed(record) || (impact.BaselineP99ResponseTime > 0 && record.ResponseTime > p99Threshold) {
+				function.AffectedInvocations++
+				impact.AffectedInvocations++
+			}
+		}
+
+		report.Failures = append(report.Failures, impact)
+		for _, function := range functions {
+			report.Functions = append(report.Functions, *function)
+		}
+		slices.SortFunc(report.Functions[len(report.Functions)-len(functions):], func(a, b FunctionFailureImpact) int {
+			return strings.Compare(a.Function, b.Function)
+		})
+	}
+
+	return report, nil
+}
+
+// WriteFailureImpactReport writes the full report as JSON and the impact of each failure as CSV.
+func WriteFailureImpactReport(report *FailureImpactReport, jsonPath string, csvPath string) error {
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err = os.WriteFile(jsonPath, data, 0644); err != nil {
+		return err
+	}
+
+	file, err := os.Create(csvPath)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	return gocsv.MarshalFile(&report.Failures, file)
+}
+
+func failed(record *ExecutionRecord) bool {
+	return record.ConnectionTimeout || record.FunctionTimeout
+}
+
+// recordsBetween returns the records started in [start, end), given records sorted by start time.
+func recordsBetween(records []*ExecutionRecord, start int64, end int64) []*ExecutionRecord {
+	from, _ := slices.BinarySearchFunc(records, start, func(record *ExecutionRecord, t int64) int {
+		return int(record.StartTime - t)
+	})
+	to, _ := slices.BinarySearchFunc(records, end, func(record *ExecutionRecord, t int64) int {
+		return int(record.StartTime - t)
+	})
+
+	return records[from:to]
+}
+
+// successStatistics returns the ratio of successful invocations and their p99 response time.
+func successStatistics(records []*ExecutionRecord) (float64, int64) {
+	if len(records) == 0 {
+		return 0, 0
+	}
+
+	var responseTimes []int64
+	for _, record := range records {
+		if !failed(record) {
+			responseTimes = append(responseTimes, record.ResponseTime)
+		}
+	}
+
+	successRate := float64(len(responseTimes)) / float64(len(records))
+	slices.Sort(responseTimes)
+	return successRate, percentile(responseTimes, 0.99)
+}
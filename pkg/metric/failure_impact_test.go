@@ -0,0 +1,81 @@
+package metric
+
+import (
+	"testing"
+	"time"
+)
+
+func TestAnalyzeFailureImpact(t *testing.T) {
+	var records []*ExecutionRecord
+	for second := int64(0); second < 180; second++ {
+		for _, function := range []string{"a", "b"} {
+			record := &ExecutionRecord{
+				ExecutionRecordBase: ExecutionRecordBase{StartTime: second * 1e6, ResponseTime: 1000},
+				Function:            function,
+			}
+
+			switch {
+			// a fails shortly after the first failure and until the end after the second one
+			case function == "a" && ((second >= 62 && second < 85) || second >= 150):
+				record.ConnectionTimeout = true
+			// b slows down during the first failure
+			case function == "b" && second >= 60 && second < 70:
+				record.ResponseTime = 5000
+			}
+
+			records = append(records, record)
+		}
+	}
+
+	failures := []*FailureRecord{
+		{Event: 1, Component: "activator", StartTime: 150e6, EndTime: 150e6},
+		{Event: 0, Component: "worker_node", StartTime: 60e6, EndTime: 70e6},
+	}
+
+	report, err := AnalyzeFailureImpact(records, failures, 10*time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(report.Failures) != 2 {
+		t.Fatalf("Expected 2 failure impacts, got %d", len(report.Failures))
+	}
+
+	first := report.Failures[0]
+	if first.Event != 0 || first.BaselineSuccessRate != 1 || first.BaselineP99ResponseTime != 1000 {
+		t.Errorf("Unexpected baseline %+v", first)
+	}
+	if first.MinSuccessRate != 0.5 || first.AvailabilityDip != 0.5 {
+		t.Errorf("Expected an availability dip of 0.5, got %+v", first)
+	}
+	if first.TimeToFirstError != 2e6 || first.TimeToRecovery != 30e6 || first.AffectedInvocations != 33 {
+		t.Errorf("Unexpected impact %+v", first)
+	}
+
+	second := report.Failures[1]
+	if second.TimeToFirstError != 0 || second.TimeToRecovery != -1 {
+		t.Errorf("Failure should not be recovered from before the end, got %+v", second)
+	}
+
+	expected := []FunctionFailureImpact{
+		{Event: 0, Function: "a", Invocations: 30, FailedInvocations: 23, AffectedInvocations: 23},
+		{Event: 0, Function: "b", Invocations: 30, FailedInvocations: 0, AffectedInvocations: 10},
+		{Event: 1, Function: "a", Invocations: 30, FailedInvocations: 30, AffectedInvocations: 30},
+		{Event: 1, Function: "b", Invocations: 30, FailedInvocations: 0, AffectedInvocations: 0},
+	}
+	if len(report.Functions) != len(expected) {
+		t.Fatalf("Expected %d function impacts, got %+v", len(expected), report.Functions)
+	}
+	for i, function := range report.Functions {
+		if function != expected[i] {
+			t.Errorf("Expected %+v, got %+v", expected[i], function)
+		}
+	}
+
+	if empty, err := AnalyzeFailureImpact(nil, failures, 10*time.Second); err != nil || len(empty.Failures) != 0 {
+		t.Error("No impact should be reported without invocations")
+	}
+
+	if _, err = AnalyzeFailureImpact(records, failures, 0); err == nil {
+		t.Error("Expected an error for an empty window")
+	}
+}
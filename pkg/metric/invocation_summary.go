@@ -0,0 +1,262 @@
+package metric
+
+import (
+	"encoding/csv"
+	"math"
+	"os"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/gocarina/gocsv"
+	"github.com/vhive-serverless/loader/pkg/common"
+)
+
+// targeted and issued invocations of a bucket of the trace
+type traceBucket struct {
+	targeted          atomic.Int64
+	functionsTargeted atomic.Int64
+	issued            atomic.Int64
+	functionsInvoked  atomic.Int64
+}
+
+// invocations completed during a bucket
+type completedBucket struct {
+	successful    int
+	failed        int
+	coldStarts    int
+	responseTimes []int64
+}
+
+// InvocationSummarizer writes a MinuteInvocationRecord per bucket of the trace granularity while the experiment
+// runs, so that the summary of the buckets elapsed survives a crash of the loader. The buckets completing
+// invocations after the end of the trace, while the last invocations are awaited, are summarized as well.
+type InvocationSummarizer struct {
+	interval time.Duration
+	warmup   int
+	buckets  []traceBucket
+
+	mutex     sync.Mutex
+	start     time.Time
+	completed map[int]*completedBucket
+	written   int
+
+	file   *os.File
+	writer *gocsv.SafeCSVWriter
+	err    error
+
+	stop chan struct{}
+	done chan struct{}
+}
+
+// NewInvocationSummarizer creates the CSV file at path for a trace of the given number of buckets, the first warmup
+// of which belong to the warmup phase.
+func NewInvocationSummarizer(path string, buckets int, interval time.Duration, warmup int) (*InvocationSummarizer, error) {
+	file, err := os.Create(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return &InvocationSummarizer{
+		interval:  interval,
+		warmup:    warmup,
+		buckets:   make([]traceBucket, buckets),
+		completed: make(map[int]*completedBucket),
+		file:      file,
+		writer:    gocsv.NewSafeCSVWriter(csv.NewWriter(file)),
+		stop:      make(chan struct{}),
+		done:      make(chan struct{}),
+	}, nil
+}
+
+// Target adds the invocations of a function per bucket to the targeted invocations.
+func (s *InvocationSummarizer) Target(perBucketCount []int) {
+	for i, count := range perBucketCount {
+		if i >= len(s.buckets) || count == 0 {
+			continue
+		}
+
+		s.buckets[i].targeted.Add(int64(count))
+		s.buckets[i].functionsTargeted.Add(1)
+	}
+}
+
+// Issued counts an invocation issued in the bucket, which is the first of its function in the bucket if first.
+func (s *InvocationSummarizer) Issued(bucket int, first bool) {
+	if bucket >= len(s.buckets) {
+		return
+	}
+
+	s.buckets[bucket].issued.Add(1)
+	if first {
+		s.buckets[bucket].functionsInvoked.Add(1)
+	}
+}
+
+// Start starts the first bucket and writes the summary of each bucket once it elapses.
+func (s *InvocationSummarizer) Start() {
+	s.mutex.Lock()
+	s.start = time.Now()
+	s.mutex.Unlock()
+
+	go func() {
+		defer close(s.done)
+
+		ticker := time.NewTicker(s.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				s.writeUntil(s.currentBucket())
+			case <-s.stop:
+				return
+			}
+		}
+	}()
+}
+
+// Add counts the record as completed in the current bucket.
+func (s *InvocationSummarizer) Add(record *ExecutionRecord) {
+	s.add(record, s.currentBucket())
+}
+
+func (s *InvocationSummarizer) currentBucket() int {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	return int(time.Since(s.start) / s.interval)
+}
+
+func (s *InvocationSummarizer) add(record *ExecutionRecord, bucket int) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	// records collected as their bucket is written are counted in the next one
+	bucket = max(bucket, s.written)
+
+	completed, ok := s.completed[bucket]
+	if !ok {
+		completed = &completedBucket{}
+		s.completed[bucket] = completed
+	}
+
+	if failed(record) {
+		completed.failed++
+	} else {
+		completed.successful++
+		completed.responseTimes = append(completed.responseTimes, record.ResponseTime)
+	}
+	if record.StartType == Cold {
+		completed.coldStarts++
+	}
+}
+
+// writeUntil writes the summary of the buckets preceding the given one which have not been written yet.
+func (s *InvocationSummarizer) writeUntil(bucket int) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	var records []*MinuteInvocationRecord
+	for ; s.written < bucket; s.written++ {
+		records = append(records, s.summarize(s.written, s.interval))
+	}
+
+	s.write(records)
+}
+
+func (s *InvocationSummarizer) summarize(bucket int, duration time.Duration) *MinuteInvocationRecord {
+	record := &MinuteInvocationRecord{
+		Phase:     int(common.ExecutionPhase),
+		MinuteIdx: bucket,
+		Duration:  duration.Microseconds(),
+	}
+	if bucket < s.warmup {
+		record.Phase = int(common.WarmupPhase)
+	}
+
+	if bucket < len(s.buckets) {
+		record.Targeted = int(s.buckets[bucket].targeted.Load())
+		record.NumFuncTargeted = int(s.buckets[bucket].functionsTargeted.Load())
+		record.Issued = int(s.buckets[bucket].issued.Load())
+		record.NumFuncInvoked = int(s.buckets[bucket].functionsInvoked.Load())
+		record.Rps = int(float64(record.Issued) / s.interval.Seconds())
+	}
+
+	if completed, ok := s.completed[bucket]; ok {
+		record.Successful = completed.successful
+		record.Failed = completed.failed
+		record.Completed = completed.successful + completed.failed
+		record.NumColdStarts = completed.coldStarts
+
+		slices.Sort(completed.responseTimes)
+		record.P50ResponseTime = percentile(completed.responseTimes, 0.5)
+		record.P90ResponseTime = percentile(completed.responseTimes, 0.9)
+		record.P99ResponseTime = percentile(completed.responseTimes, 0.99)
+
+		delete(s.completed, bucket)
+	}
+
+	return record
+}
+
+// write appends the records to the file, with the header before the first one, and flushes them.
+func (s *InvocationSummarizer) write(records []*MinuteInvocationRecord) {
+	if len(records) == 0 || s.err != nil {
+		return
+	}
+
+	if s.written == len(records) {
+		s.err = gocsv.MarshalCSV(records, s.writer)
+	} else {
+		s.err = gocsv.MarshalCSVWithoutHeaders(records, s.writer)
+	}
+}
+
+// Close writes the summary of the remaining buckets, including the current one up to now, and closes the file.
+// Records must not be added once Close is called.
+func (s *InvocationSummarizer) Close() error {
+	close(s.stop)
+	<-s.done
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	// the trace buckets are all written, even if the experiment ended earlier
+	elapsed := time.Since(s.start)
+	current := int(elapsed / s.interval)
+
+	last := max(current, len(s.buckets)-1)
+	for bucket := range s.completed {
+		last = max(last, bucket)
+	}
+
+	var records []*MinuteInvocationRecord
+	for ; s.written <= last; s.written++ {
+		duration := s.interval
+		if s.written == current {
+			duration = elapsed - time.Duration(current)*s.interval
+		} else if s.written > current {
+			duration = 0
+		}
+
+		records = append(records, s.summarize(s.written, duration))
+	}
+	s.write(records)
+
+	if err := s.file.Close(); s.err == nil {
+		s.err = err
+	}
+
+	return s.err
+}
+
+// percentile returns the nearest-rank percentile of the sorted values, or 0 if there are none.
+func percentile[T int64 | float64](sorted []T, p float64) T {
+	if len(sorted) == 0 {
+		return 0
+	}
+
+	return sorted[max(int(math.Ceil(p*float64(len(sorted))))-1, 0)]
+}
@@ -0,0 +1,109 @@
+package metric
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/gocarina/gocsv"
+	"github.com/vhive-serverless/loader/pkg/common"
+)
+
+func readInvocationSummary(t *testing.T, path string) []MinuteInvocationRecord {
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	var records []MinuteInvocationRecord
+	if err = gocsv.UnmarshalFile(file, &records); err != nil {
+		t.Fatal(err)
+	}
+
+	return records
+}
+
+func TestInvocationSummarizer(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "invocation_summary.csv")
+	summarizer, err := NewInvocationSummarizer(path, 3, time.Minute, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	summarizer.Start()
+
+	summarizer.Target([]int{2, 0, 3})
+	summarizer.Target([]int{1, 1, 0})
+	summarizer.Issued(0, true)
+	summarizer.Issued(0, false)
+	summarizer.Issued(0, true)
+	summarizer.Issued(2, true)
+
+	for _, responseTime := range []int64{30, 10, 20} {
+		summarizer.add(&ExecutionRecord{ExecutionRecordBase: ExecutionRecordBase{ResponseTime: responseTime}, StartType: Cold}, 0)
+	}
+	summarizer.add(&ExecutionRecord{ExecutionRecordBase: ExecutionRecordBase{ResponseTime: 900, FunctionTimeout: true}}, 0)
+
+	// the summary of the elapsed buckets is written before the end of the experiment
+	summarizer.writeUntil(1)
+	records := readInvocationSummary(t, path)
+	expected := MinuteInvocationRecord{
+		Phase:           int(common.WarmupPhase),
+		Duration:        time.Minute.Microseconds(),
+		NumFuncTargeted: 2,
+		NumFuncInvoked:  2,
+		NumColdStarts:   3,
+		Targeted:        3,
+		Issued:          3,
+		Completed:       4,
+		Successful:      3,
+		Failed:          1,
+		P50ResponseTime: 20,
+		P90ResponseTime: 30,
+		P99ResponseTime: 30,
+	}
+	if len(records) != 1 || records[0] != expected {
+		t.Fatalf("Unexpected summary of the first bucket %+v", records)
+	}
+
+	// records collected as a bucket is written belong to the next one, and those of the buckets following the
+	// trace are summarized as well
+	summarizer.add(&ExecutionRecord{ExecutionRecordBase: ExecutionRecordBase{ResponseTime: 40}}, 0)
+	summarizer.add(&ExecutionRecord{ExecutionRecordBase: ExecutionRecordBase{ResponseTime: 50}}, 3)
+	if err = summarizer.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	records = readInvocationSummary(t, path)
+	if len(records) != 4 {
+		t.Fatalf("Expected 4 buckets, got %d", len(records))
+	}
+	for i, record := range records[1:] {
+		if record.MinuteIdx != i+1 || record.Phase != int(common.ExecutionPhase) {
+			t.Errorf("Unexpected bucket %+v", record)
+		}
+	}
+	if records[1].Completed != 1 || records[1].P50ResponseTime != 40 || records[1].NumFuncTargeted != 1 {
+		t.Errorf("Unexpected summary of the second bucket %+v", records[1])
+	}
+	if records[2].Targeted != 3 || records[2].Issued != 1 || records[2].Rps != 0 || records[2].Completed != 0 {
+		t.Errorf("Unexpected summary of the third bucket %+v", records[2])
+	}
+	if records[3].Targeted != 0 || records[3].Completed != 1 || records[3].P99ResponseTime != 50 {
+		t.Errorf("Unexpected summary of the bucket following the trace %+v", records[3])
+	}
+}
+
+func TestPercentile(t *testing.T) {
+	values := []int64{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}
+
+	for p, expected := range map[float64]int64{0: 1, 0.5: 5, 0.9: 9, 0.99: 10, 1: 10} {
+		if value := percentile(values, p); value != expected {
+			t.Errorf("Expected p%v of %d, got %d", p*100, expected, value)
+		}
+	}
+	if percentile([]int64{}, 0.5) != 0 {
+		t.Error("Expected 0 without values")
+	}
+}
@@ -0,0 +1,111 @@
+package metric
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+
+	"github.com/parquet-go/parquet-go"
+	"github.com/parquet-go/parquet-go/compress"
+	"github.com/parquet-go/parquet-go/compress/gzip"
+	"github.com/parquet-go/parquet-go/compress/uncompressed"
+	"github.com/parquet-go/parquet-go/compress/zstd"
+	"github.com/vhive-serverless/loader/pkg/common"
+)
+
+// number of records buffered in memory before being written as a row group
+const parquetRowGroupSize = 1 << 16
+
+// parquetSink writes the columns of the records as required Parquet columns, in the order of the CSV header. The
+// schema is derived from the first record, so the writer is created on the first write.
+type parquetSink struct {
+	file  *os.File
+	codec compress.Codec
+
+	columns []recordColumn
+	// struct with a field per column, tagged with the column name, from which the writer deduces the schema
+	rowType reflect.Type
+	writer  *parquet.Writer
+}
+
+func newParquetSink(path string, compression string) (*parquetSink, error) {
+	var codec compress.Codec
+
+	switch compression {
+	case common.OutputCompressionGzip:
+		codec = &gzip.Codec{}
+	case common.OutputCompressionZstd:
+		codec = &zstd.Codec{}
+	case "":
+		codec = &uncompressed.Codec{}
+	default:
+		return nil, fmt.Errorf("unsupported output compression %s", compression)
+	}
+
+	file, err := os.Create(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return &parquetSink{file: file, codec: codec}, nil
+}
+
+func (s *parquetSink) Write(record any) error {
+	if s.writer == nil {
+		s.columns = recordColumns(reflect.TypeOf(record))
+		s.rowType = parquetRowType(s.columns)
+		s.newWriter(parquet.SchemaOf(reflect.New(s.rowType).Interface()))
+	}
+
+	value := recordValue(record)
+	row := reflect.New(s.rowType)
+	for i := range s.columns {
+		row.Elem().Field(i).Set(reflect.ValueOf(s.columns[i].value(value)))
+	}
+
+	return s.writer.Write(row.Interface())
+}
+
+func (s *parquetSink) newWriter(schema *parquet.Schema) {
+	s.writer = parquet.NewWriter(s.file, schema,
+		parquet.Compression(s.codec),
+		parquet.MaxRowsPerRowGroup(parquetRowGroupSize),
+		parquet.CreatedBy("vhive-serverless loader", "", ""),
+	)
+}
+
+func (s *parquetSink) Close() error {
+	if s.writer == nil {
+		// files without records have no columns
+		s.newWriter(parquet.NewSchema("schema", parquet.Group{}))
+	}
+
+	return errors.Join(s.writer.Close(), s.file.Close())
+}
+
+// parquetRowType returns a struct type with a field per column, as the value the column holds.
+func parquetRowType(columns []recordColumn) reflect.Type {
+	fields := make([]reflect.StructField, len(columns))
+	for i, column := range columns {
+		var fieldType reflect.Type
+		switch column.kind {
+		case integerColumn:
+			fieldType = reflect.TypeFor[int64]()
+		case floatColumn:
+			fieldType = reflect.TypeFor[float64]()
+		case booleanColumn:
+			fieldType = reflect.TypeFor[bool]()
+		default:
+			fieldType = reflect.TypeFor[string]()
+		}
+
+		fields[i] = reflect.StructField{
+			Name: fmt.Sprintf("Column%d", i),
+			Type: fieldType,
+			Tag:  reflect.StructTag(fmt.Sprintf(`parquet:"%s"`, column.name)),
+		}
+	}
+
+	return reflect.StructOf(fields)
+}
@@ -31,56 +31,92 @@ const (
 	Cold StartType = "cold"
 )
 
+// MinuteInvocationRecord summarizes the invocations of a bucket of the trace granularity. Invocations are targeted
+// and issued in the bucket of the trace they belong to, and completed in the bucket during which their record is
+// collected.
 type MinuteInvocationRecord struct {
-	Phase           int   `csv:"phase"`
-	Rps             int   `csv:"rps"`
-	MinuteIdx       int   `csv:"index"`
-	Duration        int64 `csv:"duration"`
-	NumFuncTargeted int   `csv:"num_func_target"`
-	NumFuncInvoked  int   `csv:"num_func_invoked"`
-	NumColdStarts   int   `csv:"num_coldstarts"`
+	Phase     int `csv:"phase" json:"phase"`
+	Rps       int `csv:"rps" json:"rps"`
+	MinuteIdx int `csv:"index" json:"index"`
+	// Measurements in microseconds
+	Duration        int64 `csv:"duration" json:"duration"`
+	NumFuncTargeted int   `csv:"num_func_target" json:"num_func_target"`
+	NumFuncInvoked  int   `csv:"num_func_invoked" json:"num_func_invoked"`
+	NumColdStarts   int   `csv:"num_coldstarts" json:"num_coldstarts"`
+
+	Targeted   int `csv:"targeted" json:"targeted"`
+	Issued     int `csv:"issued" json:"issued"`
+	Completed  int `csv:"completed" json:"completed"`
+	Successful int `csv:"successful" json:"successful"`
+	Failed     int `csv:"failed" json:"failed"`
+
+	// Response time of the successful invocations in microseconds
+	P50ResponseTime int64 `csv:"p50_response_time" json:"p50_response_time"`
+	P90ResponseTime int64 `csv:"p90_response_time" json:"p90_response_time"`
+	P99ResponseTime int64 `csv:"p99_response_time" json:"p99_response_time"`
 }
 
 type ExecutionRecordBase struct {
-	Phase        int    `csv:"phase"`
-	Instance     string `csv:"instance"`
-	InvocationID string `csv:"invocationID"`
-	StartTime    int64  `csv:"startTime"`
+	Phase        int    `csv:"phase" json:"phase"`
+	Instance     string `csv:"instance" json:"instance"`
+	InvocationID string `csv:"invocationID" json:"invocationID"`
+	StartTime    int64  `csv:"startTime" json:"startTime"`
 
 	// Measurements in microseconds
-	RequestedDuration           uint32 `csv:"requestedDuration"`
-	GRPCConnectionEstablishTime int64  `csv:"grpcConnEstablish"`
-	ResponseTime                int64  `csv:"responseTime"`
-	ActualDuration              uint32 `csv:"actualDuration"`
+	RequestedDuration           uint32 `csv:"requestedDuration" json:"requestedDuration"`
+	GRPCConnectionEstablishTime int64  `csv:"grpcConnEstablish" json:"grpcConnEstablish"`
+	ResponseTime                int64  `csv:"responseTime" json:"responseTime"`
+	ActualDuration              uint32 `csv:"actualDuration" json:"actualDuration"`
 
-	ConnectionTimeout bool `csv:"connectionTimeout"`
-	FunctionTimeout   bool `csv:"functionTimeout"`
+	ConnectionTimeout bool `csv:"connectionTimeout" json:"connectionTimeout"`
+	FunctionTimeout   bool `csv:"functionTimeout" json:"functionTimeout"`
 }
 
-type ExecutionRecordOpenWhisk struct {
+type ExecutionRecord struct {
 	ExecutionRecordBase
 
-	ActivationID   string    `csv:"activationID"`
-	StartType      StartType `csv:"startType"`
-	HttpStatusCode int       `csv:"httpStatusCode"`
+	// Measurements in microseconds
+	ActualMemoryUsage       uint32 `csv:"actualMemoryUsage" json:"actualMemoryUsage"`
+	MemoryAllocationTimeout bool   `csv:"memoryAllocationTimeout" json:"memoryAllocationTimeout"`
+
+	AsyncResponseID     string `csv:"-" json:"-"`
+	TimeToSubmitMs      int64  `csv:"timeToSubmitMs" json:"timeToSubmitMs"`
+	UserCodeExecutionMs int64  `csv:"userCodeExecutionMs" json:"userCodeExecutionMs"`
 
+	TimeToGetResponseMs int64 `csv:"timeToGetResponseMs" json:"timeToGetResponseMs"`
+
+	// Timestamps reported by the function instance (µs since epoch)
+	FunctionReceiveTime int64 `csv:"functionReceiveTime" json:"functionReceiveTime"`
+	FunctionStartTime   int64 `csv:"functionStartTime" json:"functionStartTime"`
+	FunctionEndTime     int64 `csv:"functionEndTime" json:"functionEndTime"`
 	// Measurements in microseconds
-	WaitTime int64 `csv:"waitTime"`
-	InitTime int64 `csv:"initTime"`
-}
+	InstanceUptime int64 `csv:"instanceUptime" json:"instanceUptime"`
+	// Random ID drawn by the function instance on startup
+	InstanceID string `csv:"instanceID" json:"instanceID"`
 
-type ExecutionRecord struct {
-	ExecutionRecordBase
+	// Derived from the function-reported timestamps. QueueingTime is the part of the response time spent
+	// outside the function instance (i.e., routing, queueing and network) and does not rely on synchronized clocks.
+	QueueingTime int64     `csv:"queueingTime" json:"queueingTime"`
+	StartType    StartType `csv:"startType" json:"startType"`
+
+	// Reported by the platform gateway (OpenFaaS)
+	CallID string `csv:"callID" json:"callID"`
+	// Measurements in microseconds
+	GatewayDuration int64 `csv:"gatewayDuration" json:"gatewayDuration"`
 
+	// Read from the activation record (OpenWhisk) or the invocation log tail (AWS Lambda)
+	ActivationID string `csv:"activationID" json:"activationID"`
 	// Measurements in microseconds
-	ActualMemoryUsage       uint32 `csv:"actualMemoryUsage"`
-	MemoryAllocationTimeout bool   `csv:"memoryAllocationTimeout"`
+	WaitTime int64 `csv:"waitTime" json:"waitTime"`
+	InitTime int64 `csv:"initTime" json:"initTime"`
+	// Status code of the response creating the activation (OpenWhisk)
+	HttpStatusCode int `csv:"httpStatusCode" json:"httpStatusCode"`
 
-	AsyncResponseID     string `csv:"-"`
-	TimeToSubmitMs      int64  `csv:"timeToSubmitMs"`
-	UserCodeExecutionMs int64  `csv:"userCodeExecutionMs"`
+	// Status code of the failed gRPC invocations (dynamic gRPC)
+	GRPCStatusCode string `csv:"grpcStatusCode" json:"grpcStatusCode"`
 
-	TimeToGetResponseMs int64 `csv:"timeToGetResponseMs"`
+	// Name of the invoked function
+	Function string `csv:"function" json:"function"`
 }
 
 type DeploymentScale struct {
@@ -154,3 +190,19 @@ type TValues struct {
 	Pct5  float64 `json:"5%"`
 	Pct10 float64 `json:"10%"`
 }
+
+// FailureRecord is a failure injected into the cluster during the experiment.
+type FailureRecord struct {
+	Event      int    `csv:"event" json:"event"`
+	Occurrence int    `csv:"occurrence" json:"occurrence"`
+	Component  string `csv:"component" json:"component"`
+	Target     string `csv:"target" json:"target"`
+	Nodes      string `csv:"nodes" json:"nodes"`
+	Recovery   string `csv:"recovery" json:"recovery"`
+
+	// µs since epoch; the failure ends once recovered, or once injected for instantaneous failures
+	StartTime int64 `csv:"startTime" json:"startTime"`
+	EndTime   int64 `csv:"endTime" json:"endTime"`
+
+	Error string `csv:"error" json:"error"`
+}
@@ -0,0 +1,141 @@
+package metric
+
+import (
+	"errors"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/vhive-serverless/loader/pkg/common"
+)
+
+// interval at which the shards pass their records to the sink
+const defaultCollectorFlushInterval = 100 * time.Millisecond
+
+// RecordCollector receives the execution records of the invocations.
+type RecordCollector interface {
+	Collect(record *ExecutionRecord)
+}
+
+// RecordObserver aggregates the execution records as they are written.
+type RecordObserver interface {
+	Add(record *ExecutionRecord)
+}
+
+// ShardedCollector collects the execution records without blocking the invocations. Records are enqueued to one of
+// several lock-free queues, each drained periodically by a writer passing the records in batches to the sink.
+type ShardedCollector struct {
+	shards []*common.LockFreeQueue[*ExecutionRecord]
+	next   atomic.Uint64
+
+	sink      RecordSink
+	observers []RecordObserver
+	batches   chan []*ExecutionRecord
+
+	stop       chan struct{}
+	shardsDone sync.WaitGroup
+	sinkDone   chan struct{}
+
+	written int64
+	err     error
+}
+
+// NewShardedCollector starts the writers of the shards and of the sink. The records are also added to the observers.
+// The number of shards defaults to GOMAXPROCS and the flush interval to 100ms if not positive.
+func NewShardedCollector(sink RecordSink, shards int, flushInterval time.Duration, observers ...RecordObserver) *ShardedCollector {
+	if shards <= 0 {
+		shards = runtime.GOMAXPROCS(0)
+	}
+	if flushInterval <= 0 {
+		flushInterval = defaultCollectorFlushInterval
+	}
+
+	c := &ShardedCollector{
+		shards:    make([]*common.LockFreeQueue[*ExecutionRecord], shards),
+		sink:      sink,
+		observers: observers,
+		batches:   make(chan []*ExecutionRecord, 4*shards),
+		stop:      make(chan struct{}),
+		sinkDone:  make(chan struct{}),
+	}
+
+	c.shardsDone.Add(shards)
+	for i := range c.shards {
+		c.shards[i] = common.NewLockFreeQueue[*ExecutionRecord]()
+		go c.runShardWriter(c.shards[i], flushInterval)
+	}
+	go c.runSinkWriter()
+
+	return c
+}
+
+// Collect enqueues the record to the next shard, never waiting for the sink.
+func (c *ShardedCollector) Collect(record *ExecutionRecord) {
+	c.shards[c.next.Add(1)%uint64(len(c.shards))].Enqueue(record)
+}
+
+func (c *ShardedCollector) runShardWriter(shard *common.LockFreeQueue[*ExecutionRecord], flushInterval time.Duration) {
+	defer c.shardsDone.Done()
+
+	ticker := time.NewTicker(flushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			c.flush(shard)
+		case <-c.stop:
+			c.flush(shard)
+			return
+		}
+	}
+}
+
+// flush passes the records enqueued to the shard to the sink writer. Each shard has a single writer, so the records
+// counted in the length of the queue can be dequeued.
+func (c *ShardedCollector) flush(shard *common.LockFreeQueue[*ExecutionRecord]) {
+	length := shard.Length()
+	if length == 0 {
+		return
+	}
+
+	batch := make([]*ExecutionRecord, length)
+	for i := range batch {
+		batch[i] = shard.Dequeue()
+	}
+
+	c.batches <- batch
+}
+
+func (c *ShardedCollector) runSinkWriter() {
+	defer close(c.sinkDone)
+
+	for batch := range c.batches {
+		for _, record := range batch {
+			// records are no longer written after the first error of the sink, but still observed
+			if c.err == nil {
+				if c.err = c.sink.Write(record); c.err == nil {
+					c.written++
+				}
+			}
+			for _, observer := range c.observers {
+				observer.Add(record)
+			}
+		}
+	}
+
+	c.err = errors.Join(c.err, c.sink.Close())
+}
+
+// Close writes the records collected so far and closes the sink, returning the number of records written. Records
+// must not be collected once Close is called.
+func (c *ShardedCollector) Close() (int64, error) {
+	close(c.stop)
+	c.shardsDone.Wait()
+
+	close(c.batches)
+	<-c.sinkDone
+
+	return c.written, c.err
+}
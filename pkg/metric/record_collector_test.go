@@ -0,0 +1,123 @@
+package metric
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// countingSink counts the records written, failing after failAfter records if positive.
+type countingSink struct {
+	written   atomic.Int64
+	failAfter int64
+	closed    bool
+}
+
+func (s *countingSink) Write(record any) error {
+	if s.written.Add(1) == s.failAfter {
+		return errors.New("disk full")
+	}
+	return nil
+}
+
+func (s *countingSink) Close() error {
+	s.closed = true
+	return nil
+}
+
+func TestShardedCollector(t *testing.T) {
+	sink := &countingSink{}
+	coldStarts := NewColdStartSummarizer()
+	collector := NewShardedCollector(sink, 4, time.Millisecond, coldStarts)
+
+	const producers, recordsPerProducer = 16, 1000
+
+	var wg sync.WaitGroup
+	for i := 0; i < producers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < recordsPerProducer; j++ {
+				collector.Collect(&ExecutionRecord{Function: "f", StartType: Cold})
+			}
+		}()
+	}
+	wg.Wait()
+
+	written, err := collector.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if written != producers*recordsPerProducer || sink.written.Load() != written || !sink.closed {
+		t.Errorf("Expected %d records to be written, got %d (%d in the sink)", producers*recordsPerProducer, written, sink.written.Load())
+	}
+	if summaries := coldStarts.Summaries(); len(summaries) != 1 || summaries[0].ColdStarts != producers*recordsPerProducer {
+		t.Errorf("Records should be added to the cold start summary, got %+v", summaries)
+	}
+}
+
+func TestShardedCollectorSinkError(t *testing.T) {
+	collector := NewShardedCollector(&countingSink{failAfter: 2}, 1, 0)
+	for i := 0; i < 3; i++ {
+		collector.Collect(&ExecutionRecord{})
+	}
+
+	// only the record written before the error counts
+	if written, err := collector.Close(); err == nil || written != 1 {
+		t.Errorf("Expected the error of the sink after 1 record, got %d records - %v", written, err)
+	}
+}
+
+func TestShardedCollectorEmpty(t *testing.T) {
+	sink := &countingSink{}
+	if written, err := NewShardedCollector(sink, 0, 0).Close(); written != 0 || err != nil || !sink.closed {
+		t.Errorf("Expected no records and a closed sink, got %d - %v", written, err)
+	}
+}
+
+// BenchmarkShardedCollector measures the rate at which concurrent invocations can hand their records over, until
+// they are all written.
+func BenchmarkShardedCollector(b *testing.B) {
+	record := &ExecutionRecord{}
+	collector := NewShardedCollector(&countingSink{}, 0, 0)
+
+	start := time.Now()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			collector.Collect(record)
+		}
+	})
+	if _, err := collector.Close(); err != nil {
+		b.Fatal(err)
+	}
+
+	b.ReportMetric(float64(b.N)/time.Since(start).Seconds(), "records/s")
+}
+
+// BenchmarkChannelCollector is the baseline of a single unbuffered channel read by one goroutine.
+func BenchmarkChannelCollector(b *testing.B) {
+	record := &ExecutionRecord{}
+	sink := &countingSink{}
+	records := make(chan *ExecutionRecord)
+	done := make(chan struct{})
+
+	go func() {
+		for record := range records {
+			_ = sink.Write(record)
+		}
+		close(done)
+	}()
+
+	start := time.Now()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			records <- record
+		}
+	})
+	close(records)
+	<-done
+
+	b.ReportMetric(float64(b.N)/time.Since(start).Seconds(), "records/s")
+}
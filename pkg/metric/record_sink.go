@@ -0,0 +1,308 @@
+package metric
+
+import (
+	"bufio"
+	"compress/gzip"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"sync"
+
+	"github.com/gocarina/gocsv"
+	"github.com/klauspost/compress/zstd"
+	log "github.com/sirupsen/logrus"
+	"github.com/vhive-serverless/loader/pkg/common"
+)
+
+// RecordSink writes records of the same struct type to an output file.
+type RecordSink interface {
+	Write(record any) error
+	// Close writes the buffered records and closes the file.
+	Close() error
+}
+
+// OutputExtension returns the extension of the files written in the format with the compression. Parquet files
+// compress their pages instead of the whole file.
+func OutputExtension(format string, compression string) string {
+	var extension string
+	switch format {
+	case common.OutputFormatParquet:
+		return ".parquet"
+	case common.OutputFormatSQLite:
+		return ".sqlite"
+	case common.OutputFormatJSONLines:
+		extension = ".jsonl"
+	default:
+		extension = ".csv"
+	}
+
+	switch compression {
+	case common.OutputCompressionGzip:
+		extension += ".gz"
+	case common.OutputCompressionZstd:
+		extension += ".zst"
+	}
+
+	return extension
+}
+
+// NewRecordSink creates the file at path, to which the sink writes records in the format with the compression.
+// CSV is the default format, with a header derived from the csv tags of the records.
+func NewRecordSink(path string, format string, compression string) (RecordSink, error) {
+	switch format {
+	case common.OutputFormatParquet:
+		return newParquetSink(path, compression)
+	case common.OutputFormatSQLite:
+		return newSQLiteSink(path)
+	}
+
+	file, err := createCompressedFile(path, compression)
+	if err != nil {
+		return nil, err
+	}
+
+	if format == common.OutputFormatJSONLines {
+		return &jsonLinesSink{file: file, encoder: json.NewEncoder(file)}, nil
+	}
+
+	return newCSVSink(file), nil
+}
+
+// RunRecordWriter writes the records to the sink until the channel is closed.
+func RunRecordWriter(records chan any, sink RecordSink, writerDone *sync.WaitGroup) {
+	for record := range records {
+		if err := sink.Write(record); err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	if err := sink.Close(); err != nil {
+		log.Fatal(err)
+	}
+
+	writerDone.Done()
+}
+
+// ReadExecutionRecords reads the execution records written in the CSV or JSON lines format.
+func ReadExecutionRecords(path string, format string, compression string) ([]*ExecutionRecord, error) {
+	if format != "" && format != common.OutputFormatCSV && format != common.OutputFormatJSONLines {
+		return nil, fmt.Errorf("reading %s outputs is not supported", format)
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var reader io.Reader = bufio.NewReader(file)
+	switch compression {
+	case common.OutputCompressionGzip:
+		if reader, err = gzip.NewReader(reader); err != nil {
+			return nil, err
+		}
+	case common.OutputCompressionZstd:
+		decoder, err := zstd.NewReader(reader)
+		if err != nil {
+			return nil, err
+		}
+		defer decoder.Close()
+		reader = decoder
+	}
+
+	var records []*ExecutionRecord
+	if format != common.OutputFormatJSONLines {
+		err = gocsv.Unmarshal(reader, &records)
+		return records, err
+	}
+
+	decoder := json.NewDecoder(reader)
+	for {
+		record := &ExecutionRecord{}
+		if err = decoder.Decode(record); err == io.EOF {
+			return records, nil
+		} else if err != nil {
+			return nil, err
+		}
+		records = append(records, record)
+	}
+}
+
+// compressedFile buffers and compresses the writes to a file.
+type compressedFile struct {
+	io.Writer
+	file       *os.File
+	buffer     *bufio.Writer
+	compressor io.WriteCloser
+}
+
+func createCompressedFile(path string, compression string) (*compressedFile, error) {
+	file, err := os.Create(path)
+	if err != nil {
+		return nil, err
+	}
+
+	f := &compressedFile{file: file, buffer: bufio.NewWriter(file)}
+	switch compression {
+	case common.OutputCompressionGzip:
+		f.compressor = gzip.NewWriter(f.buffer)
+	case common.OutputCompressionZstd:
+		f.compressor, err = zstd.NewWriter(f.buffer)
+	case "":
+	default:
+		err = fmt.Errorf("unsupported output compression %s", compression)
+	}
+	if err != nil {
+		_ = file.Close()
+		return nil, err
+	}
+
+	f.Writer = f.buffer
+	if f.compressor != nil {
+		f.Writer = f.compressor
+	}
+
+	return f, nil
+}
+
+func (f *compressedFile) Close() error {
+	var errs []error
+	if f.compressor != nil {
+		errs = append(errs, f.compressor.Close())
+	}
+	errs = append(errs, f.buffer.Flush(), f.file.Close())
+
+	return errors.Join(errs...)
+}
+
+// csvSink marshals the records with gocsv, as RunCSVWriter.
+type csvSink struct {
+	file    io.WriteCloser
+	records chan any
+	done    chan error
+}
+
+func newCSVSink(file io.WriteCloser) *csvSink {
+	s := &csvSink{file: file, records: make(chan any, 100), done: make(chan error, 1)}
+
+	go func() {
+		s.done <- gocsv.MarshalChan(s.records, gocsv.NewSafeCSVWriter(csv.NewWriter(file)))
+	}()
+
+	return s
+}
+
+func (s *csvSink) Write(record any) error {
+	s.records <- record
+	return nil
+}
+
+func (s *csvSink) Close() error {
+	close(s.records)
+	return errors.Join(<-s.done, s.file.Close())
+}
+
+// jsonLinesSink writes each record as a JSON object on its own line, named after the json tags of the record.
+type jsonLinesSink struct {
+	file    io.WriteCloser
+	encoder *json.Encoder
+}
+
+func (s *jsonLinesSink) Write(record any) error {
+	return s.encoder.Encode(record)
+}
+
+func (s *jsonLinesSink) Close() error {
+	return s.file.Close()
+}
+
+type columnType int
+
+const (
+	integerColumn columnType = iota
+	floatColumn
+	booleanColumn
+	stringColumn
+	// lists and other composite fields are stored as JSON strings
+	jsonColumn
+)
+
+// recordColumn is a field of a record written as a column by the columnar sinks, named after its csv tag.
+type recordColumn struct {
+	name  string
+	index []int
+	kind  columnType
+}
+
+// recordColumns returns the columns of the record type, flattening embedded structs as gocsv.
+func recordColumns(t reflect.Type) []recordColumn {
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	var columns []recordColumn
+	for _, field := range reflect.VisibleFields(t) {
+		if !field.IsExported() || field.Anonymous {
+			continue
+		}
+
+		name := field.Tag.Get("csv")
+		if name == "-" {
+			continue
+		} else if name == "" {
+			name = field.Name
+		}
+
+		column := recordColumn{name: name, index: field.Index}
+		switch field.Type.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+			reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			column.kind = integerColumn
+		case reflect.Float32, reflect.Float64:
+			column.kind = floatColumn
+		case reflect.Bool:
+			column.kind = booleanColumn
+		case reflect.String:
+			column.kind = stringColumn
+		default:
+			column.kind = jsonColumn
+		}
+
+		columns = append(columns, column)
+	}
+
+	return columns
+}
+
+// value returns the column of the record as an int64, float64, bool or string.
+func (c *recordColumn) value(record reflect.Value) any {
+	field := record.FieldByIndex(c.index)
+
+	switch c.kind {
+	case integerColumn:
+		if field.CanInt() {
+			return field.Int()
+		}
+		return int64(field.Uint())
+	case floatColumn:
+		return field.Float()
+	case booleanColumn:
+		return field.Bool()
+	case stringColumn:
+		return field.String()
+	default:
+		data, err := json.Marshal(field.Interface())
+		if err != nil {
+			return ""
+		}
+		return string(data)
+	}
+}
+
+func recordValue(record any) reflect.Value {
+	return reflect.Indirect(reflect.ValueOf(record))
+}
@@ -0,0 +1,176 @@
+package metric
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/parquet-go/parquet-go"
+	"github.com/parquet-go/parquet-go/format"
+	"github.com/vhive-serverless/loader/pkg/common"
+)
+
+func testExecutionRecords() []*ExecutionRecord {
+	var records []*ExecutionRecord
+	for i := 0; i < 10; i++ {
+		records = append(records, &ExecutionRecord{
+			ExecutionRecordBase: ExecutionRecordBase{
+				Phase:             int(common.ExecutionPhase),
+				InvocationID:      "invocation",
+				StartTime:         int64(i) * 1000,
+				RequestedDuration: uint32(i),
+				ResponseTime:      int64(i) * 10,
+				FunctionTimeout:   i%3 == 0,
+			},
+			StartType: Cold,
+			Function:  "trace-func-0",
+		})
+	}
+
+	return records
+}
+
+func writeRecords[T any](t *testing.T, path string, format string, compression string, records []T) {
+	sink, err := NewRecordSink(path, format, compression)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, record := range records {
+		if err = sink.Write(record); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err = sink.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRecordSinkRoundTrip(t *testing.T) {
+	records := testExecutionRecords()
+
+	for _, format := range []string{common.OutputFormatCSV, common.OutputFormatJSONLines} {
+		for _, compression := range common.ValidOutputCompressions {
+			path := filepath.Join(t.TempDir(), "duration"+OutputExtension(format, compression))
+			writeRecords(t, path, format, compression, records)
+
+			read, err := ReadExecutionRecords(path, format, compression)
+			if err != nil {
+				t.Fatalf("Failed to read %s: %v", path, err)
+			}
+			if !reflect.DeepEqual(read, records) {
+				t.Errorf("Records read from %s differ from those written", path)
+			}
+		}
+	}
+}
+
+func TestJSONLinesSink(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cluster_usage.csv")
+	usage := ClusterUsage{Timestamp: 1, Cpu: []string{"10%"}, Pods: []int{3}}
+	writeRecords(t, path, common.OutputFormatJSONLines, "", []ClusterUsage{usage})
+
+	expected, _ := json.Marshal(usage)
+	data, _ := os.ReadFile(path)
+	if string(data) != string(expected)+"\n" {
+		t.Errorf("Unexpected JSON line %s", data)
+	}
+}
+
+func TestSQLiteSink(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "duration.sqlite")
+	writeRecords(t, path, common.OutputFormatSQLite, "", testExecutionRecords())
+
+	db, err := sql.Open("sqlite3", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	var count, timeouts int
+	var responseTime int64
+	var startType string
+	if err = db.QueryRow(`SELECT COUNT(*), SUM("functionTimeout"), MAX("responseTime"), MIN("startType") FROM records`).
+		Scan(&count, &timeouts, &responseTime, &startType); err != nil {
+		t.Fatal(err)
+	}
+	if count != 10 || timeouts != 4 || responseTime != 90 || startType != string(Cold) {
+		t.Errorf("Unexpected records: %d records, %d timeouts, %d max response time, %s start", count, timeouts, responseTime, startType)
+	}
+}
+
+func TestParquetSink(t *testing.T) {
+	for _, compression := range []string{"", common.OutputCompressionGzip, common.OutputCompressionZstd} {
+		path := filepath.Join(t.TempDir(), "duration.parquet")
+		writeRecords(t, path, common.OutputFormatParquet, compression, testExecutionRecords())
+
+		file, err := os.Open(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer file.Close()
+		stat, err := file.Stat()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		parquetFile, err := parquet.OpenFile(file, stat.Size())
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// the columns are in the order of the CSV header
+		fields := parquetFile.Schema().Fields()
+		columns := recordColumns(reflect.TypeOf(ExecutionRecord{}))
+		if len(fields) != len(columns) || parquetFile.NumRows() != 10 {
+			t.Fatalf("Unexpected schema of %d columns and %d rows", len(fields), parquetFile.NumRows())
+		}
+		responseTime := -1
+		for i, column := range columns {
+			if fields[i].Name() != column.name {
+				t.Fatalf("Unexpected column %s instead of %s", fields[i].Name(), column.name)
+			}
+			if column.name == "responseTime" {
+				responseTime = i
+			}
+		}
+
+		rowGroup := parquetFile.Metadata().RowGroups[0]
+		if codec := rowGroup.Columns[responseTime].MetaData.Codec; (compression == "") != (codec == format.Uncompressed) {
+			t.Errorf("Unexpected codec %s", codec)
+		}
+
+		rows := make([]parquet.Row, 10)
+		reader := parquet.NewReader(parquetFile)
+		if n, err := reader.ReadRows(rows); n != 10 {
+			t.Fatalf("Read %d rows - %v", n, err)
+		}
+		for i, row := range rows {
+			if value := row[responseTime].Int64(); value != int64(i)*10 {
+				t.Errorf("Unexpected response time %d in row %d", value, i)
+			}
+		}
+	}
+}
+
+func TestParquetSinkWithoutRecords(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "duration.parquet")
+	writeRecords[*ExecutionRecord](t, path, common.OutputFormatParquet, "", nil)
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	parquetFile, err := parquet.OpenFile(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if parquetFile.NumRows() != 0 || len(parquetFile.Schema().Fields()) != 0 {
+		t.Errorf("Unexpected file of %d rows", parquetFile.NumRows())
+	}
+}
@@ -0,0 +1,133 @@
+package metric
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+
+	// registers the sqlite3 driver, which requires cgo
+	_ "github.com/mattn/go-sqlite3"
+)
+
+const (
+	// table of the records in each SQLite output
+	sqliteTable = "records"
+	// number of records inserted per transaction
+	sqliteBatchSize = 10000
+)
+
+// sqliteSink inserts the records into a table, created once the type of the records is known.
+type sqliteSink struct {
+	db      *sql.DB
+	tx      *sql.Tx
+	insert  *sql.Stmt
+	columns []recordColumn
+	pending int
+}
+
+func newSQLiteSink(path string) (*sqliteSink, error) {
+	// truncates the output, as for the other formats
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+
+	db, err := sql.Open("sqlite3", path)
+	if err != nil {
+		return nil, err
+	}
+	if err = db.Ping(); err != nil {
+		_ = db.Close()
+		return nil, err
+	}
+
+	return &sqliteSink{db: db}, nil
+}
+
+func (s *sqliteSink) Write(record any) error {
+	if s.columns == nil {
+		if err := s.createTable(reflect.TypeOf(record)); err != nil {
+			return err
+		}
+	}
+
+	if s.tx == nil {
+		tx, err := s.db.Begin()
+		if err != nil {
+			return err
+		}
+		s.tx = tx
+	}
+
+	value := recordValue(record)
+	values := make([]any, len(s.columns))
+	for i := range s.columns {
+		values[i] = s.columns[i].value(value)
+	}
+
+	if _, err := s.tx.Stmt(s.insert).Exec(values...); err != nil {
+		return err
+	}
+
+	s.pending++
+	if s.pending == sqliteBatchSize {
+		return s.commit()
+	}
+
+	return nil
+}
+
+func (s *sqliteSink) createTable(t reflect.Type) error {
+	s.columns = recordColumns(t)
+
+	definitions := make([]string, len(s.columns))
+	placeholders := make([]string, len(s.columns))
+	for i, column := range s.columns {
+		sqlType := "TEXT"
+		switch column.kind {
+		case integerColumn, booleanColumn:
+			sqlType = "INTEGER"
+		case floatColumn:
+			sqlType = "REAL"
+		}
+
+		definitions[i] = fmt.Sprintf("%q %s", column.name, sqlType)
+		placeholders[i] = "?"
+	}
+
+	if _, err := s.db.Exec(fmt.Sprintf("CREATE TABLE %s (%s)", sqliteTable, strings.Join(definitions, ", "))); err != nil {
+		return err
+	}
+
+	insert, err := s.db.Prepare(fmt.Sprintf("INSERT INTO %s VALUES (%s)", sqliteTable, strings.Join(placeholders, ", ")))
+	if err != nil {
+		return err
+	}
+	s.insert = insert
+
+	return nil
+}
+
+func (s *sqliteSink) commit() error {
+	s.pending = 0
+	if s.tx == nil {
+		return nil
+	}
+
+	tx := s.tx
+	s.tx = nil
+
+	return tx.Commit()
+}
+
+func (s *sqliteSink) Close() error {
+	errs := []error{s.commit()}
+	if s.insert != nil {
+		errs = append(errs, s.insert.Close())
+	}
+	errs = append(errs, s.db.Close())
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,284 @@
+package simulator
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/vhive-serverless/loader/pkg/config"
+)
+
+var (
+	ErrFunctionNotDeployed = errors.New("function not deployed")
+	ErrQueueTimeout        = errors.New("no instance became available before the queue timeout")
+)
+
+// Invocation describes how the platform served a request.
+type Invocation struct {
+	Instance string
+	Cold     bool
+	// time spent waiting for an instance, including the cold start of instances started by other requests
+	QueueingTime  time.Duration
+	ColdStartTime time.Duration
+	// execution of the function on the instance
+	Start time.Time
+	End   time.Time
+	// time since the instance was created, at the end of the execution
+	InstanceUptime time.Duration
+}
+
+type instance struct {
+	id       string
+	busy     int
+	created  time.Time
+	readyAt  time.Time
+	lastUsed time.Time
+}
+
+type function struct {
+	memoryMiB int
+	minScale  int
+	instances []*instance
+}
+
+// Platform is an in-process model of a FaaS platform running on a single node. Functions scale out by creating
+// instances, which pay a cold start and are removed once idle for longer than the keep-alive. Instances of all
+// functions share the memory of the node; requests that find neither a free instance nor memory for a new one wait
+// in queue, evicting the least recently used idle instance of another function if possible.
+type Platform struct {
+	cfg *config.SimulatedConfig
+
+	mutex           sync.Mutex
+	instanceFreed   *sync.Cond
+	rng             *rand.Rand
+	functions       map[string]*function
+	usedMemoryMiB   int
+	createdInstance int
+}
+
+var (
+	platformsMutex sync.Mutex
+	platforms      = make(map[*config.SimulatedConfig]*Platform)
+)
+
+// ForConfig returns the platform modeled by the configuration, so that the deployer and the invoker of the loader
+// operate on the same platform.
+func ForConfig(cfg *config.SimulatedConfig) *Platform {
+	platformsMutex.Lock()
+	defer platformsMutex.Unlock()
+
+	if platform, ok := platforms[cfg]; ok {
+		return platform
+	}
+
+	platform := NewPlatform(cfg)
+	platforms[cfg] = platform
+
+	return platform
+}
+
+func NewPlatform(cfg *config.SimulatedConfig) *Platform {
+	p := &Platform{
+		cfg:       cfg,
+		rng:       rand.New(rand.NewSource(cfg.Seed)),
+		functions: make(map[string]*function),
+	}
+	p.instanceFreed = sync.NewCond(&p.mutex)
+
+	return p
+}
+
+// Deploy registers the function and starts its initial instances, which are kept warm if within the minimum scale.
+func (p *Platform) Deploy(name string, memoryMiB int, initialScale int, minScale int) error {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	f, ok := p.functions[name]
+	if !ok {
+		f = &function{}
+		p.functions[name] = f
+	}
+	f.memoryMiB = max(memoryMiB, 1)
+	f.minScale = minScale
+
+	now := time.Now()
+	for len(f.instances) < max(initialScale, minScale) {
+		if !p.reserveMemory(f.memoryMiB, now) {
+			return fmt.Errorf("not enough memory for %d instances of %s", max(initialScale, minScale), name)
+		}
+		f.instances = append(f.instances, p.newInstance(now, now))
+	}
+
+	return nil
+}
+
+// Clean removes all functions and their instances.
+func (p *Platform) Clean() {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	p.functions = make(map[string]*function)
+	p.usedMemoryMiB = 0
+}
+
+// Instances returns the number of instances of the function.
+func (p *Platform) Instances(name string) int {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	if f, ok := p.functions[name]; ok {
+		p.expireIdleInstances(time.Now())
+		return len(f.instances)
+	}
+
+	return 0
+}
+
+// Invoke executes the function for the given duration, blocking until the request completes.
+func (p *Platform) Invoke(name string, runtime time.Duration) (Invocation, error) {
+	arrival := time.Now()
+	deadline := arrival.Add(time.Duration(p.cfg.QueueTimeoutSeconds) * time.Second)
+
+	// wakes up queued requests once the queue timeout expires
+	timer := time.AfterFunc(time.Until(deadline), func() {
+		p.mutex.Lock()
+		p.instanceFreed.Broadcast()
+		p.mutex.Unlock()
+	})
+	defer timer.Stop()
+
+	p.mutex.Lock()
+	f, ok := p.functions[name]
+	if !ok {
+		p.mutex.Unlock()
+		return Invocation{}, ErrFunctionNotDeployed
+	}
+
+	var acquired *instance
+	cold := false
+	for acquired == nil {
+		now := time.Now()
+		p.expireIdleInstances(now)
+
+		if acquired = p.availableInstance(f); acquired != nil {
+			break
+		}
+
+		if p.reserveMemory(f.memoryMiB, now) {
+			coldStart := time.Duration(p.cfg.ColdStartMedianMs * math.Exp(p.cfg.ColdStartSigma*p.rng.NormFloat64()) * float64(time.Millisecond))
+			acquired = p.newInstance(now, now.Add(coldStart))
+			f.instances = append(f.instances, acquired)
+			cold = true
+
+			break
+		}
+
+		if !now.Before(deadline) {
+			p.mutex.Unlock()
+			return Invocation{QueueingTime: now.Sub(arrival)}, ErrQueueTimeout
+		}
+		p.instanceFreed.Wait()
+	}
+	acquired.busy++
+	readyAt := acquired.readyAt
+	p.mutex.Unlock()
+
+	time.Sleep(time.Until(readyAt))
+	invocation := Invocation{Instance: acquired.id, Cold: cold, Start: time.Now()}
+	if cold {
+		invocation.ColdStartTime = readyAt.Sub(acquired.created)
+		invocation.QueueingTime = acquired.created.Sub(arrival)
+	} else {
+		invocation.QueueingTime = invocation.Start.Sub(arrival)
+	}
+
+	time.Sleep(runtime)
+	invocation.End = time.Now()
+	invocation.InstanceUptime = invocation.End.Sub(acquired.created)
+
+	p.mutex.Lock()
+	acquired.busy--
+	acquired.lastUsed = invocation.End
+	p.instanceFreed.Broadcast()
+	p.mutex.Unlock()
+
+	return invocation, nil
+}
+
+func (p *Platform) newInstance(created time.Time, readyAt time.Time) *instance {
+	p.createdInstance++
+
+	return &instance{
+		id:       fmt.Sprintf("instance-%d", p.createdInstance),
+		created:  created,
+		readyAt:  readyAt,
+		lastUsed: readyAt,
+	}
+}
+
+// availableInstance returns the most recently used instance with a free slot, so that the others can expire.
+func (p *Platform) availableInstance(f *function) *instance {
+	var result *instance
+	for _, inst := range f.instances {
+		if inst.busy < p.cfg.InstanceConcurrency && (result == nil || inst.lastUsed.After(result.lastUsed)) {
+			result = inst
+		}
+	}
+
+	return result
+}
+
+// reserveMemory reserves memory for a new instance, evicting idle instances of other functions if needed.
+func (p *Platform) reserveMemory(memoryMiB int, now time.Time) bool {
+	for p.usedMemoryMiB+memoryMiB > p.cfg.NodeMemoryMiB {
+		if !p.evictIdleInstance(now) {
+			return false
+		}
+	}
+
+	p.usedMemoryMiB += memoryMiB
+	return true
+}
+
+func (p *Platform) evictIdleInstance(now time.Time) bool {
+	var victim *function
+	victimIdx := -1
+	for _, f := range p.functions {
+		if len(f.instances) <= f.minScale {
+			continue
+		}
+		for i, inst := range f.instances {
+			if inst.busy == 0 && now.After(inst.readyAt) && (victimIdx < 0 || inst.lastUsed.Before(victim.instances[victimIdx].lastUsed)) {
+				victim, victimIdx = f, i
+			}
+		}
+	}
+
+	if victim == nil {
+		return false
+	}
+
+	p.removeInstance(victim, victimIdx)
+	return true
+}
+
+func (p *Platform) expireIdleInstances(now time.Time) {
+	keepAlive := time.Duration(p.cfg.KeepAliveSeconds) * time.Second
+
+	for _, f := range p.functions {
+		for i := len(f.instances) - 1; i >= 0 && len(f.instances) > f.minScale; i-- {
+			inst := f.instances[i]
+			if inst.busy == 0 && now.Sub(inst.lastUsed) > keepAlive {
+				p.removeInstance(f, i)
+			}
+		}
+	}
+}
+
+func (p *Platform) removeInstance(f *function, idx int) {
+	f.instances = append(f.instances[:idx], f.instances[idx+1:]...)
+	p.usedMemoryMiB -= f.memoryMiB
+}
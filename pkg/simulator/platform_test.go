@@ -0,0 +1,153 @@
+package simulator
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/vhive-serverless/loader/pkg/config"
+)
+
+func testConfig() *config.SimulatedConfig {
+	return &config.SimulatedConfig{
+		KeepAliveSeconds:    1,
+		ColdStartMedianMs:   50,
+		ColdStartSigma:      0.1,
+		InstanceConcurrency: 1,
+		NodeMemoryMiB:       1024,
+		QueueTimeoutSeconds: 1,
+		Seed:                42,
+	}
+}
+
+func TestColdAndWarmStarts(t *testing.T) {
+	p := NewPlatform(testConfig())
+	if err := p.Deploy("f", 128, 0, 0); err != nil {
+		t.Fatal(err)
+	}
+
+	first, err := p.Invoke("f", 10*time.Millisecond)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !first.Cold || first.ColdStartTime < 20*time.Millisecond {
+		t.Errorf("First invocation should be a cold start, got %+v", first)
+	}
+	if first.End.Sub(first.Start) < 10*time.Millisecond {
+		t.Errorf("Execution should last the requested runtime, got %v", first.End.Sub(first.Start))
+	}
+
+	second, err := p.Invoke("f", 10*time.Millisecond)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if second.Cold || second.Instance != first.Instance {
+		t.Errorf("Second invocation should reuse the warm instance, got %+v", second)
+	}
+	if second.InstanceUptime <= first.InstanceUptime {
+		t.Errorf("Instance uptime should grow, got %v after %v", second.InstanceUptime, first.InstanceUptime)
+	}
+
+	if _, err = p.Invoke("missing", time.Millisecond); !errors.Is(err, ErrFunctionNotDeployed) {
+		t.Errorf("Expected ErrFunctionNotDeployed, got %v", err)
+	}
+}
+
+func TestKeepAlive(t *testing.T) {
+	p := NewPlatform(testConfig())
+	if err := p.Deploy("f", 128, 1, 0); err != nil {
+		t.Fatal(err)
+	}
+	if err := p.Deploy("g", 128, 1, 1); err != nil {
+		t.Fatal(err)
+	}
+
+	time.Sleep(1100 * time.Millisecond)
+
+	if p.Instances("f") != 0 {
+		t.Errorf("Idle instance should expire after the keep-alive")
+	}
+	if p.Instances("g") != 1 {
+		t.Errorf("Instances within the minimum scale should be kept")
+	}
+}
+
+func TestInstanceConcurrency(t *testing.T) {
+	cfg := testConfig()
+	cfg.InstanceConcurrency = 2
+	p := NewPlatform(cfg)
+	if err := p.Deploy("f", 128, 1, 0); err != nil {
+		t.Fatal(err)
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 3; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := p.Invoke("f", 100*time.Millisecond); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if p.Instances("f") != 2 {
+		t.Errorf("Expected 2 instances serving 3 concurrent requests, got %d", p.Instances("f"))
+	}
+}
+
+func TestNodeCapacity(t *testing.T) {
+	cfg := testConfig()
+	cfg.NodeMemoryMiB = 128
+	p := NewPlatform(cfg)
+	if err := p.Deploy("f", 128, 1, 0); err != nil {
+		t.Fatal(err)
+	}
+	if err := p.Deploy("g", 128, 0, 0); err != nil {
+		t.Fatal(err)
+	}
+
+	// the idle instance of f is evicted to make room for g
+	invocation, err := p.Invoke("g", time.Millisecond)
+	if err != nil || !invocation.Cold {
+		t.Fatalf("Expected a cold start after eviction, got %+v - %v", invocation, err)
+	}
+	if p.Instances("f") != 0 {
+		t.Errorf("Idle instance of f should have been evicted")
+	}
+
+	// a request queued behind a busy instance is served once it is freed
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		if _, err := p.Invoke("g", 200*time.Millisecond); err != nil {
+			t.Error(err)
+		}
+	}()
+	time.Sleep(50 * time.Millisecond)
+
+	queued, err := p.Invoke("f", time.Millisecond)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if queued.QueueingTime < 100*time.Millisecond {
+		t.Errorf("Request should have waited for memory, queued for %v", queued.QueueingTime)
+	}
+	wg.Wait()
+
+	// requests time out when the node stays full
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		_, _ = p.Invoke("g", 1500*time.Millisecond)
+	}()
+	time.Sleep(100 * time.Millisecond)
+
+	if _, err = p.Invoke("f", time.Millisecond); !errors.Is(err, ErrQueueTimeout) {
+		t.Errorf("Expected ErrQueueTimeout, got %v", err)
+	}
+	wg.Wait()
+}
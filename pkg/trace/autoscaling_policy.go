@@ -0,0 +1,173 @@
+package trace
+
+import (
+	"math"
+	"slices"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/vhive-serverless/loader/pkg/common"
+	"github.com/vhive-serverless/loader/pkg/config"
+)
+
+const (
+	// bounds of the stable window of the Knative autoscaler
+	burstyStableWindowSeconds  = 30
+	defaultStableWindowSeconds = 60
+	// per-minute invocation counts varying more than their mean are considered bursty
+	burstyCoefficientOfVariation = 1.0
+
+	// instances are not retained for longer than the keep-alive of Azure Functions
+	maxScaleToZeroRetentionSeconds = 600
+)
+
+// ApplyAutoscalingPolicies computes the scaling settings of each function from its per-minute invocations and
+// runtime, according to the policy of the configuration, and applies the per-function overrides.
+func ApplyAutoscalingPolicies(functions []*common.Function, cfg *config.AutoscalingConfig) {
+	if cfg == nil {
+		return
+	}
+
+	for _, function := range functions {
+		override, overridden := cfg.Overrides[function.Name]
+
+		policy := cfg.Policy
+		if overridden && override.Policy != "" {
+			policy = override.Policy
+		}
+
+		settings := profileAutoscaling(function, policy, cfg.MaxScaleHeadroom)
+		if overridden {
+			applyAutoscalingOverride(&settings, override)
+		}
+
+		function.Autoscaling = &settings
+		log.Debugf("Function %s autoscaling settings: %+v", function.Name, settings)
+	}
+}
+
+func profileAutoscaling(function *common.Function, policy string, maxScaleHeadroom float64) common.AutoscalingSettings {
+	settings := common.AutoscalingSettings{Policy: policy}
+	if policy == common.AutoscalingPolicyStatic {
+		return settings
+	}
+
+	invocations := perMinuteInvocations(function)
+	runtimeMs := 0.0
+	if function.RuntimeStats != nil {
+		runtimeMs = function.RuntimeStats.Average
+	}
+
+	// expected concurrency of each minute, following Little's law as in profileConcurrency
+	peakConcurrency, meanConcurrency := 0.0, 0.0
+	for _, count := range invocations {
+		concurrency := float64(count) / 60.0 * runtimeMs / 1000.0
+		peakConcurrency = max(peakConcurrency, concurrency)
+		meanConcurrency += concurrency / float64(len(invocations))
+	}
+
+	settings.MaxScale = max(1, int(math.Ceil(peakConcurrency*maxScaleHeadroom)))
+	settings.StableWindowSeconds = defaultStableWindowSeconds
+	if coefficientOfVariation(invocations) > burstyCoefficientOfVariation {
+		settings.StableWindowSeconds = burstyStableWindowSeconds
+	}
+
+	switch policy {
+	case common.AutoscalingPolicyTrace:
+		settings.Metric = "concurrency"
+		// functions serve one request per instance
+		settings.Target = 1
+		settings.ScaleToZeroRetentionSeconds = min(medianIdleMinutes(invocations)*60, maxScaleToZeroRetentionSeconds)
+	case common.AutoscalingPolicyProvisioned:
+		settings.Metric = "concurrency"
+		settings.Target = 1
+		settings.MinScale = min(int(math.Ceil(meanConcurrency)), settings.MaxScale)
+		settings.MinScaleSet = true
+	case common.AutoscalingPolicyRPS:
+		settings.Metric = "rps"
+		// requests an instance can process per second
+		settings.Target = 1
+		if runtimeMs > 0 {
+			settings.Target = max(1, math.Round(1000.0/runtimeMs))
+		}
+	}
+
+	return settings
+}
+
+func applyAutoscalingOverride(settings *common.AutoscalingSettings, override config.AutoscalingOverride) {
+	if override.MinScale != nil {
+		settings.MinScale = *override.MinScale
+		settings.MinScaleSet = true
+	}
+	if override.MaxScale != nil {
+		settings.MaxScale = *override.MaxScale
+	}
+	if override.Metric != "" {
+		settings.Metric = override.Metric
+	}
+	if override.Target != nil {
+		settings.Target = *override.Target
+	}
+	if override.StableWindowSeconds != nil {
+		settings.StableWindowSeconds = *override.StableWindowSeconds
+	}
+	if override.ScaleToZeroRetentionSeconds != nil {
+		settings.ScaleToZeroRetentionSeconds = *override.ScaleToZeroRetentionSeconds
+	}
+}
+
+func perMinuteInvocations(function *common.Function) []int {
+	if function.Specification != nil && len(function.Specification.PerMinuteCount) > 0 {
+		return function.Specification.PerMinuteCount
+	}
+	if function.InvocationStats != nil {
+		return function.InvocationStats.Invocations
+	}
+
+	return nil
+}
+
+func coefficientOfVariation(invocations []int) float64 {
+	if len(invocations) == 0 {
+		return 0
+	}
+
+	mean := 0.0
+	for _, count := range invocations {
+		mean += float64(count) / float64(len(invocations))
+	}
+	if mean == 0 {
+		return 0
+	}
+
+	variance := 0.0
+	for _, count := range invocations {
+		variance += math.Pow(float64(count)-mean, 2) / float64(len(invocations))
+	}
+
+	return math.Sqrt(variance) / mean
+}
+
+// medianIdleMinutes returns the median length of the idle periods between two active minutes, or 0 if the function
+// is never idle in between.
+func medianIdleMinutes(invocations []int) int {
+	var gaps []int
+
+	lastActive := -1
+	for minute, count := range invocations {
+		if count == 0 {
+			continue
+		}
+		if lastActive >= 0 && minute-lastActive > 1 {
+			gaps = append(gaps, minute-lastActive-1)
+		}
+		lastActive = minute
+	}
+
+	if len(gaps) == 0 {
+		return 0
+	}
+
+	slices.Sort(gaps)
+	return gaps[len(gaps)/2]
+}
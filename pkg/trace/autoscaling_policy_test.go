@@ -0,0 +1,70 @@
+package trace
+
+import (
+	"testing"
+
+	"github.com/vhive-serverless/loader/pkg/common"
+	"github.com/vhive-serverless/loader/pkg/config"
+)
+
+func TestApplyAutoscalingPolicies(t *testing.T) {
+	minScale, maxScale := 0, 50
+	target := 5.0
+
+	newFunction := func(name string, invocations []int) *common.Function {
+		return &common.Function{
+			Name:          name,
+			RuntimeStats:  &common.FunctionRuntimeStats{Average: 1000},
+			Specification: &common.FunctionSpecification{PerMinuteCount: invocations},
+		}
+	}
+	functions := []*common.Function{
+		// peak concurrency of 10, idle for 2, 4 and 2 minutes in between
+		newFunction("trace", []int{600, 0, 0, 60, 0, 0, 0, 0, 60, 0, 0, 60}),
+		// steady concurrency of 4
+		newFunction("provisioned", []int{240, 240, 240, 240}),
+		newFunction("rps", []int{60, 60}),
+		newFunction("overridden", []int{60, 60}),
+		newFunction("static", []int{60, 60}),
+		newFunction("scale-to-zero", []int{60, 60}),
+	}
+
+	ApplyAutoscalingPolicies(functions, &config.AutoscalingConfig{
+		Policy:           common.AutoscalingPolicyTrace,
+		MaxScaleHeadroom: 1.2,
+		Overrides: map[string]config.AutoscalingOverride{
+			"provisioned":   {Policy: common.AutoscalingPolicyProvisioned},
+			"rps":           {Policy: common.AutoscalingPolicyRPS},
+			"overridden":    {MaxScale: &maxScale, Metric: "rps", Target: &target},
+			"static":        {Policy: common.AutoscalingPolicyStatic},
+			"scale-to-zero": {Policy: common.AutoscalingPolicyStatic, MinScale: &minScale},
+		},
+	})
+
+	expected := map[string]common.AutoscalingSettings{
+		"trace": {Policy: common.AutoscalingPolicyTrace, MaxScale: 12, Metric: "concurrency", Target: 1,
+			StableWindowSeconds: 30, ScaleToZeroRetentionSeconds: 120},
+		"provisioned": {Policy: common.AutoscalingPolicyProvisioned, MinScale: 4, MinScaleSet: true, MaxScale: 5,
+			Metric: "concurrency", Target: 1, StableWindowSeconds: 60},
+		"rps": {Policy: common.AutoscalingPolicyRPS, MaxScale: 2, Metric: "rps", Target: 1, StableWindowSeconds: 60},
+		"overridden": {Policy: common.AutoscalingPolicyTrace, MaxScale: 50, Metric: "rps", Target: 5,
+			StableWindowSeconds: 60},
+		"static":        {Policy: common.AutoscalingPolicyStatic},
+		"scale-to-zero": {Policy: common.AutoscalingPolicyStatic, MinScaleSet: true},
+	}
+
+	for _, function := range functions {
+		if function.Autoscaling == nil || *function.Autoscaling != expected[function.Name] {
+			t.Errorf("Unexpected autoscaling settings of %s: %+v", function.Name, function.Autoscaling)
+		}
+	}
+}
+
+func TestApplyAutoscalingPoliciesDisabled(t *testing.T) {
+	function := &common.Function{Name: "function"}
+	ApplyAutoscalingPolicies([]*common.Function{function}, nil)
+
+	if function.Autoscaling != nil {
+		t.Error("Autoscaling settings should only be computed with an autoscaling configuration.")
+	}
+}
@@ -76,12 +76,17 @@ func (m *FaasRequest) GetMemoryInMebiBytes() uint32 {
 }
 
 type FaasReply struct {
-	Message              string   `protobuf:"bytes,1,opt,name=message,proto3" json:"message,omitempty"`
-	DurationInMicroSec   uint32   `protobuf:"varint,2,opt,name=durationInMicroSec,proto3" json:"durationInMicroSec,omitempty"`
-	MemoryUsageInKb      uint32   `protobuf:"varint,3,opt,name=memoryUsageInKb,proto3" json:"memoryUsageInKb,omitempty"`
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
+	Message                    string   `protobuf:"bytes,1,opt,name=message,proto3" json:"message,omitempty"`
+	DurationInMicroSec         uint32   `protobuf:"varint,2,opt,name=durationInMicroSec,proto3" json:"durationInMicroSec,omitempty"`
+	MemoryUsageInKb            uint32   `protobuf:"varint,3,opt,name=memoryUsageInKb,proto3" json:"memoryUsageInKb,omitempty"`
+	ReceiveTimestampInMicroSec int64    `protobuf:"varint,4,opt,name=receiveTimestampInMicroSec,proto3" json:"receiveTimestampInMicroSec,omitempty"`
+	StartTimestampInMicroSec   int64    `protobuf:"varint,5,opt,name=startTimestampInMicroSec,proto3" json:"startTimestampInMicroSec,omitempty"`
+	EndTimestampInMicroSec     int64    `protobuf:"varint,6,opt,name=endTimestampInMicroSec,proto3" json:"endTimestampInMicroSec,omitempty"`
+	ColdStart                  bool     `protobuf:"varint,7,opt,name=coldStart,proto3" json:"coldStart,omitempty"`
+	InstanceUptimeInMicroSec   int64    `protobuf:"varint,8,opt,name=instanceUptimeInMicroSec,proto3" json:"instanceUptimeInMicroSec,omitempty"`
+	XXX_NoUnkeyedLiteral       struct{} `json:"-"`
+	XXX_unrecognized           []byte   `json:"-"`
+	XXX_sizecache              int32    `json:"-"`
 }
 
 func (m *FaasReply) Reset()         { *m = FaasReply{} }
@@ -130,6 +135,41 @@ func (m *FaasReply) GetMemoryUsageInKb() uint32 {
 	return 0
 }
 
+func (m *FaasReply) GetReceiveTimestampInMicroSec() int64 {
+	if m != nil {
+		return m.ReceiveTimestampInMicroSec
+	}
+	return 0
+}
+
+func (m *FaasReply) GetStartTimestampInMicroSec() int64 {
+	if m != nil {
+		return m.StartTimestampInMicroSec
+	}
+	return 0
+}
+
+func (m *FaasReply) GetEndTimestampInMicroSec() int64 {
+	if m != nil {
+		return m.EndTimestampInMicroSec
+	}
+	return 0
+}
+
+func (m *FaasReply) GetColdStart() bool {
+	if m != nil {
+		return m.ColdStart
+	}
+	return false
+}
+
+func (m *FaasReply) GetInstanceUptimeInMicroSec() int64 {
+	if m != nil {
+		return m.InstanceUptimeInMicroSec
+	}
+	return 0
+}
+
 func init() {
 	proto.RegisterType((*FaasRequest)(nil), "faas.FaasRequest")
 	proto.RegisterType((*FaasReply)(nil), "faas.FaasReply")
@@ -138,21 +178,27 @@ func init() {
 func init() { proto.RegisterFile("server/faas.proto", fileDescriptor_4886c8193ee7bbe7) }
 
 var fileDescriptor_4886c8193ee7bbe7 = []byte{
-	// 256 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x7c, 0x90, 0xbf, 0x4b, 0xc3, 0x40,
-	0x14, 0xc7, 0x8d, 0x8a, 0xb5, 0x4f, 0xa4, 0xe4, 0xa6, 0xe0, 0x54, 0xe3, 0x92, 0x41, 0x73, 0xa0,
-	0xa3, 0x5b, 0x41, 0x21, 0x48, 0x97, 0x88, 0x8b, 0xdb, 0x25, 0x7d, 0xb6, 0x07, 0xf7, 0x23, 0xbe,
-	0xbb, 0x13, 0x33, 0x39, 0xf8, 0x8f, 0x4b, 0x7a, 0x8a, 0xc5, 0x4a, 0xb7, 0x77, 0xef, 0xfb, 0x8e,
-	0xef, 0x87, 0x0f, 0xa4, 0x0e, 0xe9, 0x0d, 0x89, 0xbf, 0x08, 0xe1, 0xca, 0x8e, 0xac, 0xb7, 0xec,
-	0x70, 0x98, 0xf3, 0xcf, 0x04, 0x4e, 0xee, 0x85, 0x70, 0x35, 0xbe, 0x06, 0x74, 0x9e, 0x65, 0x30,
-	0xd2, 0xe8, 0x9c, 0x58, 0x62, 0x96, 0x4c, 0x93, 0x62, 0x5c, 0xff, 0x3c, 0xd9, 0x25, 0xa4, 0x14,
-	0x8c, 0x97, 0x1a, 0x2b, 0x33, 0x97, 0x4a, 0xc9, 0x47, 0x6c, 0xb3, 0xfd, 0x69, 0x52, 0x9c, 0xd6,
-	0xdb, 0xc1, 0x70, 0xad, 0x51, 0x5b, 0xea, 0x2b, 0x33, 0xc7, 0x46, 0xce, 0x7a, 0x8f, 0x2e, 0x3b,
-	0x88, 0xd7, 0x5b, 0x41, 0xfe, 0x01, 0xe3, 0x08, 0xd1, 0xa9, 0x7e, 0x07, 0x42, 0x09, 0x6c, 0x11,
-	0x48, 0x78, 0x69, 0xcd, 0x50, 0xd5, 0x92, 0xfd, 0x65, 0xf8, 0x27, 0x61, 0x05, 0x4c, 0x62, 0xd7,
-	0xd3, 0xf0, 0xbd, 0x32, 0x0f, 0xcd, 0x37, 0xc2, 0xdf, 0xf5, 0xf5, 0x2d, 0x1c, 0xdf, 0xbd, 0x63,
-	0x1b, 0xbc, 0x25, 0xc6, 0x61, 0x14, 0x67, 0x64, 0x69, 0xb9, 0x16, 0xb6, 0x21, 0xe8, 0x6c, 0xb2,
-	0xb9, 0xea, 0x54, 0x9f, 0xef, 0xcd, 0x2e, 0x9e, 0xcf, 0x97, 0xd2, 0xaf, 0x42, 0x53, 0xb6, 0x56,
-	0x73, 0xf4, 0xab, 0x2b, 0x14, 0x4e, 0x71, 0x65, 0xc5, 0x02, 0x89, 0x47, 0xf3, 0xcd, 0xd1, 0xda,
-	0xfa, 0xcd, 0x57, 0x00, 0x00, 0x00, 0xff, 0xff, 0x29, 0xf8, 0x44, 0xf1, 0x8a, 0x01, 0x00, 0x00,
+	// 342 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x7c, 0x92, 0xcd, 0x4b, 0xf3, 0x40,
+	0x10, 0xc6, 0xdf, 0xbc, 0xad, 0xfd, 0x18, 0x91, 0xd2, 0x3d, 0x48, 0x28, 0x1e, 0x62, 0xbd, 0xe4,
+	0xa0, 0x09, 0x28, 0x78, 0x50, 0xf0, 0x50, 0x50, 0x28, 0xd2, 0x4b, 0x6a, 0x2f, 0xde, 0x36, 0xc9,
+	0xd8, 0x2e, 0xec, 0x47, 0xdc, 0xdd, 0x14, 0x73, 0xf6, 0xec, 0xff, 0x2c, 0x49, 0x5a, 0x5a, 0xec,
+	0xc7, 0x6d, 0x76, 0x9e, 0x79, 0x66, 0x7e, 0xb0, 0x0f, 0xf4, 0x0d, 0xea, 0x25, 0xea, 0xf0, 0x83,
+	0x52, 0x13, 0x64, 0x5a, 0x59, 0x45, 0x9a, 0x65, 0x3d, 0xfc, 0x76, 0xe0, 0xf4, 0x85, 0x52, 0x13,
+	0xe1, 0x67, 0x8e, 0xc6, 0x12, 0x17, 0xda, 0x02, 0x8d, 0xa1, 0x73, 0x74, 0x1d, 0xcf, 0xf1, 0xbb,
+	0xd1, 0xfa, 0x49, 0xae, 0xa1, 0xaf, 0x73, 0x69, 0x99, 0xc0, 0xb1, 0x9c, 0x30, 0xce, 0xd9, 0x14,
+	0x13, 0xf7, 0xbf, 0xe7, 0xf8, 0x67, 0xd1, 0xae, 0x50, 0x4e, 0x0b, 0x14, 0x4a, 0x17, 0x63, 0x39,
+	0xc1, 0x98, 0x8d, 0x0a, 0x8b, 0xc6, 0x6d, 0xd4, 0xd3, 0x3b, 0xc2, 0xf0, 0xa7, 0x01, 0xdd, 0x9a,
+	0x22, 0xe3, 0xc5, 0x11, 0x86, 0x00, 0x48, 0x9a, 0x6b, 0x6a, 0x99, 0x92, 0xe5, 0xad, 0x44, 0xab,
+	0x0d, 0xc4, 0x1e, 0x85, 0xf8, 0xd0, 0xab, 0x8f, 0xcd, 0x4a, 0xfb, 0x58, 0xbe, 0xc6, 0x2b, 0x86,
+	0xbf, 0x6d, 0xf2, 0x04, 0x03, 0x8d, 0x09, 0xb2, 0x25, 0xbe, 0x31, 0x81, 0xc6, 0x52, 0x91, 0x6d,
+	0x5d, 0x68, 0x7a, 0x8e, 0xdf, 0x88, 0x8e, 0x4c, 0x90, 0x07, 0x70, 0x8d, 0xa5, 0xda, 0xee, 0x73,
+	0x9f, 0x54, 0xee, 0x83, 0x3a, 0xb9, 0x87, 0x73, 0x94, 0xe9, 0x3e, 0x67, 0xab, 0x72, 0x1e, 0x50,
+	0xc9, 0x05, 0x74, 0x13, 0xc5, 0xd3, 0x69, 0xb9, 0xd7, 0x6d, 0x7b, 0x8e, 0xdf, 0x89, 0x36, 0x8d,
+	0x92, 0x88, 0x49, 0x63, 0xa9, 0x4c, 0x70, 0x96, 0xad, 0x7f, 0x67, 0xb5, 0xb7, 0x53, 0x13, 0x1d,
+	0xd2, 0x6f, 0x1f, 0xa1, 0xf3, 0xfc, 0x85, 0x49, 0x6e, 0x95, 0x26, 0x21, 0xb4, 0xeb, 0x1a, 0x49,
+	0x3f, 0xa8, 0xf2, 0xb3, 0x95, 0x97, 0x41, 0x6f, 0xbb, 0x95, 0xf1, 0x62, 0xf8, 0x6f, 0x74, 0xf5,
+	0x7e, 0x39, 0x67, 0x76, 0x91, 0xc7, 0x41, 0xa2, 0x44, 0x88, 0x76, 0x71, 0x83, 0xd4, 0xf0, 0x90,
+	0x2b, 0x9a, 0xa2, 0x0e, 0xeb, 0x20, 0xc6, 0xad, 0x2a, 0x84, 0x77, 0xbf, 0x03, 0x00, 0xbe, 0x49,
+	0xad, 0x8d, 0x99, 0x02, 0x00, 0x00,
 }
@@ -0,0 +1,142 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2023 EASL and the vHive community
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package standard
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	util "github.com/vhive-serverless/loader/pkg/common"
+	"github.com/vhive-serverless/loader/pkg/workload/proto"
+)
+
+// StartHTTPServer serves the function over plain HTTP for platforms whose gateways cannot proxy gRPC (e.g., OpenFaaS).
+// Requests and replies are JSON objects with the same fields as FaasRequest and FaasReply.
+func StartHTTPServer(serverAddress string, serverPort int, functionType FunctionType) {
+	readEnvironmentalVariables()
+	serverSideCode = functionType
+
+	server := &http.Server{
+		Addr:    fmt.Sprintf("%s:%d", serverAddress, serverPort),
+		Handler: newHTTPHandler(),
+	}
+
+	sigc := make(chan os.Signal, 1)
+	signal.Notify(sigc, syscall.SIGTERM)
+
+	go func() {
+		<-sigc
+		log.Info("Received SIGTERM, shutting down gracefully...")
+		_ = server.Shutdown(context.Background())
+	}()
+
+	err := server.ListenAndServe()
+	if !errors.Is(err, http.ErrServerClosed) {
+		util.Check(err)
+	}
+}
+
+func newHTTPHandler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", handleHTTPInvocation)
+	// Fission specializes generic pool pods with the function package before routing requests to them. The trace
+	// function is baked into the image, so specialization is acknowledged without counting as an invocation.
+	mux.HandleFunc("/specialize", handleSpecialization)
+	mux.HandleFunc("/v2/specialize", handleSpecialization)
+
+	return mux
+}
+
+func handleSpecialization(w http.ResponseWriter, _ *http.Request) {
+	w.WriteHeader(http.StatusOK)
+}
+
+func handleHTTPInvocation(w http.ResponseWriter, r *http.Request) {
+	received := time.Now()
+
+	invocationID := r.Header.Get(util.InvocationIDHeader)
+	functionName := r.Header.Get(util.FunctionNameHeader)
+
+	var req proto.FaasRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, fmt.Sprintf("Invalid request - %v", err), http.StatusBadRequest)
+		return
+	}
+
+	reply := execute(&req, received)
+	util.InvocationLogEntry(invocationID, functionName).
+		Infof("Invocation served in %d[us] (cold start: %t)", reply.DurationInMicroSec, reply.ColdStart)
+
+	if callbackURL := r.Header.Get(util.AsyncCallbackURLHeader); callbackURL != "" {
+		go sendCallback(callbackURL, invocationID, functionName, reply, util.InvocationLogEntry(invocationID, functionName))
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set(util.InvocationIDHeader, invocationID)
+	w.Header().Set(util.FunctionNameHeader, functionName)
+
+	_ = json.NewEncoder(w).Encode(httpReply(reply))
+}
+
+// httpReply returns the fields of the reply as a JSON object, which is the body of HTTP replies and the payload of
+// callbacks.
+func httpReply(reply *proto.FaasReply) map[string]any {
+	return map[string]any{
+		"Message":            reply.Message,
+		"DurationInMicroSec": reply.DurationInMicroSec,
+		"MemoryUsageInKb":    reply.MemoryUsageInKb,
+
+		"ReceiveTimestampInMicroSec": reply.ReceiveTimestampInMicroSec,
+		"StartTimestampInMicroSec":   reply.StartTimestampInMicroSec,
+		"EndTimestampInMicroSec":     reply.EndTimestampInMicroSec,
+		"ColdStart":                  reply.ColdStart,
+		"InstanceUptimeInMicroSec":   reply.InstanceUptimeInMicroSec,
+		"InstanceID":                 instance.ID(),
+	}
+}
+
+// sendCallback POSTs the reply of an asynchronous invocation to the callback receiver of the loader, which matches it
+// to the invocation by its ID and the name of the function.
+func sendCallback(callbackURL string, invocationID string, functionName string, reply *proto.FaasReply, invocationLog *log.Entry) {
+	payload, err := json.Marshal(httpReply(reply))
+	if err == nil {
+		err = util.PostAsyncCallback(callbackURL, &util.AsyncCallback{
+			InvocationID: invocationID,
+			Function:     functionName,
+			Payload:      payload,
+		})
+	}
+	if err != nil {
+		invocationLog.Warnf("Failed to send the callback to %s - %v", callbackURL, err)
+	}
+}
@@ -46,6 +46,7 @@ import (
 var hostname string
 var IterationsMultiplier int
 var serverSideCode FunctionType
+var instance = util.NewFunctionInstance()
 
 type FunctionType int
 
@@ -58,8 +59,25 @@ type funcServer struct {
 	proto.UnimplementedExecutorServer
 }
 
-func (s *funcServer) Execute(_ context.Context, req *proto.FaasRequest) (*proto.FaasReply, error) {
+func (s *funcServer) Execute(ctx context.Context, req *proto.FaasRequest) (*proto.FaasReply, error) {
+	received := time.Now()
+	invocationLog := util.EchoInvocationMetadata(ctx)
+
+	reply := execute(req, received)
+	invocationLog.Infof("Invocation served in %d[us] (cold start: %t)", reply.DurationInMicroSec, reply.ColdStart)
+
+	if invocationID, functionName, callbackURL := util.IncomingInvocationMetadata(ctx); callbackURL != "" {
+		go sendCallback(callbackURL, invocationID, functionName, reply, invocationLog)
+	}
+
+	return reply, nil
+}
+
+// execute runs the function body and reports the function-side timestamps, regardless of the serving protocol.
+func execute(req *proto.FaasRequest, received time.Time) *proto.FaasReply {
 	var msg string
+	coldStart, uptime := instance.RegisterRequest(received)
+
 	start := time.Now()
 
 	if serverSideCode == TraceFunction {
@@ -80,12 +98,19 @@ func (s *funcServer) Execute(_ context.Context, req *proto.FaasRequest) (*proto.
 	} else {
 		msg = fmt.Sprintf("OK - EMPTY - %s", hostname)
 	}
+	end := time.Now()
 
 	return &proto.FaasReply{
 		Message:            msg,
-		DurationInMicroSec: uint32(time.Since(start).Microseconds()),
+		DurationInMicroSec: uint32(end.Sub(start).Microseconds()),
 		MemoryUsageInKb:    req.MemoryInMebiBytes * 1024,
-	}, nil
+
+		ReceiveTimestampInMicroSec: received.UnixMicro(),
+		StartTimestampInMicroSec:   start.UnixMicro(),
+		EndTimestampInMicroSec:     end.UnixMicro(),
+		ColdStart:                  coldStart,
+		InstanceUptimeInMicroSec:   uptime,
+	}
 }
 
 func readEnvironmentalVariables() {
@@ -49,6 +49,8 @@ type funcServer struct {
 	proto.UnimplementedExecutorServer
 }
 
+var instance = util.NewFunctionInstance()
+
 func busySpin(timeoutSem <-chan time.Time) {
 	/** `for { }` generates the assembly `jmp self`, which is a spin lock. */
 	for {
@@ -62,6 +64,10 @@ func busySpin(timeoutSem <-chan time.Time) {
 }
 
 func (s *funcServer) Execute(ctx context.Context, req *proto.FaasRequest) (*proto.FaasReply, error) {
+	received := time.Now()
+	coldStart, uptime := instance.RegisterRequest(received)
+	invocationLog := util.EchoInvocationMetadata(ctx)
+
 	start := time.Now()
 	runtimeRequested := req.RuntimeInMilliSec
 	timeoutSem := time.After(time.Duration(runtimeRequested) * time.Millisecond)
@@ -94,10 +100,19 @@ finish:
 		msg = "Timeout when materialising allocated memory."
 	}
 
+	end := time.Now()
+	invocationLog.Infof("Invocation served in %d[us] (cold start: %t)", end.Sub(start).Microseconds(), coldStart)
+
 	return &proto.FaasReply{
 		Message:            msg,
-		DurationInMicroSec: uint32(time.Since(start).Microseconds()),
+		DurationInMicroSec: uint32(end.Sub(start).Microseconds()),
 		MemoryUsageInKb:    util.B2Kib(numPagesRequested * uint32(unix.Getpagesize())),
+
+		ReceiveTimestampInMicroSec: received.UnixMicro(),
+		StartTimestampInMicroSec:   start.UnixMicro(),
+		EndTimestampInMicroSec:     end.UnixMicro(),
+		ColdStart:                  coldStart,
+		InstanceUptimeInMicroSec:   uptime,
 	}, nil
 }
 
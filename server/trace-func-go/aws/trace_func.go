@@ -41,10 +41,20 @@ import (
 // https://serverless.com/framework/docs/providers/aws/events/apigateway/#lambda-proxy-integration
 type Response events.APIGatewayProxyResponse
 
+// instance lives as long as the Lambda execution environment, so the first request it serves is a cold start
+var instance = common.NewFunctionInstance()
+
 // Handler is our lambda handler invoked by the `lambda.Start` function call
 func Handler(_ context.Context, event events.LambdaFunctionURLRequest) (Response, error) {
 	var buf bytes.Buffer
 
+	received := time.Now()
+	coldStart, uptime := instance.RegisterRequest(received)
+
+	// Function URL requests carry lowercase header names
+	invocationID := event.Headers[common.InvocationIDHeader]
+	functionName := event.Headers[common.FunctionNameHeader]
+
 	start := time.Now()
 
 	// Obtain payload from the request
@@ -60,16 +70,38 @@ func Handler(_ context.Context, event events.LambdaFunctionURLRequest) (Response
 
 	standard.IterationsMultiplier = 102 // Cloudlab xl170 benchmark @ 1 second function execution time
 	_ = common.TraceFunctionExecution(start, 102, req.RuntimeInMilliSec)
+	end := time.Now()
+	common.InvocationLogEntry(invocationID, functionName).
+		Infof("Invocation served in %d[us] (cold start: %t)", end.Sub(start).Microseconds(), coldStart)
 
 	body, err := json.Marshal(map[string]any{
-		"DurationInMicroSec": uint32(time.Since(start).Microseconds()),
+		"DurationInMicroSec": uint32(end.Sub(start).Microseconds()),
 		"MemoryUsageInKb":    req.MemoryInMebiBytes * 1024,
+
+		"ReceiveTimestampInMicroSec": received.UnixMicro(),
+		"StartTimestampInMicroSec":   start.UnixMicro(),
+		"EndTimestampInMicroSec":     end.UnixMicro(),
+		"ColdStart":                  coldStart,
+		"InstanceUptimeInMicroSec":   uptime,
+		"InstanceID":                 instance.ID(),
 	})
 	if err != nil {
 		return Response{StatusCode: 400}, err
 	}
 	json.HTMLEscape(&buf, body)
 
+	// Event invocations are reported to the callback receiver of the loader, if enabled
+	if callbackURL := event.Headers[common.AsyncCallbackURLHeader]; callbackURL != "" {
+		err = common.PostAsyncCallback(callbackURL, &common.AsyncCallback{
+			InvocationID: invocationID,
+			Function:     functionName,
+			Payload:      body,
+		})
+		if err != nil {
+			common.InvocationLogEntry(invocationID, functionName).Warnf("Failed to send the callback - %v", err)
+		}
+	}
+
 	resp := Response{
 		StatusCode:      200,
 		IsBase64Encoded: false,
@@ -77,6 +109,9 @@ func Handler(_ context.Context, event events.LambdaFunctionURLRequest) (Response
 		Headers: map[string]string{
 			"Content-Type":           "application/json",
 			"X-MyCompany-Func-Reply": "trace_func_go handler",
+
+			common.InvocationIDHeader: invocationID,
+			common.FunctionNameHeader: functionName,
 		},
 	}
 
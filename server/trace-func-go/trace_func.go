@@ -62,5 +62,11 @@ func main() {
 		log.Infof("Function type: EMPTY\n")
 	}
 
-	standard.StartGRPCServer("", serverPort, functionType, *zipkin)
+	// Gateways that only proxy HTTP (e.g., OpenFaaS) require FUNC_PROTOCOL_ENV=http
+	if os.Getenv("FUNC_PROTOCOL_ENV") == "http" {
+		log.Infof("Protocol: HTTP\n")
+		standard.StartHTTPServer("", serverPort, functionType)
+	} else {
+		standard.StartGRPCServer("", serverPort, functionType, *zipkin)
+	}
 }
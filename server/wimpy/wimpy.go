@@ -43,7 +43,13 @@ type funcServer struct {
 	proto.UnimplementedExecutorServer
 }
 
+var instance = util.NewFunctionInstance()
+
 func (s *funcServer) Execute(ctx context.Context, req *proto.FaasRequest) (*proto.FaasReply, error) {
+	received := time.Now()
+	coldStart, uptime := instance.RegisterRequest(received)
+	invocationLog := util.EchoInvocationMetadata(ctx)
+
 	start := time.Now()
 	runtimeRequested := req.RuntimeInMilliSec
 	timeoutSem := time.After(time.Duration(runtimeRequested) * time.Millisecond)
@@ -65,10 +71,19 @@ func (s *funcServer) Execute(ctx context.Context, req *proto.FaasRequest) (*prot
 	util.Check(err)
 
 	<-timeoutSem //* Blocking wait.
+	end := time.Now()
+	invocationLog.Infof("Invocation served in %d[us] (cold start: %t)", end.Sub(start).Microseconds(), coldStart)
+
 	return &proto.FaasReply{
 		Message:            "Wimpy func -- DONE", // Unused
-		DurationInMicroSec: uint32(time.Since(start).Microseconds()),
+		DurationInMicroSec: uint32(end.Sub(start).Microseconds()),
 		MemoryUsageInKb:    util.B2Kib(numPagesRequested * uint32(pageSize)),
+
+		ReceiveTimestampInMicroSec: received.UnixMicro(),
+		StartTimestampInMicroSec:   start.UnixMicro(),
+		EndTimestampInMicroSec:     end.UnixMicro(),
+		ColdStart:                  coldStart,
+		InstanceUptimeInMicroSec:   uptime,
 	}, nil
 }
 
@@ -0,0 +1,77 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2023 EASL and the vHive community
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package main
+
+import (
+	"flag"
+	"os"
+	"time"
+
+	"github.com/gocarina/gocsv"
+	log "github.com/sirupsen/logrus"
+	"github.com/vhive-serverless/loader/pkg/metric"
+)
+
+func main() {
+	var (
+		durationsPath = flag.String("durations", "data/out/experiment_duration_30.csv", "Path to the duration output of the experiment")
+		format        = flag.String("format", "csv", "Format of the duration output, csv or jsonl")
+		compression   = flag.String("compression", "", "Compression of the duration output, gzip or zstd")
+		failuresPath  = flag.String("failures", "data/out/experiment_failures_30.csv", "Path to the failures CSV of the experiment")
+		outputPrefix  = flag.String("o", "data/out/experiment_failure_impact", "Prefix of the JSON and CSV reports")
+		window        = flag.Duration("window", 10*time.Second, "Granularity at which invocations are compared to their baseline")
+	)
+	flag.Parse()
+
+	records, err := metric.ReadExecutionRecords(*durationsPath, *format, *compression)
+	if err != nil {
+		log.Fatalf("Failed to read %s - %v", *durationsPath, err)
+	}
+
+	var failures []*metric.FailureRecord
+	readCSV(*failuresPath, &failures)
+
+	report, err := metric.AnalyzeFailureImpact(records, failures, *window)
+	if err != nil {
+		log.Fatalf("Failed to analyze the impact of the failures - %v", err)
+	}
+	if err = metric.WriteFailureImpactReport(report, *outputPrefix+".json", *outputPrefix+".csv"); err != nil {
+		log.Fatalf("Failed to write the failure impact report - %v", err)
+	}
+
+	log.Infof("Analyzed the impact of %d failures on %d invocations", len(report.Failures), len(records))
+}
+
+func readCSV(path string, out any) {
+	file, err := os.Open(path)
+	if err != nil {
+		log.Fatalf("Failed to open %s - %v", path, err)
+	}
+	defer file.Close()
+
+	if err = gocsv.UnmarshalFile(file, out); err != nil {
+		log.Fatalf("Failed to read %s - %v", path, err)
+	}
+}